@@ -0,0 +1,444 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// The agent holds the unlocked keys of banks in memory, and signs and derives file keys for the commands of the CLI,
+// so that banks are unlocked once instead of at each command. It listens on a Unix socket only reachable by its user.
+
+// AgentSocketEnv overrides the default path of the agent socket, <home>/agent.sock
+const AgentSocketEnv = "FILEBANKD_AGENT_SOCK"
+
+func AgentSocketPath(bankhome string) string {
+	if path := os.Getenv(AgentSocketEnv); path != "" {
+		return path
+	}
+	return bankhome + "/agent.sock"
+}
+
+type fileBankAgent struct {
+	pb.FileBankAgentServer
+	// keys are forgotten after timeout when added without a timeout, never when 0
+	timeout time.Duration
+	mu      sync.Mutex
+	banks   map[string]*agentBank
+}
+
+type agentBank struct {
+	name    string
+	keys    *localBankKeys
+	expires time.Time
+	timer   *time.Timer
+}
+
+// RunAgent serves the agent on a Unix socket until it is interrupted, then forgets all keys
+func RunAgent(socketPath string, timeout time.Duration) error {
+	listener, err := listenAgentSocket(socketPath)
+	if err != nil {
+		return err
+	}
+	agent := &fileBankAgent{timeout: timeout, banks: make(map[string]*agentBank)}
+	server := grpc.NewServer()
+	pb.RegisterFileBankAgentServer(server, agent)
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupted
+		server.Stop()
+	}()
+
+	log.Printf("Agent listening on %v", socketPath)
+	err = server.Serve(listener)
+	agent.removeAll()
+	return err
+}
+
+// listens on a socket only accessible to the user, replacing the socket of an agent that is no longer running
+func listenAgentSocket(socketPath string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return nil, errors.New(fmt.Sprintf("An agent is already listening on %v", socketPath))
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return listenPrivateSocket(socketPath)
+}
+
+func (a *fileBankAgent) AddBank(ctx context.Context, req *pb.AgentAddBankRequest) (*pb.AgentBank, error) {
+	if len(req.PrivKeySeed) != ed25519.SeedSize {
+		return nil, errors.New("Invalid private key")
+	}
+	suite := cr.CipherSuite(req.CipherSuite)
+	if err := suite.Validate(); err != nil {
+		return nil, err
+	}
+	keys := &localBankKeys{
+		privKey:  ed25519.NewKeyFromSeed(req.PrivKeySeed),
+		fileKeys: &fileKeys{suite: suite},
+	}
+	switch req.Version {
+	case pb.DescriptorVersion_DESCRIPTOR_V1:
+		keys.passphrase = req.Passphrase
+	case pb.DescriptorVersion_DESCRIPTOR_V2:
+		if len(req.MasterKey) == 0 {
+			return nil, errors.New("Bank has no master key")
+		}
+		keys.masterKey = req.MasterKey
+	default:
+		return nil, errors.New(fmt.Sprintf("Unknown bank descriptor version %v", req.Version))
+	}
+	clear(req.PrivKeySeed)
+
+	timeout := a.timeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+	id := string(req.BankId)
+	bank := &agentBank{name: req.Name, keys: keys}
+	if timeout > 0 {
+		bank.expires = time.Now().Add(timeout)
+		bank.timer = time.AfterFunc(timeout, func() {
+			a.remove(id, bank)
+		})
+	}
+
+	a.mu.Lock()
+	if previous, ok := a.banks[id]; ok {
+		previous.forget()
+	}
+	a.banks[id] = bank
+	a.mu.Unlock()
+	return bank.toProto(id), nil
+}
+
+func (a *fileBankAgent) RemoveBank(ctx context.Context, req *pb.AgentBankRequest) (*pb.AgentRemoveBankResponse, error) {
+	err := a.withBank(req.BankId, func(bank *agentBank) error {
+		delete(a.banks, string(req.BankId))
+		bank.forget()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AgentRemoveBankResponse{}, nil
+}
+
+func (a *fileBankAgent) GetBank(ctx context.Context, req *pb.AgentBankRequest) (*pb.AgentBank, error) {
+	var resp *pb.AgentBank
+	err := a.withBank(req.BankId, func(bank *agentBank) error {
+		resp = bank.toProto(string(req.BankId))
+		return nil
+	})
+	return resp, err
+}
+
+func (a *fileBankAgent) ListBanks(ctx context.Context, req *pb.AgentListBanksRequest) (*pb.AgentListBanksResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	resp := &pb.AgentListBanksResponse{}
+	for id, bank := range a.banks {
+		resp.Banks = append(resp.Banks, bank.toProto(id))
+	}
+	sort.Slice(resp.Banks, func(i, j int) bool { return resp.Banks[i].Name < resp.Banks[j].Name })
+	return resp, nil
+}
+
+func (a *fileBankAgent) Sign(ctx context.Context, req *pb.AgentSignRequest) (*pb.AgentSignResponse, error) {
+	resp := &pb.AgentSignResponse{}
+	err := a.withBank(req.BankId, func(bank *agentBank) error {
+		resp.Signature = ed25519.Sign(bank.keys.privKey, req.Message)
+		return nil
+	})
+	return resp, err
+}
+
+func (a *fileBankAgent) FileKey(ctx context.Context, req *pb.AgentFileKeyRequest) (*pb.AgentFileKeyResponse, error) {
+	resp := &pb.AgentFileKeyResponse{}
+	err := a.withBank(req.BankId, func(bank *agentBank) error {
+		var err error
+		resp.Key, err = bank.keys.fileKey(req.Kdf, req.Salt, req.WrappedKey)
+		return err
+	})
+	return resp, err
+}
+
+func (a *fileBankAgent) NewFileKey(ctx context.Context, req *pb.AgentNewFileKeyRequest) (*pb.AgentFileKeyResponse, error) {
+	resp := &pb.AgentFileKeyResponse{}
+	err := a.withBank(req.BankId, func(bank *agentBank) error {
+		var err error
+		resp.Key, resp.Salt, err = bank.keys.newFileKey(req.Kdf)
+		return err
+	})
+	return resp, err
+}
+
+// runs f on a bank while holding the lock, so that its keys are not forgotten meanwhile
+func (a *fileBankAgent) withBank(id []byte, f func(*agentBank) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	bank, ok := a.banks[string(id)]
+	if !ok {
+		return errors.New("Bank is not held by the agent")
+	}
+	return f(bank)
+}
+
+// removes a bank if it was not replaced since
+func (a *fileBankAgent) remove(id string, bank *agentBank) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.banks[id] == bank {
+		delete(a.banks, id)
+		bank.forget()
+	}
+}
+
+func (a *fileBankAgent) removeAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, bank := range a.banks {
+		delete(a.banks, id)
+		bank.forget()
+	}
+}
+
+func (b *agentBank) forget() {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.keys.close()
+}
+
+func (b *agentBank) toProto(id string) *pb.AgentBank {
+	var expires int64
+	if !b.expires.IsZero() {
+		expires = b.expires.Unix()
+	}
+	return &pb.AgentBank{
+		BankId:  []byte(id),
+		Name:    b.name,
+		PubKey:  b.keys.publicKey(),
+		Expires: expires,
+	}
+}
+
+// keys of a bank held by the agent
+type agentBankKeys struct {
+	conn   *grpc.ClientConn
+	agent  pb.FileBankAgentClient
+	bankId []byte
+	pubKey ed25519.PublicKey
+	suite  cr.CipherSuite
+}
+
+// banks are identified in the agent by the hash of their encrypted private key
+func agentBankId(bank *pb.ClientBankDescriptor) []byte {
+	id := cr.HashOnce(bank.PrivKey)
+	return id[:]
+}
+
+func connectToAgent(bankhome string) (*grpc.ClientConn, pb.FileBankAgentClient, error) {
+	socketPath := AgentSocketPath(bankhome)
+	if _, err := os.Stat(socketPath); err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("No agent is listening on %v", socketPath))
+	}
+	conn, err := grpc.Dial("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, pb.NewFileBankAgentClient(conn), nil
+}
+
+func agentContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 5*time.Second)
+}
+
+// returns the keys of a bank held by the agent, nil when no agent is running or when it does not hold the bank
+func agentBankKeysIfHeld(bankhome string, bank *pb.ClientBankDescriptor) *agentBankKeys {
+	conn, agent, err := connectToAgent(bankhome)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := agentContext()
+	defer cancel()
+	bankId := agentBankId(bank)
+	held, err := agent.GetBank(ctx, &pb.AgentBankRequest{BankId: bankId})
+	if err != nil || len(held.PubKey) != ed25519.PublicKeySize {
+		conn.Close()
+		return nil
+	}
+	return &agentBankKeys{
+		conn:   conn,
+		agent:  agent,
+		bankId: bankId,
+		pubKey: held.PubKey,
+		suite:  cr.CipherSuite(bank.CipherSuite),
+	}
+}
+
+func (k *agentBankKeys) publicKey() ed25519.PublicKey {
+	return k.pubKey
+}
+
+func (k *agentBankKeys) sign(m proto.Message) ([]byte, error) {
+	message, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := agentContext()
+	defer cancel()
+	resp, err := k.agent.Sign(ctx, &pb.AgentSignRequest{BankId: k.bankId, Message: message})
+	if err != nil {
+		return nil, fmt.Errorf("Agent could not sign: %v", err)
+	}
+	return resp.Signature, nil
+}
+
+func (k *agentBankKeys) signer() ed25519.PublicKey {
+	return nil
+}
+
+func (k *agentBankKeys) fileKey(kdf *pb.KdfParams, salt, wrappedKey []byte) ([]byte, error) {
+	ctx, cancel := agentContext()
+	defer cancel()
+	resp, err := k.agent.FileKey(ctx, &pb.AgentFileKeyRequest{BankId: k.bankId, Kdf: kdf, Salt: salt, WrappedKey: wrappedKey})
+	if err != nil {
+		return nil, fmt.Errorf("Agent could not derive file key: %v", err)
+	}
+	return resp.Key, nil
+}
+
+func (k *agentBankKeys) newFileKey(kdf *pb.KdfParams) ([]byte, []byte, error) {
+	ctx, cancel := agentContext()
+	defer cancel()
+	resp, err := k.agent.NewFileKey(ctx, &pb.AgentNewFileKeyRequest{BankId: k.bankId, Kdf: kdf})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Agent could not generate file key: %v", err)
+	}
+	return resp.Key, resp.Salt, nil
+}
+
+func (k *agentBankKeys) cipherSuite() cr.CipherSuite {
+	return k.suite
+}
+
+func (k *agentBankKeys) close() {
+	k.conn.Close()
+}
+
+// CallAgentAddBank unlocks a bank with its password and hands its keys to the agent, which forgets them after timeout,
+// or after its default timeout when 0
+func CallAgentAddBank(bankhome, serverName, bankName string, timeout time.Duration) error {
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+	conn, agent, err := connectToAgent(bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	keys, err := unlockLocalBankKeys(bank, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+
+	ctx, cancel := agentContext()
+	defer cancel()
+	added, err := agent.AddBank(ctx, &pb.AgentAddBankRequest{
+		BankId:      agentBankId(bank),
+		Name:        serverName + ":" + bankName,
+		PrivKeySeed: keys.privKey.Seed(),
+		Version:     bank.Version,
+		CipherSuite: bank.CipherSuite,
+		MasterKey:   keys.masterKey,
+		Passphrase:  keys.passphrase,
+		Timeout:     int64(timeout / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	if added.Expires == 0 {
+		fmt.Printf("Keys of bank %s:%s added to the agent\n", serverName, bankName)
+	} else {
+		fmt.Printf("Keys of bank %s:%s added to the agent until %v\n", serverName, bankName, time.Unix(added.Expires, 0).Format(time.TimeOnly))
+	}
+	return nil
+}
+
+// CallAgentRemoveBank makes the agent forget the keys of a bank
+func CallAgentRemoveBank(bankhome, serverName, bankName string) error {
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	conn, agent, err := connectToAgent(bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := agentContext()
+	defer cancel()
+	if _, err := agent.RemoveBank(ctx, &pb.AgentBankRequest{BankId: agentBankId(bank)}); err != nil {
+		return err
+	}
+	fmt.Printf("Keys of bank %s:%s removed from the agent\n", serverName, bankName)
+	return nil
+}
+
+// CallAgentListBanks lists the banks held by the agent
+func CallAgentListBanks(bankhome string) error {
+	conn, agent, err := connectToAgent(bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := agentContext()
+	defer cancel()
+	resp, err := agent.ListBanks(ctx, &pb.AgentListBanksRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%30s %4s %20s\n===========================================================\n", "Bank", "", "Expires")
+	for _, bank := range resp.Banks {
+		expires := "never"
+		if bank.Expires != 0 {
+			expires = time.Unix(bank.Expires, 0).Format(time.DateTime)
+		}
+		fmt.Printf("%30s %4s %20s\n", bank.Name, "", expires)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package client
+
+import (
+	"net"
+	"os"
+)
+
+// without umask, the permissions of the socket are restricted once it is created
+func listenPrivateSocket(socketPath string) (net.Listener, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
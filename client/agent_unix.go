@@ -0,0 +1,16 @@
+//go:build unix
+
+package client
+
+import (
+	"net"
+	"syscall"
+)
+
+// creates the socket without permissions for others, so that it is never reachable by them
+func listenPrivateSocket(socketPath string) (net.Listener, error) {
+	oldMask := syscall.Umask(0077)
+	listener, err := net.Listen("unix", socketPath)
+	syscall.Umask(oldMask)
+	return listener, err
+}
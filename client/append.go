@@ -0,0 +1,269 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+func CallAppendFiles(bankhome, serverName, bankName string, filepaths []string) error {
+	if len(filepaths) == 0 {
+		return errors.New("Files list is empty")
+	}
+
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+	// import server pubkey
+	serverPubKey, err := cr.ImportPublicKey(server.PubKey)
+	if err != nil {
+		return err
+	}
+
+	// verify that bank exists
+	if bankExist, err := storage.Client_BankExists(bankhome, serverName, bankName); err != nil {
+		return err
+	} else if !bankExist {
+		return errors.New(fmt.Sprintf("Bank %v:%v does not exist", serverName, bankName))
+	}
+	bank, err := storage.Client_ReadBankDescriptor(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+	if !merkle.TreeMode(bank.TreeMode).IsIndexed() {
+		return errors.New("Only banks using indexed trees or mountain ranges can be extended")
+	}
+
+	// files are read when they are sent
+	names, err := storage.FileNamesFromPaths(filepaths)
+	if err != nil {
+		return err
+	}
+
+	// unlock bank keys with the agent or the bank password
+	keys, err := unlockBank(bankhome, bank)
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+
+	tree := merkle.MerkleTree{
+		Mode:      merkle.TreeMode(bank.TreeMode),
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(bank.ChunkSize),
+	}
+
+	conn, client, err := connectToNode(server.Host, bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background()) // no deadline for transfers, idle streams are cancelled by the connection
+	defer cancel()
+
+	stream, err := client.AppendFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp1, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return err
+	}
+
+	var serverNonce []byte
+	switch phase := resp1.Phase.(type) {
+	case *pb.AppendFilesResponse_Nonce:
+		serverNonce = phase.Nonce
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// sign request
+	messageToSign := &pb.SignAppendRequestClient{
+		Nonce:      serverNonce,
+		PubKeyAddr: bankPubKeyHashB58,
+		OldNbfiles: bank.Nbfiles,
+		Nbfiles:    int32(len(filepaths)),
+	}
+	sign, err := keys.sign(messageToSign)
+	if err != nil {
+		return err
+	}
+
+	// send request
+	req1 := &pb.AppendFilesRequest{
+		Phase: &pb.AppendFilesRequest_AppendReq{
+			AppendReq: &pb.AppendRequest{
+				Nonce:      serverNonce,
+				PubKeyAddr: bankPubKeyHashB58,
+				OldNbfiles: bank.Nbfiles,
+				Nbfiles:    int32(len(filepaths)),
+				Signature:  sign,
+			},
+		},
+	}
+	if err := stream.Send(req1); err != nil {
+		return err
+	}
+
+	// encrypt and send files as they are read, numbering continues after the files already in the bank
+	sendPart := func(part *pb.FileMessage) error {
+		return stream.Send(&pb.AppendFilesRequest{
+			Phase: &pb.AppendFilesRequest_File{
+				File: part,
+			},
+		})
+	}
+	fileDescriptors := []*pb.FileDescriptor{}
+	for i, path := range filepaths {
+		seq := bank.Nbfiles + int32(i+1)
+		aad := fileAAD(keyHash[:], bank.Version, seq, true)
+		descriptor, err := sendEncryptedFile(sendPart, tree, seq, func(w io.Writer) (*pb.FileDescriptor, error) {
+			return encryptFileFromPath(w, keys, path, bank.Compression, bank.Kdf, aad)
+		})
+		if err != nil {
+			return err
+		}
+		descriptor.Name = names[i]
+		fileDescriptors = append(fileDescriptors, descriptor)
+	}
+	// send Nonce
+	clientNonce, err := cr.Random12BytesNonce()
+	if err != nil {
+		return err
+	}
+	req3 := &pb.AppendFilesRequest{
+		Phase: &pb.AppendFilesRequest_Nonce{
+			Nonce: clientNonce,
+		},
+	}
+	if err := stream.Send(req3); err != nil {
+		return err
+	}
+	// receive signed response
+	resp2, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return err
+	}
+	var appendedRoot *pb.AppendedRoot
+	switch phase := resp2.Phase.(type) {
+	case *pb.AppendFilesResponse_AppendedRoot:
+		appendedRoot = phase.AppendedRoot
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// verify nonce
+	if !bytes.Equal(appendedRoot.Nonce, clientNonce) {
+		return errors.New("Invalid challenge response nonce")
+	}
+	// verify signature
+	if err := verifyAppendedRootSignature(appendedRoot, serverPubKey); err != nil {
+		return err
+	}
+
+	// verify new root before accepting it
+	if err := verifyAppendedRoot(appendedRoot, fileDescriptors, bank); err != nil {
+		return err
+	}
+
+	// update bank descriptor
+	bank.Nbfiles = appendedRoot.Nbfiles
+	bank.MerkleRoot = appendedRoot.MerkleRoot
+	bank.FileDescriptors = append(bank.FileDescriptors, fileDescriptors...)
+	if err := storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	refreshBankManifest(bankhome, server, bank, keys)
+	fmt.Printf("%v files have been succesfully appended to bank %s:%s\n", len(fileDescriptors), serverName, bankName)
+	return nil
+}
+
+func verifyAppendedRootSignature(resp *pb.AppendedRoot, pubKey ed25519.PublicKey) error {
+	signedMessage := &pb.SignAppendedRootServer{
+		Nonce:      resp.Nonce,
+		MerkleRoot: resp.MerkleRoot,
+		Nbfiles:    resp.Nbfiles,
+	}
+	return cr.VerifySignature(signedMessage, pubKey, resp.Signature)
+}
+
+func verifyAppendedRoot(appendedRoot *pb.AppendedRoot, fileDescriptors []*pb.FileDescriptor, bank *pb.ClientBankDescriptor) error {
+	if int(appendedRoot.Nbfiles) != int(bank.Nbfiles)+len(fileDescriptors) {
+		return errors.New(fmt.Sprintf("Server bank has %v files, expected %v", appendedRoot.Nbfiles, int(bank.Nbfiles)+len(fileDescriptors)))
+	}
+	if len(appendedRoot.MerkleRoot) != 32 {
+		return errors.New("Invalid merkle root format")
+	}
+	newRoot := [32]byte(appendedRoot.MerkleRoot)
+
+	// verify that the new tree extends the tree of the local merkle root
+	consistencyHashes, err := unlinearizeProof(appendedRoot.ConsistencyProof)
+	if err != nil {
+		return err
+	}
+	consistencyProof := merkle.MerkleConsistencyProof{
+		Hashes:  consistencyHashes,
+		OldSize: int(bank.Nbfiles),
+		NewSize: int(appendedRoot.Nbfiles),
+		Version: merkle.TreeVersion(bank.TreeVersion),
+		Hash:    cr.HashAlgorithm(bank.HashAlgorithm),
+	}
+	if !consistencyProof.VerifyConsistency([32]byte(bank.MerkleRoot), newRoot) {
+		return errors.New("Invalid consistency proof: new merkle root does not extend local bank")
+	}
+
+	// verify that the appended files are the last leafs of the new tree
+	filesHashes, err := unlinearizeProof(appendedRoot.FilesProof)
+	if err != nil {
+		return err
+	}
+	filesProof := merkle.MerkleMultiProof{
+		Hashes:    filesHashes,
+		Mode:      merkle.IndexedTree,
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(bank.ChunkSize),
+		TreeSize:  int(appendedRoot.Nbfiles),
+	}
+	var contentHashes [][32]byte
+	for i, fileDescriptor := range fileDescriptors {
+		filesProof.Indexes = append(filesProof.Indexes, int(bank.Nbfiles)+i)
+		contentHashes = append(contentHashes, [32]byte(fileDescriptor.ContentHash))
+	}
+	if !filesProof.VerifyContentHashesMultiProof(contentHashes, newRoot) {
+		return errors.New("Invalid merkle proof for appended files")
+	}
+	return nil
+}
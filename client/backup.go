@@ -0,0 +1,186 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/term"
+)
+
+// CallBackupBank prints the recovery phrase of a bank, holding its private key and master key, and stores the manifest
+// of the bank on the server. The phrase is also printed as a QR code when qr is set. Only banks of descriptor v2 can be
+// backed up, banks of descriptor v1 are upgraded by 'bank rotate'
+func CallBackupBank(bankhome, serverName, bankName string, qr bool) error {
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+	if bank.Version != pb.DescriptorVersion_DESCRIPTOR_V2 {
+		return errors.New(fmt.Sprintf("Bank %v:%v has no master key and cannot be backed up, upgrade it with 'bank rotate'", serverName, bankName))
+	}
+
+	// the agent does not give out keys, the bank password is always asked
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	keys, err := unlockLocalBankKeys(bank, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	passphrase = "" // passphrase will hopefully be garbage-collected
+
+	if err := storeBankManifest(bankhome, server, bank, keys); err != nil {
+		return errors.New(fmt.Sprintf("Could not store the manifest of bank %v:%v on the server: %v", serverName, bankName, err))
+	}
+	phrase, err := cr.EncodeRecoveryPhrase(keys.privKey, keys.masterKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Recovery phrase of bank %s:%s. Anyone who has it can read and modify the bank, keep it offline:\n\n", serverName, bankName)
+	words := strings.Fields(phrase)
+	for i := 0; i < len(words); i += 6 {
+		for j := i; j < i+6 && j < len(words); j++ {
+			fmt.Printf("%2d. %-10s", j+1, words[j])
+		}
+		fmt.Println()
+	}
+	if qr {
+		code, err := qrcode.New(phrase, qrcode.Low)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%s", code.ToSmallString(false))
+	}
+	fmt.Printf("\nThe manifest of the bank is stored on the server, restore the bank with 'bank restore'\n")
+	return nil
+}
+
+// CallRestoreBank writes the descriptor of a bank from its recovery phrase and the manifest stored on the server. The
+// phrase is read from phraseFile, or from the terminal when empty. The private key and master key are protected by a
+// new password, read from newSource, and derived with params
+func CallRestoreBank(bankhome, serverName, bankName, phraseFile string, newSource *cr.PassphraseSource, params cr.KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	server, err := readServerOfNewBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+
+	phrase, err := readRecoveryPhrase(phraseFile)
+	if err != nil {
+		return err
+	}
+	privKey, masterKey, err := cr.DecodeRecoveryPhrase(phrase)
+	if err != nil {
+		return err
+	}
+	keys := &localBankKeys{privKey: privKey, fileKeys: &fileKeys{masterKey: masterKey}}
+	defer keys.close()
+	return restoreBank(bankhome, server, serverName, bankName, keys, newSource, params)
+}
+
+// reads the descriptor of a server that exists locally, for a bank that does not
+func readServerOfNewBank(bankhome, serverName, bankName string) (*pb.ServerDescriptor, error) {
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return nil, err
+	} else if !serverExists {
+		return nil, errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	// verify that bank does not exist
+	if bankExist, err := storage.Client_BankExists(bankhome, serverName, bankName); err != nil {
+		return nil, err
+	} else if bankExist {
+		return nil, errors.New(fmt.Sprintf("Bank %v:%v already exists", serverName, bankName))
+	}
+	return storage.Client_ReadServerDescriptor(bankhome, serverName)
+}
+
+// writes the descriptor of the bank whose private key and master key are given, from the manifest stored on the
+// server, with the keys protected by a new password
+func restoreBank(bankhome string, server *pb.ServerDescriptor, serverName, bankName string, keys *localBankKeys, newSource *cr.PassphraseSource, params cr.KDFParams) error {
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+	result, err := callBankManifest(bankhome, server, keys, bankPubKeyHashB58, nil, nil)
+	if err != nil {
+		return err
+	}
+	bank, err := openBankManifest(result.Manifest, keyHash, keys.fileKeys)
+	if err != nil {
+		return err
+	}
+	// files pushed after the manifest was stored could not be decrypted
+	if bank.Nbfiles != result.Nbfiles {
+		return errors.New(fmt.Sprintf("Manifest lists %v files, but the bank has %v files on the server", bank.Nbfiles, result.Nbfiles))
+	}
+
+	newPassphrase, err := readNewPassphrase(newSource, "bank password")
+	if err != nil {
+		return err
+	}
+	if bank.PrivKey, err = cr.SafeExportPrivateKey(keys.privKey, newPassphrase); err != nil {
+		return err
+	}
+	if bank.MasterKey, err = wrapMasterKey(newPassphrase, keys.masterKey, params, keys.suite); err != nil {
+		return err
+	}
+	if err := storage.Client_WriteBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	fmt.Printf("Bank %s:%s has been restored with its %d files\n", serverName, bankName, bank.Nbfiles)
+	return nil
+}
+
+// reads the recovery phrase from the file at path, or a line of the terminal when path is empty
+func readRecoveryPhrase(path string) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	fmt.Printf("Enter recovery phrase: ")
+	// the phrase holds the keys of the bank, it is not echoed when typed in a terminal
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		phrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		return string(phrase), nil
+	}
+	phrase, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return phrase, nil
+}
@@ -1,10 +1,13 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	pb "github.com/oteffahi/merkle-filebank/proto"
 	"google.golang.org/grpc"
@@ -30,14 +33,14 @@ func connectToNode(endpoint string, bankhome string) (*grpc.ClientConn, pb.FileB
 			config := &tls.Config{
 				RootCAs: certPool,
 			}
-			conn, err = grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+			conn, err = grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(config)), grpc.WithStreamInterceptor(idleStreamInterceptor))
 			client := pb.NewFileBankServiceClient(conn)
 			return conn, client, nil
 		}
 	}
 
 	fmt.Println("!!! Could not load certificate. Connecting without TLS...")
-	conn, err = grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err = grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithStreamInterceptor(idleStreamInterceptor))
 	if err != nil {
 		defer conn.Close()
 		return nil, nil, err
@@ -46,3 +49,48 @@ func connectToNode(endpoint string, bankhome string) (*grpc.ClientConn, pb.FileB
 	client := pb.NewFileBankServiceClient(conn)
 	return conn, client, nil
 }
+
+// streams carrying files have no deadline, as they last as long as their files take to transfer. They are cancelled
+// when no message was sent or received for streamIdleTimeout instead, so that a stalled peer does not block the client
+const streamIdleTimeout = 5 * time.Minute
+
+var errStreamIdle = errors.New(fmt.Sprintf("No message was sent or received for %v", streamIdleTimeout))
+
+// cancels streams that stay idle for streamIdleTimeout
+func idleStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(streamIdleTimeout, func() { cancel(errStreamIdle) })
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		timer.Stop()
+		cancel(nil)
+		return nil, err
+	}
+	return &idleStream{ClientStream: stream, ctx: ctx, timer: timer}, nil
+}
+
+// stream whose idle timer is reset by each message
+type idleStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	timer *time.Timer
+}
+
+func (s *idleStream) SendMsg(m any) error {
+	return s.reset(s.ClientStream.SendMsg(m))
+}
+
+func (s *idleStream) RecvMsg(m any) error {
+	return s.reset(s.ClientStream.RecvMsg(m))
+}
+
+func (s *idleStream) reset(err error) error {
+	if err != nil {
+		if context.Cause(s.ctx) == errStreamIdle {
+			return errStreamIdle
+		}
+		return err
+	}
+	s.timer.Reset(streamIdleTimeout)
+	return nil
+}
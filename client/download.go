@@ -2,11 +2,9 @@ package client
 
 import (
 	"context"
-	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"io"
-	"time"
 
 	cr "github.com/oteffahi/merkle-filebank/cryptography"
 	"github.com/oteffahi/merkle-filebank/merkle"
@@ -14,7 +12,15 @@ import (
 	"github.com/oteffahi/merkle-filebank/storage"
 )
 
-func CallDownloadFiles(bankhome, serverName, bankName string, fileNumber int) error {
+// proofPath is empty when the proof is not saved
+func CallDownloadFiles(bankhome, serverName, bankName string, fileNumbers []int, proofPath string, jsonProof bool) error {
+	if len(fileNumbers) == 0 {
+		return errors.New("Files list is empty")
+	}
+	if proofPath != "" && len(fileNumbers) != 1 {
+		return errors.New("Proofs can only be saved when pulling a single file")
+	}
+
 	// verify that server exists locally
 	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
 		return err
@@ -37,57 +43,102 @@ func CallDownloadFiles(bankhome, serverName, bankName string, fileNumber int) er
 		return err
 	}
 
-	// verify fileNumber exists in bank
-	if fileNumber < 1 || fileNumber > int(bank.Nbfiles) {
-		return errors.New(fmt.Sprintf("No file identified by %v. Bank %v:%v has files between 1-%v", fileNumber, serverName, bankName, bank.Nbfiles))
+	// verify fileNumbers exist in bank
+	requested := make(map[int]bool)
+	for _, fileNumber := range fileNumbers {
+		if fileNumber < 1 || fileNumber > int(bank.Nbfiles) {
+			return errors.New(fmt.Sprintf("No file identified by %v. Bank %v:%v has files between 1-%v", fileNumber, serverName, bankName, bank.Nbfiles))
+		}
+		if requested[fileNumber] {
+			return errors.New(fmt.Sprintf("File %v requested more than once", fileNumber))
+		}
+		requested[fileNumber] = true
 	}
 
-	// import bank private key
-	fmt.Printf("Enter bank password: ")
-	passphrase, err := cr.ReadPassphrase()
-	fmt.Println()
+	// unlock bank keys with the agent or the bank password
+	keys, err := unlockBank(bankhome, bank)
 	if err != nil {
 		return err
 	}
-	bankPrivKey, err := cr.SafeImportPrivateKey(bank.PrivKey, []byte(passphrase))
+	defer keys.close()
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
 	if err != nil {
-		return fmt.Errorf("Error occured while decrypting bank key: %v\n", err)
+		return err
 	}
-	// get publicKey from privateKey
-	bankPubKey, _ := bankPrivKey.Public().(ed25519.PublicKey) // loading private key would have generated an error for this to fail
-	// export, hash and base58 public key
-	exportedPubKey, err := cr.ExportPublicKey(bankPubKey)
+
+	var aeskeys [][]byte
+	for _, fileNumber := range fileNumbers {
+		fileDescriptor := bank.FileDescriptors[fileNumber-1]
+		aeskey, err := keys.fileKey(fileDescriptor.Kdf, fileDescriptor.Salt, fileDescriptor.WrappedKey)
+		if err != nil {
+			return err
+		}
+		aeskeys = append(aeskeys, aeskey)
+	}
+
+	// files are decrypted and decompressed as they are received, and only kept once verified
+	var outputs []*storage.DownloadedFile
+	defer func() {
+		for _, output := range outputs {
+			output.Remove()
+		}
+	}()
+	fileAndProof, err := downloadVerifiedFiles(bankhome, server, bank, keys, bankPubKeyHashB58, fileNumbers, func(i int, ciphertext io.Reader) error {
+		fileDescriptor := bank.FileDescriptors[fileNumbers[i]-1]
+		output, err := storage.Client_CreateDownloadedFile(bankhome, fileDescriptor.Name)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, output)
+		return decompressTo(output, fileDescriptor.Compression, func(w io.Writer) error {
+			aad := fileAAD(keyHash[:], bank.Version, fileDescriptor.Seq, fileDescriptor.Bound)
+			return decryptFile(w, cr.CipherSuite(bank.CipherSuite), ciphertext, aeskeys[i], fileDescriptor.Iv, aad, fileDescriptor.SegmentSize)
+		})
+	})
 	if err != nil {
 		return err
 	}
-	keyHash := cr.HashOnce(exportedPubKey)
-	bankPubKeyHashB58 := cr.Base58Encode(keyHash[:])
+	for i, output := range outputs {
+		if err := output.Commit(); err != nil {
+			return err
+		}
+		fmt.Printf("Successfully downloaded, verified and decrypted file %d from bank %s:%s\n", fileNumbers[i], serverName, bankName)
+	}
 
-	// derive decryption key from passphrase
-	fileDescriptor := bank.FileDescriptors[fileNumber-1]
-	aeskey := cr.DeriveKey([]byte(passphrase), fileDescriptor.Salt)
-	passphrase = "" // passphrase will hopefully be garbage-collected
+	if proofPath != "" {
+		if err := storage.WriteSavedProof(proofPath, savedProofFromResponse(fileAndProof, fileNumbers[0], bank, keyHash[:]), jsonProof); err != nil {
+			return err
+		}
+		fmt.Printf("Merkle proof of file %d written to %s\n", fileNumbers[0], proofPath)
+	}
+	return nil
+}
 
+// downloads files from the server, and verifies them against the merkle root of the bank. The ciphertext of each file
+// is passed to receive as it is received, in requested order, and what receive made of it must be discarded when an
+// error is returned, as files are only verified once all are received. Returns the proof of the file when a single
+// file is downloaded
+func downloadVerifiedFiles(bankhome string, server *pb.ServerDescriptor, bank *pb.ClientBankDescriptor, keys bankKeys, bankPubKeyHashB58 string, fileNumbers []int, receive func(i int, ciphertext io.Reader) error) (*pb.FileAndProof, error) {
 	conn, client, err := connectToNode(server.Host, bankhome)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer conn.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // 30s timeout because of file manipulations on server
+	ctx, cancel := context.WithCancel(context.Background()) // no deadline for transfers, idle streams are cancelled by the connection
 	defer cancel()
 
 	stream, err := client.DownloadFiles(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp1, err := stream.Recv()
 	if err == io.EOF {
-		return errors.New("Connexion closed by server")
+		return nil, errors.New("Connexion closed by server")
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// verify msg type is nonce
@@ -96,75 +147,248 @@ func CallDownloadFiles(bankhome, serverName, bankName string, fileNumber int) er
 	case *pb.DownloadFilesResponse_Nonce:
 		serverNonce = phase.Nonce
 	default:
-		return errors.New("Invalid message type")
+		return nil, errors.New("Invalid message type")
+	}
+
+	// a single file is requested through file_num, several files through file_nums
+	var fileNum int32
+	var fileNums []int32
+	if len(fileNumbers) == 1 {
+		fileNum = int32(fileNumbers[0])
+	} else {
+		for _, fileNumber := range fileNumbers {
+			fileNums = append(fileNums, int32(fileNumber))
+		}
 	}
 
 	// sign request
 	msgToSign := &pb.SignDownloadRequestClient{
 		Nonce:      serverNonce,
 		PubKeyAddr: bankPubKeyHashB58,
-		FileNum:    int32(fileNumber),
+		FileNum:    fileNum,
+		FileNums:   fileNums,
+		Signer:     keys.signer(),
 	}
-	sign, err := cr.SignMessage(msgToSign, bankPrivKey)
+	sign, err := keys.sign(msgToSign)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// generate and send request message
 	if err := stream.Send(&pb.DownloadFilesRequest{
 		Nonce:      serverNonce,
 		PubKeyAddr: bankPubKeyHashB58,
-		FileNum:    int32(fileNumber),
+		FileNum:    fileNum,
 		Signature:  sign,
+		Signer:     keys.signer(),
+		FileNums:   fileNums,
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
 	resp2, err := stream.Recv()
 	if err == io.EOF {
-		return errors.New("Connexion closed by server")
+		return nil, errors.New("Connexion closed by server")
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// proof comes first, files follow
+	var merkleProof *merkle.MerkleProof
+	var multiProof *merkle.MerkleMultiProof
 	var fileAndProof *pb.FileAndProof
 	switch phase := resp2.Phase.(type) {
 	case *pb.DownloadFilesResponse_Fp:
+		if len(fileNumbers) != 1 {
+			return nil, errors.New("Invalid message type")
+		}
+		if merkleProof, err = fileProof(phase.Fp, fileNumbers[0], bank); err != nil {
+			return nil, err
+		}
 		fileAndProof = phase.Fp
+	case *pb.DownloadFilesResponse_Fmp:
+		if len(fileNumbers) == 1 {
+			return nil, errors.New("Invalid message type")
+		}
+		if multiProof, err = filesMultiProof(phase.Fmp, fileNumbers, bank); err != nil {
+			return nil, err
+		}
 	default:
-		return errors.New("Invalid message type")
+		return nil, errors.New("Invalid message type")
 	}
 
-	// unlinearize merkle proof
-	var serverProof [][32]byte
-	if len(fileAndProof.Proof)%32 != 0 {
-		return errors.New("Invalid merkle proof format")
+	// hash files as they are received, the last one ends with the stream
+	files := storage.NewFileMessageReader(func() (*pb.FileMessage, error) {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if phase, ok := resp.Phase.(*pb.DownloadFilesResponse_File); ok {
+			return phase.File, nil
+		}
+		return nil, errors.New("Invalid message type")
+	})
+	tree := merkle.MerkleTree{
+		Mode:      merkle.TreeMode(bank.TreeMode),
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(bank.ChunkSize),
 	}
-	for i := 0; i < len(fileAndProof.Proof); i += 32 {
-		var buff [32]byte
-		copy(buff[:], fileAndProof.Proof[i:i+32])
-		serverProof = append(serverProof, buff)
+	var contentHashes [][32]byte
+	for i, fileNumber := range fileNumbers {
+		if err := files.Next(int32(fileNumber)); err != nil {
+			return nil, err
+		}
+		hasher, err := tree.NewContentHasher()
+		if err != nil {
+			return nil, err
+		}
+		ciphertext := io.TeeReader(files, hasher)
+		if err := receive(i, ciphertext); err != nil {
+			return nil, err
+		}
+		// bytes left by receive are part of the file, and must be hashed too
+		if _, err := io.Copy(io.Discard, ciphertext); err != nil {
+			return nil, err
+		}
+		contentHashes = append(contentHashes, hasher.Sum())
 	}
 
 	// verify proof
-	merkleProof := merkle.MerkleProof{
-		Hashes: serverProof,
+	if merkleProof != nil && !merkleProof.VerifyContentHashProof(contentHashes[0], [32]byte(bank.MerkleRoot)) {
+		return nil, errors.New("Invalid merkle proof")
+	}
+	if multiProof != nil && !multiProof.VerifyContentHashesMultiProof(contentHashes, [32]byte(bank.MerkleRoot)) {
+		return nil, errors.New("Invalid merkle multiproof")
+	}
+	return fileAndProof, nil
+}
+
+// writes the decompression of the output of decrypt to w, decrypting and decompressing at the same time
+func decompressTo(w io.Writer, codec pb.Compression, decrypt func(io.Writer) error) error {
+	if codec == pb.Compression_NO_COMPRESSION {
+		return decrypt(w)
+	}
+	reader, writer := io.Pipe()
+	decrypted := make(chan error, 1)
+	go func() {
+		err := decrypt(writer)
+		writer.CloseWithError(err)
+		decrypted <- err
+	}()
+	err := storage.DecompressFile(w, reader, codec)
+	if err == nil {
+		// read up to the last segment, so that the end of the file is authenticated
+		_, err = io.Copy(io.Discard, reader)
 	}
+	reader.CloseWithError(err)
+	if decryptErr := <-decrypted; decryptErr != nil && decryptErr != io.ErrClosedPipe {
+		return decryptErr
+	}
+	return err
+}
 
-	if validProof := merkleProof.VerifyFileProof(fileAndProof.File, [32]byte(bank.MerkleRoot)); !validProof {
-		return errors.New("Invalid merkle proof")
+func savedProofFromResponse(fileAndProof *pb.FileAndProof, fileNumber int, bank *pb.ClientBankDescriptor, bankKeyHash []byte) *pb.SavedProof {
+	fileDescriptor := bank.FileDescriptors[fileNumber-1]
+	savedProof := &pb.SavedProof{
+		TreeMode:          bank.TreeMode,
+		TreeVersion:       bank.TreeVersion,
+		HashAlgorithm:     bank.HashAlgorithm,
+		ChunkSize:         bank.ChunkSize,
+		MerkleRoot:        bank.MerkleRoot,
+		FileNumber:        int32(fileNumber),
+		FileName:          fileDescriptor.Name,
+		Salt:              fileDescriptor.Salt,
+		Iv:                fileDescriptor.Iv,
+		Kdf:               fileDescriptor.Kdf,
+		WrappedKey:        fileDescriptor.WrappedKey,
+		DescriptorVersion: bank.Version,
+		MasterKey:         bank.MasterKey,
+		SegmentSize:       fileDescriptor.SegmentSize,
+		BankKeyHash:       bankKeyHash,
+		Bound:             fileDescriptor.Bound,
+		CipherSuite:       bank.CipherSuite,
+		Compression:       fileDescriptor.Compression,
+	}
+	if merkle.TreeMode(bank.TreeMode).IsIndexed() {
+		savedProof.LeafIndex = fileAndProof.LeafIndex
+		savedProof.TreeSize = fileAndProof.TreeSize
 	}
+	// proof was verified, its format is valid
+	for i := 0; i < len(fileAndProof.Proof); i += 32 {
+		savedProof.Hashes = append(savedProof.Hashes, fileAndProof.Proof[i:i+32])
+	}
+	return savedProof
+}
 
-	// decrypt file
-	decryptedFile, err := cr.DecryptData(fileAndProof.File, aeskey, fileDescriptor.Iv)
+// returns the proof of a file sent by the server, once checked to prove the requested file. The proof is verified
+// once the file is received
+func fileProof(fileAndProof *pb.FileAndProof, fileNumber int, bank *pb.ClientBankDescriptor) (*merkle.MerkleProof, error) {
+	serverProof, err := unlinearizeProof(fileAndProof.Proof)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := storage.Client_WriteDownloadedFile(bankhome, fileDescriptor.Name, decryptedFile); err != nil {
-		return err
+	merkleProof := merkle.MerkleProof{
+		Hashes:    serverProof,
+		Mode:      merkle.TreeMode(bank.TreeMode),
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(bank.ChunkSize),
 	}
-	fmt.Printf("Successfully downloaded, verified and decrypted file %d from bank %s:%s\n", fileNumber, serverName, bankName)
-	return nil
+	if merkle.TreeMode(bank.TreeMode).IsIndexed() {
+		// verify that the proven leaf is the requested file
+		if int(fileAndProof.LeafIndex) != fileNumber-1 || fileAndProof.TreeSize != bank.Nbfiles {
+			return nil, errors.New(fmt.Sprintf("Merkle proof is for file %v of %v, requested file %v of %v", fileAndProof.LeafIndex+1, fileAndProof.TreeSize, fileNumber, bank.Nbfiles))
+		}
+		merkleProof.Index = int(fileAndProof.LeafIndex)
+		merkleProof.TreeSize = int(fileAndProof.TreeSize)
+	}
+	return &merkleProof, nil
+}
+
+// returns the multiproof of files sent by the server, once checked to prove the requested files. The proof is
+// verified once the files are received
+func filesMultiProof(filesAndProof *pb.FilesAndMultiProof, fileNumbers []int, bank *pb.ClientBankDescriptor) (*merkle.MerkleMultiProof, error) {
+	serverProof, err := unlinearizeProof(filesAndProof.Proof)
+	if err != nil {
+		return nil, err
+	}
+
+	multiProof := merkle.MerkleMultiProof{
+		Hashes:     serverProof,
+		ProofFlags: filesAndProof.ProofFlags,
+		Mode:       merkle.TreeMode(bank.TreeMode),
+		Version:    merkle.TreeVersion(bank.TreeVersion),
+		Hash:       cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize:  int(bank.ChunkSize),
+	}
+	if merkle.TreeMode(bank.TreeMode).IsIndexed() {
+		// verify that the proven leafs are the requested files, in requested order
+		if len(filesAndProof.LeafIndexes) != len(fileNumbers) || filesAndProof.TreeSize != bank.Nbfiles {
+			return nil, errors.New("Merkle multiproof does not match requested files")
+		}
+		for i, fileNumber := range fileNumbers {
+			if int(filesAndProof.LeafIndexes[i]) != fileNumber-1 {
+				return nil, errors.New(fmt.Sprintf("Merkle multiproof is for file %v, requested file %v", filesAndProof.LeafIndexes[i]+1, fileNumber))
+			}
+			multiProof.Indexes = append(multiProof.Indexes, fileNumber-1)
+		}
+		multiProof.TreeSize = int(filesAndProof.TreeSize)
+	}
+	return &multiProof, nil
+}
+
+func unlinearizeProof(proof []byte) ([][32]byte, error) {
+	var hashes [][32]byte
+	if len(proof)%32 != 0 {
+		return nil, errors.New("Invalid merkle proof format")
+	}
+	for i := 0; i < len(proof); i += 32 {
+		var buff [32]byte
+		copy(buff[:], proof[i:i+32])
+		hashes = append(hashes, buff)
+	}
+	return hashes, nil
 }
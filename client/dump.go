@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// DumpBankTree writes the tree of an openzeppelin bank in the format of StandardMerkleTree's dump. The tree is rebuilt
+// from the content hashes of the bank and checked against its merkle root. Dump is written to stdout if outPath is empty
+func DumpBankTree(bankhome, serverName, bankName, outPath string) error {
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if bank.TreeMode != pb.TreeMode_SORTED_TREE || bank.TreeVersion != pb.TreeVersion_TREE_OPENZEPPELIN {
+		return errors.New(fmt.Sprintf("Bank %v:%v does not use an OpenZeppelin tree", serverName, bankName))
+	}
+
+	builder, err := merkle.NewMerkleTreeBuilder(merkle.TreeMode(bank.TreeMode), merkle.TreeVersion(bank.TreeVersion), cr.HashAlgorithm(bank.HashAlgorithm), int(bank.ChunkSize))
+	if err != nil {
+		return err
+	}
+	contentHashes := make([][32]byte, len(bank.FileDescriptors))
+	for i, fileDescriptor := range bank.FileDescriptors {
+		if len(fileDescriptor.ContentHash) != 32 {
+			return errors.New(fmt.Sprintf("Content hash of file %v is unknown", fileDescriptor.Seq))
+		}
+		contentHashes[i] = [32]byte(fileDescriptor.ContentHash)
+		builder.AddContentHash(contentHashes[i])
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	if tree.GetMerkleRoot() != [32]byte(bank.MerkleRoot) {
+		return errors.New("Content hashes of bank do not match its merkle root")
+	}
+
+	dump, err := tree.DumpStandardTree(contentHashes)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if outPath == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Dumped tree of bank %v:%v to %v\n", serverName, bankName, outPath)
+	return nil
+}
+
+// ImportTreeDump validates a dump of StandardMerkleTree. If a bank is given, the dump must be the tree of its files
+func ImportTreeDump(bankhome, dumpPath, serverName, bankName string) error {
+	data, err := os.ReadFile(dumpPath)
+	if err != nil {
+		return err
+	}
+	var dump merkle.StandardTreeDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return errors.New(fmt.Sprintf("Invalid tree dump: %v", err))
+	}
+	tree, err := merkle.LoadStandardTree(&dump)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Invalid tree dump: %v", err))
+	}
+	root := tree.GetMerkleRoot()
+
+	if bankName != "" {
+		bank, err := readLocalBank(bankhome, serverName, bankName)
+		if err != nil {
+			return err
+		}
+		if [32]byte(bank.MerkleRoot) != root {
+			return errors.New(fmt.Sprintf("Dump has merkle root %x, bank %v:%v has %x", root, serverName, bankName, bank.MerkleRoot))
+		}
+		contentHashes, err := dump.ContentHashes()
+		if err != nil {
+			return err
+		}
+		if len(contentHashes) != len(bank.FileDescriptors) {
+			return errors.New(fmt.Sprintf("Dump has %v files, bank %v:%v has %v", len(contentHashes), serverName, bankName, len(bank.FileDescriptors)))
+		}
+		for i, fileDescriptor := range bank.FileDescriptors {
+			if len(fileDescriptor.ContentHash) == 32 && [32]byte(fileDescriptor.ContentHash) != contentHashes[i] {
+				return errors.New(fmt.Sprintf("File %v of dump differs from file %v of bank %v:%v", i+1, fileDescriptor.Seq, serverName, bankName))
+			}
+		}
+		fmt.Printf("Dump matches the %v files of bank %v:%v\n", len(contentHashes), serverName, bankName)
+	}
+	fmt.Printf("Valid tree dump of %v leafs with merkle root %x\n", tree.NbLeafs(), root)
+	return nil
+}
+
+func readLocalBank(bankhome, serverName, bankName string) (*pb.ClientBankDescriptor, error) {
+	if bankExist, err := storage.Client_BankExists(bankhome, serverName, bankName); err != nil {
+		return nil, err
+	} else if !bankExist {
+		return nil, errors.New(fmt.Sprintf("Bank %v:%v does not exist", serverName, bankName))
+	}
+	return storage.Client_ReadBankDescriptor(bankhome, serverName, bankName)
+}
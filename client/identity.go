@@ -0,0 +1,62 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// The identity key of a user is an ed25519 key pair, unrelated to banks. Users give their public key to their team,
+// and receive shares of bank keys encrypted to it.
+
+// CallCreateIdentity generates the identity key of the user, protected by a password read from newSource or from the
+// terminal
+func CallCreateIdentity(bankhome string, newSource *cr.PassphraseSource) error {
+	pubKey, privKey, err := cr.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	passphrase, err := readNewPassphrase(newSource, "identity password")
+	if err != nil {
+		return err
+	}
+	exportedPrivKey, err := cr.SafeExportPrivateKey(privKey, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := storage.Client_WriteIdentityKey(bankhome, exportedPrivKey, cr.PublicKeyToString(pubKey)); err != nil {
+		return err
+	}
+	fmt.Printf("Identity key created. Give your public key to your team:\n%s\n", cr.PublicKeyToString(pubKey))
+	return nil
+}
+
+func CallShowIdentity(bankhome string) error {
+	pubKey, err := storage.Client_ReadIdentityPublicKey(bankhome)
+	if err != nil {
+		return err
+	}
+	fmt.Println(pubKey)
+	return nil
+}
+
+// unlocks the identity key of the user with its password
+func unlockIdentity(bankhome string) (ed25519.PrivateKey, error) {
+	exportedPrivKey, err := storage.Client_ReadIdentityKey(bankhome)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Enter identity password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := cr.SafeImportPrivateKey(exportedPrivKey, []byte(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while decrypting identity key: %v", err)
+	}
+	return privKey, nil
+}
@@ -0,0 +1,320 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// CallRederiveKeys derives the keys of a bank again with new KDF parameters. Banks of descriptor v2 wrap their master
+// key with the new derived key. Files of v1 banks keep their key, which is wrapped with the key derived from the
+// passphrase and a new salt, and the parameters are used for the files added later. Nothing changes on the server,
+// and copies of the bank descriptor made before are not upgraded.
+func CallRederiveKeys(bankhome, serverName, bankName string, params cr.KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	if _, err := cr.SafeImportPrivateKey(bank.PrivKey, []byte(passphrase)); err != nil {
+		return fmt.Errorf("Error occured while decrypting bank key: %v\n", err)
+	}
+
+	if err := rewrapBankKeys(bank, []byte(passphrase), []byte(passphrase), &params); err != nil {
+		return err
+	}
+	passphrase = "" // passphrase will hopefully be garbage-collected
+
+	if err := storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	if bank.Version == pb.DescriptorVersion_DESCRIPTOR_V2 {
+		fmt.Printf("Master key of bank %s:%s is now derived with %v\n", serverName, bankName, params)
+	} else {
+		fmt.Printf("Keys of the %d files of bank %s:%s are now derived with %v\n", len(bank.FileDescriptors), serverName, bankName, params)
+	}
+	return nil
+}
+
+// wraps the keys of a bank with keys derived from newPassphrase, with params or with their current KDF parameters
+// when nil. The master key of v2 banks is wrapped again, v1 banks wrap the key of each file, and derive the keys of
+// files added later with params
+func rewrapBankKeys(bank *pb.ClientBankDescriptor, passphrase, newPassphrase []byte, params *cr.KDFParams) error {
+	if bank.Version == pb.DescriptorVersion_DESCRIPTOR_V2 {
+		keys, err := unlockFileKeys(passphrase, bank.Version, bank.MasterKey, cr.CipherSuite(bank.CipherSuite))
+		if err != nil {
+			return err
+		}
+		masterKeyParams := kdfParamsFromProto(bank.MasterKey.Kdf)
+		if params != nil {
+			masterKeyParams = *params
+		}
+		bank.MasterKey, err = wrapMasterKey(newPassphrase, keys.masterKey, masterKeyParams, keys.suite)
+		return err
+	}
+	for _, fileDescriptor := range bank.FileDescriptors {
+		aeskey, err := deriveKey(passphrase, cr.AES128GCM, fileDescriptor.Kdf, fileDescriptor.Salt, fileDescriptor.WrappedKey)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Could not derive key of file %v: %v", fileDescriptor.Seq, err))
+		}
+		fileParams := kdfParamsFromProto(fileDescriptor.Kdf)
+		if params != nil {
+			fileParams = *params
+		}
+		salt, wrappedKey, err := fileParams.WrapKey(newPassphrase, aeskey, cr.AES128GCM)
+		if err != nil {
+			return err
+		}
+		fileDescriptor.Salt = salt
+		fileDescriptor.Kdf = kdfParamsToProto(fileParams)
+		fileDescriptor.WrappedKey = wrappedKey
+	}
+	if params != nil {
+		bank.Kdf = kdfParamsToProto(*params)
+	}
+	return nil
+}
+
+// keys of the files of a bank. Files of v2 banks derive their key from the master key of the bank, files of v1 banks
+// each derive their key from the passphrase, and use AES-128-GCM
+type fileKeys struct {
+	passphrase []byte
+	masterKey  []byte
+	suite      cr.CipherSuite
+}
+
+func unlockFileKeys(passphrase []byte, version pb.DescriptorVersion, masterKey *pb.MasterKey, suite cr.CipherSuite) (*fileKeys, error) {
+	if err := suite.Validate(); err != nil {
+		return nil, err
+	}
+	switch version {
+	case pb.DescriptorVersion_DESCRIPTOR_V1:
+		if suite != cr.AES128GCM {
+			return nil, errors.New(fmt.Sprintf("Banks of descriptor v1 cannot use %v", suite))
+		}
+		return &fileKeys{passphrase: passphrase, suite: suite}, nil
+	case pb.DescriptorVersion_DESCRIPTOR_V2:
+		if masterKey == nil {
+			return nil, errors.New("Bank has no master key")
+		}
+		key, err := deriveKey(passphrase, suite, masterKey.Kdf, masterKey.Salt, masterKey.WrappedKey)
+		if err != nil {
+			return nil, errors.New("Could not unwrap master key, wrong bank password")
+		}
+		return &fileKeys{masterKey: key, suite: suite}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("Unknown bank descriptor version %v", version))
+	}
+}
+
+// returns the key of a file from its encryption parameters
+func (k *fileKeys) fileKey(kdf *pb.KdfParams, salt, wrappedKey []byte) ([]byte, error) {
+	if k.masterKey != nil {
+		return k.suite.DeriveFileKey(k.masterKey, salt)
+	}
+	return deriveKey(k.passphrase, k.suite, kdf, salt, wrappedKey)
+}
+
+// returns the key and salt of a new file, derived from the master key, or with the KDF parameters of v1 banks
+func (k *fileKeys) newFileKey(kdf *pb.KdfParams) ([]byte, []byte, error) {
+	if k.masterKey != nil {
+		return k.suite.NewFileKey(k.masterKey)
+	}
+	return kdfParamsFromProto(kdf).NewKey(k.passphrase, k.suite.KeySize())
+}
+
+// encrypts a new file as a STREAM bound to aad with the cipher suite of the bank, and a new file key. The plaintext is
+// compressed with codec unless it does not get smaller, and its ciphertext is written to w as it is read. Returns a
+// descriptor holding the encryption parameters of the file
+func encryptFile(w io.Writer, keys bankKeys, plaintext io.ReadSeeker, codec pb.Compression, kdf *pb.KdfParams, aad []byte) (*pb.FileDescriptor, error) {
+	codec, err := storage.FileCompression(plaintext, codec)
+	if err != nil {
+		return nil, err
+	}
+	var descriptor *pb.FileDescriptor
+	if err := compressFrom(plaintext, codec, func(r io.Reader) error {
+		descriptor, err = encryptCompressedFile(w, keys, r, codec, kdf, aad)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return descriptor, nil
+}
+
+// encrypts a file already compressed with codec like encryptFile, such as the files of a bank decrypted before they
+// are encrypted again, which keep their compression
+func encryptCompressedFile(w io.Writer, keys bankKeys, compressed io.Reader, codec pb.Compression, kdf *pb.KdfParams, aad []byte) (*pb.FileDescriptor, error) {
+	aeskey, salt, err := keys.newFileKey(kdf)
+	if err != nil {
+		return nil, err
+	}
+	suite := keys.cipherSuite()
+	noncePrefix, err := suite.NewStreamNoncePrefix()
+	if err != nil {
+		return nil, err
+	}
+	encryptedFile := &countingWriter{w: w}
+	if err := suite.EncryptStream(encryptedFile, compressed, aeskey, noncePrefix, aad, cr.DefaultSegmentSize); err != nil {
+		return nil, err
+	}
+	return &pb.FileDescriptor{
+		Salt:        salt,
+		Iv:          noncePrefix,
+		Size:        encryptedFile.n,
+		Kdf:         kdf,
+		SegmentSize: cr.DefaultSegmentSize,
+		Bound:       true,
+		Compression: codec,
+	}, nil
+}
+
+// encrypts the file at path with encryptFile, reading it from disk as it is encrypted
+func encryptFileFromPath(w io.Writer, keys bankKeys, path string, codec pb.Compression, kdf *pb.KdfParams, aad []byte) (*pb.FileDescriptor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return encryptFile(w, keys, file, codec, kdf, aad)
+}
+
+// passes the compression of plaintext with codec to encrypt, compressing and encrypting at the same time
+func compressFrom(plaintext io.Reader, codec pb.Compression, encrypt func(io.Reader) error) error {
+	if codec == pb.Compression_NO_COMPRESSION {
+		return encrypt(plaintext)
+	}
+	reader, writer := io.Pipe()
+	compressed := make(chan error, 1)
+	go func() {
+		err := storage.CompressFile(writer, plaintext, codec)
+		writer.CloseWithError(err)
+		compressed <- err
+	}()
+	err := encrypt(reader)
+	reader.CloseWithError(err)
+	if compressErr := <-compressed; compressErr != nil && compressErr != io.ErrClosedPipe {
+		return compressErr
+	}
+	return err
+}
+
+// writer counting the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// associated data of a file, binding its ciphertext to its bank, number and descriptor version. Files encrypted
+// before files were bound have none
+func fileAAD(bankKeyHash []byte, version pb.DescriptorVersion, seq int32, bound bool) []byte {
+	if !bound {
+		return nil
+	}
+	return cr.FileAAD(bankKeyHash, uint64(seq), uint32(version))
+}
+
+// decrypts a file to w as it is read, as a STREAM if it has a segment size. Files sealed in a single call use
+// AES-128-GCM and have no associated data
+func decryptFile(w io.Writer, suite cr.CipherSuite, encryptedFile io.Reader, aeskey, iv, aad []byte, segmentSize int32) error {
+	if segmentSize > 0 {
+		return suite.DecryptStream(w, encryptedFile, aeskey, iv, aad, int(segmentSize))
+	}
+	// files sealed in a single call are only authenticated once read entirely
+	ciphertext, err := io.ReadAll(encryptedFile)
+	if err != nil {
+		return err
+	}
+	decryptedFile, err := cr.DecryptData(ciphertext, aeskey, iv)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(decryptedFile)
+	return err
+}
+
+// encrypts a plaintext again with the key and iv of a file, giving back its ciphertext
+func reencryptFile(suite cr.CipherSuite, plaintext, aeskey, iv, aad []byte, segmentSize int32) ([]byte, error) {
+	if segmentSize > 0 {
+		var encryptedFile bytes.Buffer
+		if err := suite.EncryptStream(&encryptedFile, bytes.NewReader(plaintext), aeskey, iv, aad, int(segmentSize)); err != nil {
+			return nil, err
+		}
+		return encryptedFile.Bytes(), nil
+	}
+	return cr.EncryptDataWithKey(plaintext, aeskey, iv)
+}
+
+func wrapMasterKey(passphrase, masterKey []byte, params cr.KDFParams, suite cr.CipherSuite) (*pb.MasterKey, error) {
+	salt, wrappedKey, err := params.WrapKey(passphrase, masterKey, suite)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MasterKey{
+		Kdf:        kdfParamsToProto(params),
+		Salt:       salt,
+		WrappedKey: wrappedKey,
+	}, nil
+}
+
+// derives a key of the cipher suite from the passphrase, for the files of v1 banks and the master key of v2 banks.
+// Keys of files whose keys were derived again by CallRederiveKeys are unwrapped with the derived key
+func deriveKey(passphrase []byte, suite cr.CipherSuite, kdf *pb.KdfParams, salt, wrappedKey []byte) ([]byte, error) {
+	derivedKey, err := kdfParamsFromProto(kdf).DeriveKey(passphrase, salt, suite.KeySize())
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) == 0 {
+		return derivedKey, nil
+	}
+	key, err := suite.UnwrapKey(derivedKey, wrappedKey)
+	if err != nil {
+		return nil, errors.New("Could not unwrap file key, wrong bank password")
+	}
+	return key, nil
+}
+
+// files and banks without KDF parameters use PBKDF2
+func kdfParamsFromProto(params *pb.KdfParams) cr.KDFParams {
+	if params == nil {
+		return cr.KDFParams{KDF: cr.PBKDF2}
+	}
+	return cr.KDFParams{
+		KDF:     cr.KDF(params.Kdf),
+		Time:    params.Time,
+		Memory:  params.Memory,
+		Threads: uint8(min(params.Threads, 255)),
+	}
+}
+
+func kdfParamsToProto(params cr.KDFParams) *pb.KdfParams {
+	return &pb.KdfParams{
+		Kdf:     pb.Kdf(params.KDF),
+		Time:    params.Time,
+		Memory:  params.Memory,
+		Threads: uint32(params.Threads),
+	}
+}
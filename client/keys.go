@@ -0,0 +1,95 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// unlocked keys of a bank, held by this process or by the agent
+type bankKeys interface {
+	publicKey() ed25519.PublicKey
+	sign(m proto.Message) ([]byte, error)
+	// identity key signing requests as a recipient of the bank, nil when the bank key signs them
+	signer() ed25519.PublicKey
+	// key of a file from its encryption parameters
+	fileKey(kdf *pb.KdfParams, salt, wrappedKey []byte) ([]byte, error)
+	// key and salt of a new file, derived with kdf in v1 banks
+	newFileKey(kdf *pb.KdfParams) ([]byte, []byte, error)
+	cipherSuite() cr.CipherSuite
+	// forgets the keys
+	close()
+}
+
+type localBankKeys struct {
+	privKey ed25519.PrivateKey
+	*fileKeys
+}
+
+// unlocks the keys of a bank with the agent when it holds them, or with the bank password
+func unlockBank(bankhome string, bank *pb.ClientBankDescriptor) (bankKeys, error) {
+	if isSharedBank(bank) {
+		return unlockRecipientBankKeys(bankhome, bank)
+	}
+	if keys := agentBankKeysIfHeld(bankhome, bank); keys != nil {
+		return keys, nil
+	}
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	return unlockLocalBankKeys(bank, []byte(passphrase))
+}
+
+func unlockLocalBankKeys(bank *pb.ClientBankDescriptor, passphrase []byte) (*localBankKeys, error) {
+	if isSharedBank(bank) {
+		return nil, errors.New("Bank is shared with you and has no bank key, only its owner can do this")
+	}
+	privKey, err := cr.SafeImportPrivateKey(bank.PrivKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while decrypting bank key: %v\n", err)
+	}
+	keys, err := unlockFileKeys(passphrase, bank.Version, bank.MasterKey, cr.CipherSuite(bank.CipherSuite))
+	if err != nil {
+		return nil, err
+	}
+	return &localBankKeys{privKey: privKey, fileKeys: keys}, nil
+}
+
+func (k *localBankKeys) publicKey() ed25519.PublicKey {
+	return k.privKey.Public().(ed25519.PublicKey)
+}
+
+func (k *localBankKeys) sign(m proto.Message) ([]byte, error) {
+	return cr.SignMessage(m, k.privKey)
+}
+
+func (k *localBankKeys) signer() ed25519.PublicKey {
+	return nil
+}
+
+func (k *localBankKeys) cipherSuite() cr.CipherSuite {
+	return k.suite
+}
+
+func (k *localBankKeys) close() {
+	clear(k.privKey)
+	clear(k.masterKey)
+	clear(k.passphrase)
+}
+
+// hash of the exported public key of a bank, identifying the bank on its server, and its base58 encoding
+func bankKeyHash(keys bankKeys) ([32]byte, string, error) {
+	exportedPubKey, err := cr.ExportPublicKey(keys.publicKey())
+	if err != nil {
+		return [32]byte{}, "", err
+	}
+	keyHash := cr.HashOnce(exportedPubKey)
+	return keyHash, cr.Base58Encode(keyHash[:]), nil
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// The manifest of a bank is its client descriptor without the private key and the master key. It holds the salts and
+// ivs of the files, and is stored encrypted on the server, so that the bank can be restored from its recovery phrase
+// alone, or fetched by the recipients it is shared with. Only banks of descriptor v2 have one, as its key is derived
+// from the master key.
+
+const manifestAADPrefix = "merkle-filebank manifest"
+
+// associated data of the manifest, binding it to its bank
+func manifestAAD(bankKeyHash [32]byte) []byte {
+	return append([]byte(manifestAADPrefix), bankKeyHash[:]...)
+}
+
+// encrypts the manifest of a bank with a key derived from its master key and a new salt
+func sealBankManifest(bank *pb.ClientBankDescriptor, keys bankKeys) ([]byte, error) {
+	keyHash, _, err := bankKeyHash(keys)
+	if err != nil {
+		return nil, err
+	}
+	manifest := proto.Clone(bank).(*pb.ClientBankDescriptor)
+	manifest.PrivKey = nil
+	manifest.MasterKey = nil
+	data, err := proto.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	key, salt, err := keys.newFileKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	suite := keys.cipherSuite()
+	ciphertext, err := suite.Seal(key, data, manifestAAD(keyHash))
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&pb.EncryptedManifest{
+		CipherSuite: pb.CipherSuite(suite),
+		Salt:        salt,
+		Ciphertext:  ciphertext,
+	})
+}
+
+// decrypts the manifest of the bank whose key hash is given with its master key, and sets the cipher suite of keys
+func openBankManifest(data []byte, keyHash [32]byte, keys *fileKeys) (*pb.ClientBankDescriptor, error) {
+	encryptedManifest := &pb.EncryptedManifest{}
+	if err := proto.Unmarshal(data, encryptedManifest); err != nil {
+		return nil, err
+	}
+	keys.suite = cr.CipherSuite(encryptedManifest.CipherSuite)
+	if err := keys.suite.Validate(); err != nil {
+		return nil, err
+	}
+	key, err := keys.fileKey(nil, encryptedManifest.Salt, nil)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := keys.suite.Open(key, encryptedManifest.Ciphertext, manifestAAD(keyHash))
+	if err != nil {
+		return nil, errors.New("Could not decrypt manifest, wrong master key")
+	}
+	manifest := &pb.ClientBankDescriptor{}
+	if err := proto.Unmarshal(plaintext, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != pb.DescriptorVersion_DESCRIPTOR_V2 || manifest.CipherSuite != encryptedManifest.CipherSuite {
+		return nil, errors.New("Invalid manifest")
+	}
+	return manifest, nil
+}
+
+// stores the manifest of a bank on its server, along with its recipients. Banks of descriptor v1 have no manifest
+func storeBankManifest(bankhome string, server *pb.ServerDescriptor, bank *pb.ClientBankDescriptor, keys bankKeys) error {
+	if bank.Version != pb.DescriptorVersion_DESCRIPTOR_V2 {
+		return nil
+	}
+	_, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+	manifest, err := sealBankManifest(bank, keys)
+	if err != nil {
+		return err
+	}
+	_, err = callBankManifest(bankhome, server, keys, bankPubKeyHashB58, manifest, bank.Recipients)
+	return err
+}
+
+// stores the manifest after the bank changed on the server. The change is already done, so failures are only reported
+func refreshBankManifest(bankhome string, server *pb.ServerDescriptor, bank *pb.ClientBankDescriptor, keys bankKeys) {
+	if err := storeBankManifest(bankhome, server, bank, keys); err != nil {
+		fmt.Printf("Warning: could not store the manifest of the bank on the server, restoring the bank from its recovery phrase will fail until 'bank backup' is run again: %v\n", err)
+	}
+}
+
+// stores manifest and recipients on the server, or reads them back when manifest is empty
+func callBankManifest(bankhome string, server *pb.ServerDescriptor, keys bankKeys, bankPubKeyHashB58 string, manifest []byte, recipients []*pb.BankRecipient) (*pb.ManifestResult, error) {
+	conn, client, err := connectToNode(server.Host, bankhome)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.BankManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp1, err := stream.Recv()
+	if err == io.EOF {
+		return nil, errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var serverNonce []byte
+	switch phase := resp1.Phase.(type) {
+	case *pb.BankManifestResponse_Nonce:
+		serverNonce = phase.Nonce
+	default:
+		return nil, errors.New("Invalid message type")
+	}
+
+	// sign request
+	msgToSign := &pb.SignManifestRequestClient{
+		Nonce:      serverNonce,
+		PubKeyAddr: bankPubKeyHashB58,
+		Manifest:   manifest,
+		Recipients: recipients,
+		Signer:     keys.signer(),
+	}
+	sign, err := keys.sign(msgToSign)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&pb.BankManifestRequest{
+		Nonce:      serverNonce,
+		PubKeyAddr: bankPubKeyHashB58,
+		Manifest:   manifest,
+		Signature:  sign,
+		Recipients: recipients,
+		Signer:     keys.signer(),
+	}); err != nil {
+		return nil, err
+	}
+
+	resp2, err := stream.Recv()
+	if err == io.EOF {
+		return nil, errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch phase := resp2.Phase.(type) {
+	case *pb.BankManifestResponse_Result:
+		return phase.Result, nil
+	default:
+		return nil, errors.New("Invalid message type")
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// CallChangePassphrase encrypts the private key and the keys of a bank with a new password, read from newSource or
+// from the terminal. Like CallRederiveKeys, nothing changes on the server
+func CallChangePassphrase(bankhome, serverName, bankName string, newSource *cr.PassphraseSource) error {
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Enter current bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	privKey, err := cr.SafeImportPrivateKey(bank.PrivKey, []byte(passphrase))
+	if err != nil {
+		return fmt.Errorf("Error occured while decrypting bank key: %v\n", err)
+	}
+	newPassphrase, err := readNewPassphrase(newSource, "bank password")
+	if err != nil {
+		return err
+	}
+
+	if err := rewrapBankKeys(bank, []byte(passphrase), newPassphrase, nil); err != nil {
+		return err
+	}
+	if bank.PrivKey, err = cr.SafeExportPrivateKey(privKey, newPassphrase); err != nil {
+		return err
+	}
+	passphrase = "" // passphrase will hopefully be garbage-collected
+
+	if err := storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	fmt.Printf("Password of bank %s:%s has been changed\n", serverName, bankName)
+	return nil
+}
+
+// reads a new password twice from source, or from the terminal when nil. what names the password in prompts
+func readNewPassphrase(source *cr.PassphraseSource, what string) ([]byte, error) {
+	if source == nil {
+		source = &cr.PassphraseSource{Fd: -1}
+	}
+	fmt.Printf("Enter new %s: ", what)
+	firstPass, err := source.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Re-enter new %s: ", what)
+	pass, err := source.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	if pass != firstPass {
+		return nil, errors.New("Passwords do not match. Aborting.")
+	}
+	return []byte(pass), nil
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// size of the GCM tag at the end of encrypted files, and of each STREAM segment
+const gcmTagSize = 16
+
+func CallDownloadFileRange(bankhome, serverName, bankName string, fileNumber int, offset, length int64) error {
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+
+	// verify that bank exists
+	if bankExist, err := storage.Client_BankExists(bankhome, serverName, bankName); err != nil {
+		return err
+	} else if !bankExist {
+		return errors.New(fmt.Sprintf("Bank %v:%v does not exist", serverName, bankName))
+	}
+	bank, err := storage.Client_ReadBankDescriptor(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if !merkle.TreeMode(bank.TreeMode).IsIndexed() || bank.ChunkSize < 1 {
+		return errors.New("Ranged downloads are only supported by banks using chunked indexed trees")
+	}
+
+	// verify fileNumber exists in bank
+	if fileNumber < 1 || fileNumber > int(bank.Nbfiles) {
+		return errors.New(fmt.Sprintf("No file identified by %v. Bank %v:%v has files between 1-%v", fileNumber, serverName, bankName, bank.Nbfiles))
+	}
+	fileDescriptor := bank.FileDescriptors[fileNumber-1]
+	if fileDescriptor.Compression != pb.Compression_NO_COMPRESSION {
+		return errors.New(fmt.Sprintf("File %v was compressed, download the whole file instead", fileNumber))
+	}
+	ctOffset, ctLength, err := ciphertextRange(fileDescriptor, offset, length)
+	if err != nil {
+		return err
+	}
+
+	// unlock bank keys with the agent or the bank password
+	keys, err := unlockBank(bankhome, bank)
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+
+	aeskey, err := keys.fileKey(fileDescriptor.Kdf, fileDescriptor.Salt, fileDescriptor.WrappedKey)
+	if err != nil {
+		return err
+	}
+
+	conn, client, err := connectToNode(server.Host, bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background()) // no deadline for transfers, idle streams are cancelled by the connection
+	defer cancel()
+
+	stream, err := client.DownloadFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp1, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return err
+	}
+
+	// verify msg type is nonce
+	var serverNonce []byte
+	switch phase := resp1.Phase.(type) {
+	case *pb.DownloadFilesResponse_Nonce:
+		serverNonce = phase.Nonce
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// sign request
+	msgToSign := &pb.SignDownloadRequestClient{
+		Nonce:       serverNonce,
+		PubKeyAddr:  bankPubKeyHashB58,
+		FileNum:     int32(fileNumber),
+		RangeOffset: ctOffset,
+		RangeLength: ctLength,
+		Signer:      keys.signer(),
+	}
+	sign, err := keys.sign(msgToSign)
+	if err != nil {
+		return err
+	}
+
+	// generate and send request message
+	if err := stream.Send(&pb.DownloadFilesRequest{
+		Nonce:      serverNonce,
+		PubKeyAddr: bankPubKeyHashB58,
+		FileNum:    int32(fileNumber),
+		Signature:  sign,
+		Signer:     keys.signer(),
+		Range: &pb.ByteRange{
+			Offset: ctOffset,
+			Length: ctLength,
+		},
+	}); err != nil {
+		return err
+	}
+
+	resp2, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return err
+	}
+
+	var rangeAndProof *pb.RangeAndProof
+	switch phase := resp2.Phase.(type) {
+	case *pb.DownloadFilesResponse_Rp:
+		rangeAndProof = phase.Rp
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// chunks follow the proof as parts of the file, they cover the range and at most a chunk on each side
+	files := storage.NewFileMessageReader(func() (*pb.FileMessage, error) {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if phase, ok := resp.Phase.(*pb.DownloadFilesResponse_File); ok {
+			return phase.File, nil
+		}
+		return nil, errors.New("Invalid message type")
+	})
+	if err := files.Next(int32(fileNumber)); err != nil {
+		return err
+	}
+	if rangeAndProof.Chunks, err = io.ReadAll(io.LimitReader(files, ctLength+2*int64(bank.ChunkSize)+1)); err != nil {
+		return err
+	}
+	ctRange, err := verifyRangeAndProof(rangeAndProof, fileNumber, ctOffset, ctLength, bank)
+	if err != nil {
+		return err
+	}
+
+	// decrypt range
+	aad := fileAAD(keyHash[:], bank.Version, fileDescriptor.Seq, fileDescriptor.Bound)
+	decryptedRange, err := decryptFileRange(cr.CipherSuite(bank.CipherSuite), ctRange, aeskey, aad, fileDescriptor, offset, length)
+	if err != nil {
+		return err
+	}
+	rangeName := fmt.Sprintf("%s.%d-%d", fileDescriptor.Name, offset, offset+length)
+	if err := storage.Client_WriteDownloadedFile(bankhome, rangeName, decryptedRange); err != nil {
+		return err
+	}
+	fmt.Printf("Successfully downloaded, verified and decrypted bytes %d-%d of file %d from bank %s:%s\n", offset, offset+length, fileNumber, serverName, bankName)
+	return nil
+}
+
+// returns the range of the ciphertext of a file covering a plaintext range. Files sealed in a single call have the
+// offsets of their plaintext, followed by their tag. STREAM files are downloaded by whole segments
+func ciphertextRange(fileDescriptor *pb.FileDescriptor, offset, length int64) (int64, int64, error) {
+	segmentSize := int64(fileDescriptor.SegmentSize)
+	plaintextSize := fileDescriptor.Size - gcmTagSize
+	if segmentSize > 0 {
+		size, err := cr.StreamPlaintextSize(fileDescriptor.Size, int(segmentSize))
+		if err != nil {
+			return 0, 0, errors.New(fmt.Sprintf("Invalid size of file %v: %v", fileDescriptor.Seq, err))
+		}
+		plaintextSize = size
+	} else if fileDescriptor.Size < gcmTagSize {
+		return 0, 0, errors.New(fmt.Sprintf("Size of file %v is unknown, download the whole file instead", fileDescriptor.Seq))
+	}
+	// offset+length may overflow, compare the length to the remaining size instead
+	if offset < 0 || length < 1 || offset > plaintextSize || length > plaintextSize-offset {
+		return 0, 0, errors.New(fmt.Sprintf("Invalid range of %v bytes at offset %v for file %v of size %v", length, offset, fileDescriptor.Seq, plaintextSize))
+	}
+	if segmentSize == 0 {
+		return offset, length, nil
+	}
+	sealedSegmentSize := segmentSize + gcmTagSize
+	ctOffset := offset / segmentSize * sealedSegmentSize
+	ctEnd := min(((offset+length-1)/segmentSize+1)*sealedSegmentSize, fileDescriptor.Size)
+	return ctOffset, ctEnd - ctOffset, nil
+}
+
+// decrypts the plaintext range of a file from the ciphertext range returned by ciphertextRange. The GCM tag of files
+// sealed in a single call is not checked, STREAM segments are authenticated
+func decryptFileRange(suite cr.CipherSuite, ctRange, aeskey, aad []byte, fileDescriptor *pb.FileDescriptor, offset, length int64) ([]byte, error) {
+	segmentSize := int64(fileDescriptor.SegmentSize)
+	if segmentSize == 0 {
+		return cr.DecryptDataRange(ctRange, aeskey, fileDescriptor.Iv, offset)
+	}
+	plaintextSize, err := cr.StreamPlaintextSize(fileDescriptor.Size, int(segmentSize))
+	if err != nil {
+		return nil, err
+	}
+	firstSegment := offset / segmentSize
+	segments, err := suite.DecryptStreamSegments(ctRange, aeskey, fileDescriptor.Iv, aad, int(segmentSize), firstSegment, cr.StreamNbSegments(plaintextSize, int(segmentSize)))
+	if err != nil {
+		return nil, err
+	}
+	start := offset - firstSegment*segmentSize
+	return segments[start : start+length], nil
+}
+
+// verifies the chunks against the chunk root, and the chunk root against the bank root. Returns the requested ciphertext range
+func verifyRangeAndProof(rangeAndProof *pb.RangeAndProof, fileNumber int, offset, length int64, bank *pb.ClientBankDescriptor) ([]byte, error) {
+	fileSize := bank.FileDescriptors[fileNumber-1].Size
+	chunkSize := int64(bank.ChunkSize)
+
+	// verify that the chunks are the ones covering the requested range
+	firstChunk, lastChunk, err := merkle.ChunksOfRange(offset, length, fileSize, int(chunkSize))
+	if err != nil {
+		return nil, err
+	}
+	chunksEnd := min((lastChunk+1)*chunkSize, fileSize)
+	if int64(rangeAndProof.FirstChunk) != firstChunk {
+		return nil, errors.New(fmt.Sprintf("Response starts at chunk %v, expected chunk %v", rangeAndProof.FirstChunk, firstChunk))
+	}
+	if int64(len(rangeAndProof.Chunks)) != chunksEnd-firstChunk*chunkSize {
+		return nil, errors.New("Invalid size of chunks in response")
+	}
+	if len(rangeAndProof.ChunkRoot) != 32 {
+		return nil, errors.New("Invalid chunk root format")
+	}
+	chunkRoot := [32]byte(rangeAndProof.ChunkRoot)
+
+	// verify chunks in chunk tree
+	chunksHashes, err := unlinearizeProof(rangeAndProof.ChunksProof)
+	if err != nil {
+		return nil, err
+	}
+	chunksProof := merkle.MerkleMultiProof{
+		Hashes:   chunksHashes,
+		Mode:     merkle.IndexedTree,
+		Version:  merkle.TreeVersion(bank.TreeVersion),
+		Hash:     cr.HashAlgorithm(bank.HashAlgorithm),
+		TreeSize: merkle.NbChunks(fileSize, int(chunkSize)),
+	}
+	var chunks [][]byte
+	for i := firstChunk; i <= lastChunk; i++ {
+		start := (i - firstChunk) * chunkSize
+		end := min(start+chunkSize, int64(len(rangeAndProof.Chunks)))
+		chunks = append(chunks, rangeAndProof.Chunks[start:end])
+		chunksProof.Indexes = append(chunksProof.Indexes, int(i))
+	}
+	if !chunksProof.VerifyFilesMultiProof(chunks, chunkRoot) {
+		return nil, errors.New("Invalid merkle multiproof for chunks")
+	}
+
+	// verify chunk root in bank tree
+	if int(rangeAndProof.LeafIndex) != fileNumber-1 || rangeAndProof.TreeSize != bank.Nbfiles {
+		return nil, errors.New(fmt.Sprintf("Merkle proof is for file %v of %v, requested file %v of %v", rangeAndProof.LeafIndex+1, rangeAndProof.TreeSize, fileNumber, bank.Nbfiles))
+	}
+	serverProof, err := unlinearizeProof(rangeAndProof.Proof)
+	if err != nil {
+		return nil, err
+	}
+	merkleProof := merkle.MerkleProof{
+		Hashes:    serverProof,
+		Mode:      merkle.IndexedTree,
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(chunkSize),
+		Index:     int(rangeAndProof.LeafIndex),
+		TreeSize:  int(rangeAndProof.TreeSize),
+	}
+	if !merkleProof.VerifyContentHashProof(chunkRoot, [32]byte(bank.MerkleRoot)) {
+		return nil, errors.New("Invalid merkle proof for chunk root")
+	}
+
+	start := offset - firstChunk*chunkSize
+	return rangeAndProof.Chunks[start : start+length], nil
+}
@@ -0,0 +1,365 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// A bank of descriptor v2 can be shared with recipients, by encrypting its master key with HPKE to their identity
+// keys. The recipients are stored in the bank descriptor and uploaded with the manifest, the server then accepts
+// downloads signed by their identity keys. Recipients fetch the bank with its address, and can only read it: pushing
+// files and rotating still need the bank key.
+
+var bankRecipientInfo = []byte("merkle-filebank bank recipient")
+
+// keys of a bank shared with the user, whose requests are signed by the identity key of the user
+type recipientBankKeys struct {
+	bankPubKey ed25519.PublicKey
+	identity   ed25519.PrivateKey
+	*fileKeys
+}
+
+// banks shared with the user have the public key of the bank instead of its private key
+func isSharedBank(bank *pb.ClientBankDescriptor) bool {
+	return len(bank.PrivKey) == 0 && len(bank.BankPubKey) > 0
+}
+
+// operations that need the bank key are refused on banks shared with the user
+func requireBankOwner(bank *pb.ClientBankDescriptor, serverName, bankName string) error {
+	if isSharedBank(bank) {
+		return errors.New(fmt.Sprintf("Bank %v:%v is shared with you, only its owner can do this", serverName, bankName))
+	}
+	return nil
+}
+
+// unlocks the keys of a bank shared with the user with the identity password
+func unlockRecipientBankKeys(bankhome string, bank *pb.ClientBankDescriptor) (*recipientBankKeys, error) {
+	bankPubKey, err := cr.ImportPublicKey(bank.BankPubKey)
+	if err != nil {
+		return nil, err
+	}
+	suite := cr.CipherSuite(bank.CipherSuite)
+	if err := suite.Validate(); err != nil {
+		return nil, err
+	}
+	identity, err := unlockIdentity(bankhome)
+	if err != nil {
+		return nil, err
+	}
+	keys := &recipientBankKeys{bankPubKey: bankPubKey, identity: identity, fileKeys: &fileKeys{suite: suite}}
+	keyHash, _, err := bankKeyHash(keys)
+	if err != nil {
+		keys.close()
+		return nil, err
+	}
+	if keys.masterKey, err = unwrapRecipientMasterKey(keyHash, bank.Recipients, identity); err != nil {
+		keys.close()
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (k *recipientBankKeys) publicKey() ed25519.PublicKey {
+	return k.bankPubKey
+}
+
+func (k *recipientBankKeys) sign(m proto.Message) ([]byte, error) {
+	return cr.SignMessage(m, k.identity)
+}
+
+func (k *recipientBankKeys) signer() ed25519.PublicKey {
+	return k.identity.Public().(ed25519.PublicKey)
+}
+
+func (k *recipientBankKeys) cipherSuite() cr.CipherSuite {
+	return k.suite
+}
+
+func (k *recipientBankKeys) close() {
+	clear(k.identity)
+	clear(k.masterKey)
+}
+
+// associated data of a wrapped master key, binding it to its bank and its recipient
+func recipientAAD(bankKeyHash [32]byte, identity ed25519.PublicKey) []byte {
+	return append(append([]byte{}, bankKeyHash[:]...), identity...)
+}
+
+// encrypts the master key of a bank to the identity key of a recipient
+func wrapMasterKeyForRecipient(bankKeyHash [32]byte, masterKey []byte, identity ed25519.PublicKey) (*pb.BankRecipient, error) {
+	recipient, err := cr.X25519PublicKey(identity)
+	if err != nil {
+		return nil, err
+	}
+	enc, wrappedKey, err := cr.HPKESeal(recipient, bankRecipientInfo, recipientAAD(bankKeyHash, identity), masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BankRecipient{
+		Identity:   identity,
+		Enc:        enc,
+		WrappedKey: wrappedKey,
+	}, nil
+}
+
+// decrypts the master key of a bank wrapped to the identity key of the user
+func unwrapRecipientMasterKey(bankKeyHash [32]byte, recipients []*pb.BankRecipient, identity ed25519.PrivateKey) ([]byte, error) {
+	pubKey := identity.Public().(ed25519.PublicKey)
+	for _, recipient := range recipients {
+		if !bytes.Equal(recipient.Identity, pubKey) {
+			continue
+		}
+		x25519Identity, err := cr.X25519PrivateKey(identity)
+		if err != nil {
+			return nil, err
+		}
+		masterKey, err := cr.HPKEOpen(x25519Identity, recipient.Enc, bankRecipientInfo, recipientAAD(bankKeyHash, pubKey), recipient.WrappedKey)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Could not unwrap master key: %v", err))
+		}
+		return masterKey, nil
+	}
+	return nil, errors.New(fmt.Sprintf("Bank is not shared with identity %v", cr.PublicKeyToString(pubKey)))
+}
+
+// reads the identity keys of recipients from their base58 encoding
+func readRecipientKeys(recipients []string) ([]ed25519.PublicKey, error) {
+	var recipientKeys []ed25519.PublicKey
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		pubKey, err := cr.PublicKeyFromString(recipient)
+		if err != nil {
+			return nil, err
+		}
+		if seen[string(pubKey)] {
+			return nil, errors.New(fmt.Sprintf("Recipient %v is given more than once", recipient))
+		}
+		seen[string(pubKey)] = true
+		recipientKeys = append(recipientKeys, pubKey)
+	}
+	return recipientKeys, nil
+}
+
+// reads the descriptors of a server and of one of its banks owned by the user, which must be of descriptor v2
+func readOwnedBank(bankhome, serverName, bankName string) (*pb.ServerDescriptor, *pb.ClientBankDescriptor, error) {
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return nil, nil, err
+	} else if !serverExists {
+		return nil, nil, errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return nil, nil, err
+	}
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return nil, nil, err
+	}
+	if bank.Version != pb.DescriptorVersion_DESCRIPTOR_V2 {
+		return nil, nil, errors.New(fmt.Sprintf("Bank %v:%v has no master key and cannot be shared, upgrade it with 'bank rotate'", serverName, bankName))
+	}
+	return server, bank, nil
+}
+
+// CallGrantBank shares a bank with recipients, given by their identity public keys. The master key is wrapped to each
+// of them, and the recipients are stored on the server with the manifest of the bank
+func CallGrantBank(bankhome, serverName, bankName string, recipients []string) error {
+	recipientKeys, err := readRecipientKeys(recipients)
+	if err != nil {
+		return err
+	}
+	server, bank, err := readOwnedBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range bank.Recipients {
+		for i, pubKey := range recipientKeys {
+			if bytes.Equal(recipient.Identity, pubKey) {
+				return errors.New(fmt.Sprintf("Bank %v:%v is already shared with %v", serverName, bankName, recipients[i]))
+			}
+		}
+	}
+
+	// the agent does not give out keys, the bank password is always asked
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	keys, err := unlockLocalBankKeys(bank, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	passphrase = "" // passphrase will hopefully be garbage-collected
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+
+	for _, pubKey := range recipientKeys {
+		recipient, err := wrapMasterKeyForRecipient(keyHash, keys.masterKey, pubKey)
+		if err != nil {
+			return err
+		}
+		bank.Recipients = append(bank.Recipients, recipient)
+	}
+	// recipients can only fetch the bank once the server knows them
+	if err := storeBankManifest(bankhome, server, bank, keys); err != nil {
+		return errors.New(fmt.Sprintf("Could not store the recipients of bank %v:%v on the server: %v", serverName, bankName, err))
+	}
+	if err := storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	fmt.Printf("Bank %s:%s is shared with %d recipients. They can fetch it with its address %s\n", serverName, bankName, len(bank.Recipients), bankPubKeyHashB58)
+	return nil
+}
+
+// CallRevokeBank stops sharing a bank with recipients. The server refuses their requests once it is updated, but the
+// master key they already unwrapped is unchanged until the bank is rotated
+func CallRevokeBank(bankhome, serverName, bankName string, recipients []string) error {
+	recipientKeys, err := readRecipientKeys(recipients)
+	if err != nil {
+		return err
+	}
+	server, bank, err := readOwnedBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	var kept []*pb.BankRecipient
+	revoked := make(map[string]bool)
+	for _, recipient := range bank.Recipients {
+		isRevoked := false
+		for _, pubKey := range recipientKeys {
+			if bytes.Equal(recipient.Identity, pubKey) {
+				isRevoked = true
+				revoked[string(pubKey)] = true
+			}
+		}
+		if !isRevoked {
+			kept = append(kept, recipient)
+		}
+	}
+	for i, pubKey := range recipientKeys {
+		if !revoked[string(pubKey)] {
+			return errors.New(fmt.Sprintf("Bank %v:%v is not shared with %v", serverName, bankName, recipients[i]))
+		}
+	}
+	bank.Recipients = kept
+
+	// unlock bank keys with the agent or the bank password
+	keys, err := unlockBank(bankhome, bank)
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	if err := storeBankManifest(bankhome, server, bank, keys); err != nil {
+		return errors.New(fmt.Sprintf("Could not store the recipients of bank %v:%v on the server: %v", serverName, bankName, err))
+	}
+	if err := storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName); err != nil {
+		return err
+	}
+	fmt.Printf("Bank %s:%s is no longer shared with %d recipients. Rotate it with 'bank rotate' to change the master key they had\n", serverName, bankName, len(recipientKeys))
+	return nil
+}
+
+// CallFetchBank writes the descriptor of a bank shared with the user, from its address and the manifest stored on the
+// server. Fetching a bank already shared with the user updates it with the files pushed since, or with its new address
+// once it was rotated
+func CallFetchBank(bankhome, serverName, bankName, address string) error {
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+	bankExists, err := storage.Client_BankExists(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	var oldBank *pb.ClientBankDescriptor
+	if bankExists {
+		if oldBank, err = storage.Client_ReadBankDescriptor(bankhome, serverName, bankName); err != nil {
+			return err
+		}
+		if !isSharedBank(oldBank) {
+			return errors.New(fmt.Sprintf("Bank %v:%v already exists", serverName, bankName))
+		}
+	}
+
+	identity, err := unlockIdentity(bankhome)
+	if err != nil {
+		return err
+	}
+	keys := &recipientBankKeys{identity: identity, fileKeys: &fileKeys{}}
+	defer keys.close()
+	result, err := callBankManifest(bankhome, server, keys, address, nil, nil)
+	if err != nil {
+		return err
+	}
+	// the server could send the key of another bank
+	if keys.bankPubKey, err = cr.ImportPublicKey(result.PubKey); err != nil {
+		return err
+	}
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+	if bankPubKeyHashB58 != address {
+		return errors.New("Server sent the key of another bank")
+	}
+	// the manifest and the recipients must have been stored by the bank key, not by the server
+	bankSignedMsg := &pb.SignManifestRequestClient{
+		Nonce:      result.Nonce,
+		PubKeyAddr: address,
+		Manifest:   result.Manifest,
+		Recipients: result.Recipients,
+	}
+	if err := cr.VerifySignature(bankSignedMsg, keys.bankPubKey, result.Signature); err != nil {
+		return errors.New("Manifest of the bank is not signed by its key, its owner must push again")
+	}
+	if keys.masterKey, err = unwrapRecipientMasterKey(keyHash, result.Recipients, identity); err != nil {
+		return err
+	}
+	bank, err := openBankManifest(result.Manifest, keyHash, keys.fileKeys)
+	if err != nil {
+		return err
+	}
+	// files pushed after the manifest was stored could not be decrypted
+	if bank.Nbfiles != result.Nbfiles {
+		return errors.New(fmt.Sprintf("Manifest lists %v files, but the bank has %v files on the server, its owner must push again", bank.Nbfiles, result.Nbfiles))
+	}
+	// only the same bank, or the bank it was rotated to, replaces the bank already fetched
+	if oldBank != nil && !bytes.Equal(oldBank.BankPubKey, result.PubKey) && (bank.Handover == nil || !bytes.Equal(bank.Handover.OldPubKey, oldBank.BankPubKey)) {
+		return errors.New(fmt.Sprintf("Bank %v:%v already exists", serverName, bankName))
+	}
+	bank.BankPubKey = result.PubKey
+	bank.Recipients = result.Recipients
+
+	if bankExists {
+		err = storage.Client_UpdateBankDescriptor(bankhome, bank, serverName, bankName)
+	} else {
+		err = storage.Client_WriteBankDescriptor(bankhome, bank, serverName, bankName)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Bank %s:%s has been fetched with its %d files\n", serverName, bankName, bank.Nbfiles)
+	return nil
+}
@@ -0,0 +1,401 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// CallRotateBank downloads and decrypts all the files of a bank, encrypts them again under a new master key, and
+// hands the bank over to a new key pair on the server. The new private key and master key are protected by a new
+// password, read from newSource, and derived with params. Files are encrypted with cipherSuite, or with the cipher
+// suite of the bank when nil. Banks of descriptor v1 are upgraded to v2. Unlike uploads, the files encrypted again are
+// staged on disk, one at a time as they are downloaded, as the handover signs the merkle root of the new files before
+// they are sent.
+func CallRotateBank(bankhome, serverName, bankName string, newSource *cr.PassphraseSource, params cr.KDFParams, cipherSuite *cr.CipherSuite) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+	// import server pubkey
+	serverPubKey, err := cr.ImportPublicKey(server.PubKey)
+	if err != nil {
+		return err
+	}
+
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+	if bank.Nbfiles < 1 {
+		return errors.New(fmt.Sprintf("Bank %v:%v has no files", serverName, bankName))
+	}
+	suite := cr.CipherSuite(bank.CipherSuite)
+	if cipherSuite != nil {
+		suite = *cipherSuite
+	}
+	if err := suite.Validate(); err != nil {
+		return err
+	}
+
+	// unlock old bank keys with the agent or the bank password
+	keys, err := unlockBank(bankhome, bank)
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	keyHash, bankPubKeyHashB58, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+	oldPubKey, err := cr.ExportPublicKey(keys.publicKey())
+	if err != nil {
+		return err
+	}
+	newPassphrase, err := readNewPassphrase(newSource, "bank password")
+	if err != nil {
+		return err
+	}
+
+	// generate new key pair and master key
+	newPubKey, newPrivKey, err := cr.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	exportedNewPubKey, err := cr.ExportPublicKey(newPubKey)
+	if err != nil {
+		return err
+	}
+	exportedNewPrivKey, err := cr.SafeExportPrivateKey(newPrivKey, newPassphrase)
+	if err != nil {
+		return err
+	}
+	masterKey, err := cr.NewMasterKey()
+	if err != nil {
+		return err
+	}
+	wrappedMasterKey, err := wrapMasterKey(newPassphrase, masterKey, params, suite)
+	if err != nil {
+		return err
+	}
+	newKeys := &localBankKeys{privKey: newPrivKey, fileKeys: &fileKeys{masterKey: masterKey, suite: suite}}
+	defer newKeys.close()
+	newKeyHash := cr.HashOnce(exportedNewPubKey)
+	// the bank stays shared with its recipients, who fetch it again from its new address
+	var recipients []*pb.BankRecipient
+	for _, recipient := range bank.Recipients {
+		newRecipient, err := wrapMasterKeyForRecipient(newKeyHash, masterKey, recipient.Identity)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, newRecipient)
+	}
+
+	// download and verify all files, each is decrypted and encrypted again under the new key as it is received
+	var fileNumbers []int
+	for i := 1; i <= int(bank.Nbfiles); i++ {
+		fileNumbers = append(fileNumbers, i)
+	}
+	var aeskeys [][]byte
+	for _, fileDescriptor := range bank.FileDescriptors {
+		aeskey, err := keys.fileKey(fileDescriptor.Kdf, fileDescriptor.Salt, fileDescriptor.WrappedKey)
+		if err != nil {
+			return err
+		}
+		aeskeys = append(aeskeys, aeskey)
+	}
+	tree := merkle.MerkleTree{
+		Mode:      merkle.TreeMode(bank.TreeMode),
+		Version:   merkle.TreeVersion(bank.TreeVersion),
+		Hash:      cr.HashAlgorithm(bank.HashAlgorithm),
+		ChunkSize: int(bank.ChunkSize),
+	}
+	stagedPaths := make([]string, len(fileNumbers))
+	defer func() {
+		for _, stagedPath := range stagedPaths {
+			if stagedPath != "" {
+				storage.Client_RemoveStagedFile(stagedPath)
+			}
+		}
+	}()
+	fileDescriptors := make([]*pb.FileDescriptor, len(fileNumbers))
+	if _, err := downloadVerifiedFiles(bankhome, server, bank, keys, bankPubKeyHashB58, fileNumbers, func(i int, ciphertext io.Reader) error {
+		fileDescriptor := bank.FileDescriptors[i]
+		aad := fileAAD(keyHash[:], bank.Version, fileDescriptor.Seq, fileDescriptor.Bound)
+		newAAD := fileAAD(newKeyHash[:], pb.DescriptorVersion_DESCRIPTOR_V2, int32(i+1), true)
+		descriptor, err := stageRotatedFile(bankhome, tree, &stagedPaths[i], func(w io.Writer) error {
+			return decryptFile(w, cr.CipherSuite(bank.CipherSuite), ciphertext, aeskeys[i], fileDescriptor.Iv, aad, fileDescriptor.SegmentSize)
+		}, func(w io.Writer, compressed io.Reader) (*pb.FileDescriptor, error) {
+			return encryptCompressedFile(w, newKeys, compressed, fileDescriptor.Compression, nil, newAAD)
+		})
+		if err != nil {
+			return errors.New(fmt.Sprintf("Could not encrypt file %v again: %v", fileDescriptor.Seq, err))
+		}
+		descriptor.Seq = int32(i + 1)
+		descriptor.Name = fileDescriptor.Name
+		fileDescriptors[i] = descriptor
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the handover signs the merkle root of the new files, which are sent once it is
+	builder, err := merkle.NewMerkleTreeBuilder(tree.Mode, tree.Version, tree.Hash, tree.ChunkSize)
+	if err != nil {
+		return err
+	}
+	for _, descriptor := range fileDescriptors {
+		builder.AddContentHash([32]byte(descriptor.ContentHash))
+	}
+	newTree, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	merkleRoot := newTree.GetMerkleRoot()
+
+	conn, client, err := connectToNode(server.Host, bankhome)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background()) // no deadline for transfers, idle streams are cancelled by the connection
+	defer cancel()
+
+	stream, err := client.RotateBank(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp1, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return err
+	}
+
+	var serverNonce []byte
+	switch phase := resp1.Phase.(type) {
+	case *pb.RotateBankResponse_Nonce:
+		serverNonce = phase.Nonce
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// old key hands the bank over to the new key, which signs to prove it is owned by the client
+	handover := &pb.BankHandover{
+		Nonce:      serverNonce,
+		OldPubKey:  oldPubKey,
+		NewPubKey:  exportedNewPubKey,
+		Nbfiles:    bank.Nbfiles,
+		MerkleRoot: merkleRoot[:],
+		Recipients: recipients,
+	}
+	messageToSign := &pb.SignRotateRequestClient{
+		Nonce:      handover.Nonce,
+		OldPubKey:  handover.OldPubKey,
+		NewPubKey:  handover.NewPubKey,
+		Nbfiles:    handover.Nbfiles,
+		MerkleRoot: handover.MerkleRoot,
+		Recipients: handover.Recipients,
+	}
+	if handover.OldSignature, err = keys.sign(messageToSign); err != nil {
+		return err
+	}
+	if handover.NewSignature, err = newKeys.sign(messageToSign); err != nil {
+		return err
+	}
+	clientNonce, err := cr.Random12BytesNonce()
+	if err != nil {
+		return err
+	}
+
+	// keep the new descriptor, the server removes the old bank once the new one is written
+	newBank := &pb.ClientBankDescriptor{
+		PrivKey:         exportedNewPrivKey,
+		Nbfiles:         bank.Nbfiles,
+		MerkleRoot:      merkleRoot[:],
+		FileDescriptors: fileDescriptors,
+		TreeMode:        bank.TreeMode,
+		TreeVersion:     bank.TreeVersion,
+		HashAlgorithm:   bank.HashAlgorithm,
+		ChunkSize:       bank.ChunkSize,
+		Version:         pb.DescriptorVersion_DESCRIPTOR_V2,
+		MasterKey:       wrappedMasterKey,
+		CipherSuite:     pb.CipherSuite(suite),
+		Compression:     bank.Compression,
+		Handover:        handover,
+		Recipients:      recipients,
+	}
+	stagedPath, err := storage.Client_WriteStagedBankDescriptor(bankhome, newBank, serverName, bankName)
+	if err != nil {
+		return err
+	}
+
+	rotatedRoot, err := sendRotatedBank(stream, handover, clientNonce, stagedPaths)
+	if err != nil {
+		return fmt.Errorf("Rotation of bank %s:%s failed: %v\nIf the server completed it, the bank descriptor with the new keys is %v", serverName, bankName, err, stagedPath)
+	}
+
+	// verify nonce
+	if !bytes.Equal(rotatedRoot.Nonce, clientNonce) {
+		return errors.New("Invalid challenge response nonce")
+	}
+	// verify merkle root
+	if !bytes.Equal(rotatedRoot.MerkleRoot, merkleRoot[:]) {
+		return errors.New("Server-side merkle tree different from local")
+	}
+	// verify signature
+	if err := verifyBankHandoverSignature(rotatedRoot, handover, serverPubKey); err != nil {
+		return err
+	}
+
+	// replace the descriptor of the bank
+	newBank.HandoverServerSignature = rotatedRoot.Signature
+	if _, err := storage.Client_WriteStagedBankDescriptor(bankhome, newBank, serverName, bankName); err != nil {
+		return err
+	}
+	if err := storage.Client_CommitStagedBankDescriptor(bankhome, serverName, bankName); err != nil {
+		return err
+	}
+	refreshBankManifest(bankhome, server, newBank, newKeys)
+	fmt.Printf("Bank %s:%s has been rotated to new keys, its %d files were encrypted again with %v\n", serverName, bankName, bank.Nbfiles, suite)
+	return nil
+}
+
+// sends the handover and the staged files encrypted again, and returns the signed root of the new bank
+func sendRotatedBank(stream pb.FileBankService_RotateBankClient, handover *pb.BankHandover, clientNonce []byte, stagedPaths []string) (*pb.RotatedRoot, error) {
+	if err := stream.Send(&pb.RotateBankRequest{
+		Phase: &pb.RotateBankRequest_RotateReq{
+			RotateReq: &pb.RotateRequest{
+				Handover:    handover,
+				ClientNonce: clientNonce,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	// send files as parts as they are read, and close the stream after the last one
+	sendPart := func(part *pb.FileMessage) error {
+		return stream.Send(&pb.RotateBankRequest{
+			Phase: &pb.RotateBankRequest_File{
+				File: part,
+			},
+		})
+	}
+	for i, stagedPath := range stagedPaths {
+		if err := sendStagedFile(sendPart, int32(i+1), stagedPath); err != nil {
+			return nil, err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	// receive signed response
+	resp, err := stream.Recv()
+	if err == io.EOF {
+		return nil, errors.New("Connexion closed by server")
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch phase := resp.Phase.(type) {
+	case *pb.RotateBankResponse_RotatedRoot:
+		return phase.RotatedRoot, nil
+	default:
+		return nil, errors.New("Invalid message type")
+	}
+}
+
+// stages a file of the bank encrypted again, which is decrypted by decrypt and encrypted by encrypt at the same time,
+// and hashed with the parameters of tree. Files keep their compression, they are never decompressed. The path of the
+// staged file is set as soon as it is created, so that it is removed even if staging fails. Returns the descriptor of
+// the file, with its content hash
+func stageRotatedFile(bankhome string, tree merkle.MerkleTree, stagedPath *string, decrypt func(io.Writer) error, encrypt func(io.Writer, io.Reader) (*pb.FileDescriptor, error)) (*pb.FileDescriptor, error) {
+	hasher, err := tree.NewContentHasher()
+	if err != nil {
+		return nil, err
+	}
+	file, err := storage.Client_CreateStagedFile(bankhome)
+	if err != nil {
+		return nil, err
+	}
+	*stagedPath = file.Name()
+	defer file.Close()
+	staged := bufio.NewWriter(file)
+
+	reader, writer := io.Pipe()
+	decrypted := make(chan error, 1)
+	go func() {
+		err := decrypt(writer)
+		writer.CloseWithError(err)
+		decrypted <- err
+	}()
+	descriptor, err := encrypt(io.MultiWriter(staged, hasher), reader)
+	reader.CloseWithError(err)
+	if decryptErr := <-decrypted; decryptErr != nil && decryptErr != io.ErrClosedPipe {
+		return nil, decryptErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := staged.Flush(); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	contentHash := hasher.Sum()
+	descriptor.ContentHash = contentHash[:]
+	return descriptor, nil
+}
+
+// sends the staged file at stagedPath as the parts of file seq, as it is read
+func sendStagedFile(send func(*pb.FileMessage) error, seq int32, stagedPath string) error {
+	file, err := os.Open(stagedPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := storage.NewFileMessageWriter(send, seq)
+	if _, err := io.Copy(writer, bufio.NewReader(file)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func verifyBankHandoverSignature(resp *pb.RotatedRoot, handover *pb.BankHandover, pubKey ed25519.PublicKey) error {
+	signedMessage := &pb.SignBankHandoverServer{
+		Nonce:      resp.Nonce,
+		OldPubKey:  handover.OldPubKey,
+		NewPubKey:  handover.NewPubKey,
+		MerkleRoot: resp.MerkleRoot,
+	}
+	return cr.VerifySignature(signedMessage, pubKey, resp.Signature)
+}
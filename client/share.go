@@ -0,0 +1,262 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// The private key and master key of a bank can be split into Shamir shares, each encrypted with HPKE to the identity
+// key of a member of the team. Any threshold of the shares rebuild the bank, with the manifest stored on the server.
+
+var bankShareInfo = []byte("merkle-filebank bank share")
+
+// the shared secret is the seed of the private key followed by the master key
+const bankShareSecretSize = ed25519.SeedSize + 32
+
+// CallSplitBank splits the keys of a bank into one share per recipient, any threshold of which rebuild the bank, and
+// writes the shares to outDir. Recipients are identity public keys. The manifest of the bank is stored on the server
+func CallSplitBank(bankhome, serverName, bankName string, threshold int, recipients []string, outDir string) error {
+	var recipientKeys []ed25519.PublicKey
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		pubKey, err := cr.PublicKeyFromString(recipient)
+		if err != nil {
+			return err
+		}
+		if seen[string(pubKey)] {
+			return errors.New(fmt.Sprintf("Recipient %v is given more than once", recipient))
+		}
+		seen[string(pubKey)] = true
+		recipientKeys = append(recipientKeys, pubKey)
+	}
+
+	// verify that server exists locally
+	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
+		return err
+	} else if !serverExists {
+		return errors.New(fmt.Sprintf("Server %v does not exist locally", serverName))
+	}
+	server, err := storage.Client_ReadServerDescriptor(bankhome, serverName)
+	if err != nil {
+		return err
+	}
+	bank, err := readLocalBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+	if err := requireBankOwner(bank, serverName, bankName); err != nil {
+		return err
+	}
+	if bank.Version != pb.DescriptorVersion_DESCRIPTOR_V2 {
+		return errors.New(fmt.Sprintf("Bank %v:%v has no master key and cannot be split, upgrade it with 'bank rotate'", serverName, bankName))
+	}
+
+	// the agent does not give out keys, the bank password is always asked
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	keys, err := unlockLocalBankKeys(bank, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+	defer keys.close()
+	passphrase = "" // passphrase will hopefully be garbage-collected
+	keyHash, _, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+
+	secret := append(append(make([]byte, 0, bankShareSecretSize), keys.privKey.Seed()...), keys.masterKey...)
+	defer clear(secret)
+	shares, err := cr.SplitSecret(secret, len(recipientKeys), threshold)
+	if err != nil {
+		return err
+	}
+
+	var bankShares []*pb.BankShare
+	for i, recipient := range recipientKeys {
+		header := &pb.BankShareHeader{
+			BankKeyHash: keyHash[:],
+			Threshold:   int32(threshold),
+			Nbshares:    int32(len(recipientKeys)),
+			Recipient:   recipient,
+		}
+		bankShare, err := sealBankShare(header, shares[i])
+		clear(shares[i])
+		if err != nil {
+			return err
+		}
+		bankShares = append(bankShares, bankShare)
+	}
+
+	if err := storeBankManifest(bankhome, server, bank, keys); err != nil {
+		return errors.New(fmt.Sprintf("Could not store the manifest of bank %v:%v on the server: %v", serverName, bankName, err))
+	}
+	for i, bankShare := range bankShares {
+		sharePath := fmt.Sprintf("%s/%s_%s.share%d", outDir, serverName, bankName, i+1)
+		if err := storage.WriteBankShare(sharePath, bankShare); err != nil {
+			return err
+		}
+		fmt.Printf("Share %d for %s written to %s\n", i+1, recipients[i], sharePath)
+	}
+	fmt.Printf("Any %d of the %d shares rebuild bank %s:%s with 'bank combine'\n", threshold, len(bankShares), serverName, bankName)
+	return nil
+}
+
+// CallReshareBank encrypts a share received by the user again to the identity key of another user, who can combine it
+func CallReshareBank(bankhome, sharePath, recipient, outPath string) error {
+	recipientKey, err := cr.PublicKeyFromString(recipient)
+	if err != nil {
+		return err
+	}
+	bankShare, err := storage.ReadBankShare(sharePath)
+	if err != nil {
+		return err
+	}
+	identity, err := unlockIdentity(bankhome)
+	if err != nil {
+		return err
+	}
+	defer clear(identity)
+	header, share, err := openBankShare(bankShare, identity)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Could not open share %v: %v", sharePath, err))
+	}
+	defer clear(share)
+
+	header.Recipient = recipientKey
+	if bankShare, err = sealBankShare(header, share); err != nil {
+		return err
+	}
+	if err := storage.WriteBankShare(outPath, bankShare); err != nil {
+		return err
+	}
+	fmt.Printf("Share for %s written to %s\n", recipient, outPath)
+	return nil
+}
+
+// CallCombineBank rebuilds the keys of a bank from its shares, all encrypted to the identity key of the user, and
+// writes the descriptor of the bank from the manifest stored on the server. The keys are protected by a new password,
+// read from newSource, and derived with params
+func CallCombineBank(bankhome, serverName, bankName string, sharePaths []string, newSource *cr.PassphraseSource, params cr.KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if len(sharePaths) < 2 {
+		return errors.New("At least 2 shares are required")
+	}
+	server, err := readServerOfNewBank(bankhome, serverName, bankName)
+	if err != nil {
+		return err
+	}
+
+	var bankShares []*pb.BankShare
+	for _, sharePath := range sharePaths {
+		bankShare, err := storage.ReadBankShare(sharePath)
+		if err != nil {
+			return err
+		}
+		bankShares = append(bankShares, bankShare)
+	}
+	identity, err := unlockIdentity(bankhome)
+	if err != nil {
+		return err
+	}
+	defer clear(identity)
+
+	var first *pb.BankShareHeader
+	var shares [][]byte
+	defer func() {
+		for _, share := range shares {
+			clear(share)
+		}
+	}()
+	for i, bankShare := range bankShares {
+		header, share, err := openBankShare(bankShare, identity)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Could not open share %v: %v", sharePaths[i], err))
+		}
+		shares = append(shares, share)
+		if first == nil {
+			first = header
+		} else if !bytes.Equal(header.BankKeyHash, first.BankKeyHash) || header.Threshold != first.Threshold || header.Nbshares != first.Nbshares {
+			return errors.New(fmt.Sprintf("Share %v is not a share of the same bank as %v", sharePaths[i], sharePaths[0]))
+		}
+	}
+	if len(shares) < int(first.Threshold) {
+		return errors.New(fmt.Sprintf("%v shares are required, got %v", first.Threshold, len(shares)))
+	}
+
+	secret, err := cr.CombineShares(shares)
+	if err != nil {
+		return err
+	}
+	defer clear(secret)
+	if len(secret) != bankShareSecretSize {
+		return errors.New("Invalid shares length")
+	}
+	keys := &localBankKeys{
+		privKey:  ed25519.NewKeyFromSeed(secret[:ed25519.SeedSize]),
+		fileKeys: &fileKeys{masterKey: append([]byte{}, secret[ed25519.SeedSize:]...)},
+	}
+	defer keys.close()
+	keyHash, _, err := bankKeyHash(keys)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(keyHash[:], first.BankKeyHash) {
+		return errors.New("Shares do not rebuild the key of the bank")
+	}
+	return restoreBank(bankhome, server, serverName, bankName, keys, newSource, params)
+}
+
+// encrypts a share to the recipient of its header, the header being authenticated with the share
+func sealBankShare(header *pb.BankShareHeader, share []byte) (*pb.BankShare, error) {
+	recipient, err := cr.X25519PublicKey(header.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, err := proto.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	enc, ciphertext, err := cr.HPKESeal(recipient, bankShareInfo, headerBytes, share)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BankShare{
+		Header:     headerBytes,
+		Enc:        enc,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// decrypts a share encrypted to the identity key
+func openBankShare(bankShare *pb.BankShare, identity ed25519.PrivateKey) (*pb.BankShareHeader, []byte, error) {
+	header := &pb.BankShareHeader{}
+	if err := proto.Unmarshal(bankShare.Header, header); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(header.Recipient, identity.Public().(ed25519.PublicKey)) {
+		return nil, nil, errors.New(fmt.Sprintf("share is encrypted to %v, its owner can forward it with 'bank reshare'", cr.PublicKeyToString(header.Recipient)))
+	}
+	x25519Identity, err := cr.X25519PrivateKey(identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	share, err := cr.HPKEOpen(x25519Identity, bankShare.Enc, bankShareInfo, bankShare.Header, bankShare.Ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, share, nil
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"time"
 
 	cr "github.com/oteffahi/merkle-filebank/cryptography"
 	"github.com/oteffahi/merkle-filebank/merkle"
@@ -16,10 +15,22 @@ import (
 	"github.com/oteffahi/merkle-filebank/storage"
 )
 
-func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string) error {
+func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string, treeMode pb.TreeMode, treeVersion pb.TreeVersion, hashAlgorithm pb.HashAlgorithm, chunkSize int32, kdfParams cr.KDFParams, cipherSuite cr.CipherSuite, compression pb.Compression) error {
 	if len(filepaths) == 0 {
 		return errors.New("Files list is empty")
 	}
+	if chunkSize < 0 {
+		return errors.New("Chunk size cannot be negative")
+	}
+	if chunkSize > 0 && !merkle.TreeMode(treeMode).IsIndexed() {
+		return errors.New("Chunked files require an indexed tree")
+	}
+	if err := kdfParams.Validate(); err != nil {
+		return err
+	}
+	if err := cipherSuite.Validate(); err != nil {
+		return err
+	}
 
 	// verify that server exists locally
 	if serverExists, err := storage.Client_ServerExists(bankhome, serverName); err != nil {
@@ -45,8 +56,8 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 		return errors.New(fmt.Sprintf("Bank %v:%v already exists", serverName, bankName))
 	}
 
-	// read files
-	names, files, err := storage.ReadFilesFromPaths(filepaths)
+	// files are read when they are sent
+	names, err := storage.FileNamesFromPaths(filepaths)
 	if err != nil {
 		return err
 	}
@@ -67,30 +78,29 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 		return err
 	}
 
-	// encrypt files
-	fileDescriptors := []*pb.FileDescriptor{}
-	encFiles := [][]byte{}
-	for i := 0; i < len(names); i++ {
-		encryptedFile, salt, iv, err := cr.EncryptData(files[i], passphrase)
-		if err != nil {
-			return err
-		}
-		descriptor := &pb.FileDescriptor{
-			Seq:  int32(i + 1),
-			Name: names[i],
-			Salt: salt,
-			Iv:   iv,
-		}
-		encFiles = append(encFiles, encryptedFile)
-		fileDescriptors = append(fileDescriptors, descriptor)
+	tree := merkle.MerkleTree{
+		Mode:      merkle.TreeMode(treeMode),
+		Version:   merkle.TreeVersion(treeVersion),
+		Hash:      cr.HashAlgorithm(hashAlgorithm),
+		ChunkSize: int(chunkSize),
+	}
+	builder, err := merkle.NewMerkleTreeBuilder(tree.Mode, tree.Version, tree.Hash, tree.ChunkSize)
+	if err != nil {
+		return err
 	}
 
-	// generate merkle tree for files
-	var tree merkle.MerkleTree
-	if err = tree.BuildMerkleTree(encFiles); err != nil {
+	// generate master key, only wrapping it runs the KDF
+	masterKey, err := cr.NewMasterKey()
+	if err != nil {
+		return err
+	}
+	wrappedMasterKey, err := wrapMasterKey(passphrase, masterKey, kdfParams, cipherSuite)
+	if err != nil {
 		return err
 	}
-	merkleRoot := tree.GetMerkleRoot()
+
+	keyHash := cr.HashOnce(exportedPubKey)
+	keys := &localBankKeys{privKey: privKey, fileKeys: &fileKeys{masterKey: masterKey, suite: cipherSuite}}
 
 	conn, client, err := connectToNode(server.Host, bankhome)
 	if err != nil {
@@ -98,7 +108,7 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 	}
 	defer conn.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // 30s timeout because of file manipulations on server
+	ctx, cancel := context.WithCancel(context.Background()) // no deadline for transfers, idle streams are cancelled by the connection
 	defer cancel()
 
 	stream, err := client.UploadFiles(ctx)
@@ -121,11 +131,15 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 
 	// sign request
 	messageToSign := &pb.SignUploadRequestClient{
-		Nonce:   serverNonce,
-		PubKey:  exportedPubKey,
-		Nbfiles: int32(len(filepaths)),
-	}
-	sign, err := cr.SignMessage(messageToSign, privKey)
+		Nonce:         serverNonce,
+		PubKey:        exportedPubKey,
+		Nbfiles:       int32(len(filepaths)),
+		TreeMode:      treeMode,
+		TreeVersion:   treeVersion,
+		HashAlgorithm: hashAlgorithm,
+		ChunkSize:     chunkSize,
+	}
+	sign, err := keys.sign(messageToSign)
 	if err != nil {
 		return err
 	}
@@ -134,10 +148,14 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 	req1 := &pb.UploadFilesRequest{
 		Phase: &pb.UploadFilesRequest_SignedResp{
 			SignedResp: &pb.ChallengeResponse{
-				Nonce:     serverNonce,
-				Pubkey:    exportedPubKey,
-				Nbfiles:   int32(len(filepaths)),
-				Signature: sign,
+				Nonce:         serverNonce,
+				Pubkey:        exportedPubKey,
+				Nbfiles:       int32(len(filepaths)),
+				Signature:     sign,
+				TreeMode:      treeMode,
+				TreeVersion:   treeVersion,
+				HashAlgorithm: hashAlgorithm,
+				ChunkSize:     chunkSize,
 			},
 		},
 	}
@@ -145,20 +163,33 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 		return err
 	}
 
-	// Send files
-	for i, file := range encFiles {
-		req2 := &pb.UploadFilesRequest{
+	// encrypt and send files as they are read, and hash them for the merkle tree
+	sendPart := func(part *pb.FileMessage) error {
+		return stream.Send(&pb.UploadFilesRequest{
 			Phase: &pb.UploadFilesRequest_File{
-				File: &pb.FileMessage{
-					Seq:     int32(i + 1),
-					Content: file,
-				},
+				File: part,
 			},
-		}
-		if err := stream.Send(req2); err != nil {
+		})
+	}
+	fileDescriptors := []*pb.FileDescriptor{}
+	for i, path := range filepaths {
+		aad := fileAAD(keyHash[:], pb.DescriptorVersion_DESCRIPTOR_V2, int32(i+1), true)
+		descriptor, err := sendEncryptedFile(sendPart, tree, int32(i+1), func(w io.Writer) (*pb.FileDescriptor, error) {
+			return encryptFileFromPath(w, keys, path, compression, nil, aad)
+		})
+		if err != nil {
 			return err
 		}
+		descriptor.Name = names[i]
+		builder.AddContentHash([32]byte(descriptor.ContentHash))
+		fileDescriptors = append(fileDescriptors, descriptor)
+	}
+	localTree, err := builder.Build()
+	if err != nil {
+		return err
 	}
+	merkleRoot := localTree.GetMerkleRoot()
+
 	// send Nonce
 	clientNonce, err := cr.Random12BytesNonce()
 	if err != nil {
@@ -208,14 +239,44 @@ func CallUploadFiles(bankhome, serverName, bankName string, filepaths []string)
 		Nbfiles:         int32(len(filepaths)),
 		MerkleRoot:      signedResponse.MerkleRoot,
 		FileDescriptors: fileDescriptors,
+		TreeMode:        treeMode,
+		TreeVersion:     treeVersion,
+		HashAlgorithm:   hashAlgorithm,
+		ChunkSize:       chunkSize,
+		Version:         pb.DescriptorVersion_DESCRIPTOR_V2,
+		MasterKey:       wrappedMasterKey,
+		CipherSuite:     pb.CipherSuite(cipherSuite),
+		Compression:     compression,
 	}
 	if err := storage.Client_WriteBankDescriptor(bankhome, bankDescriptor, serverName, bankName); err != nil {
 		return err // TODO: maybe try to store somewhere else to save the filebank
 	}
+	refreshBankManifest(bankhome, server, bankDescriptor, keys)
 	fmt.Printf("Bank %s:%s has been succesfully created and uploaded\n", serverName, bankName)
 	return nil
 }
 
+// sends file seq as parts as encrypt writes its ciphertext, which is hashed with the parameters of tree. Returns the
+// descriptor of the file, with its number and content hash
+func sendEncryptedFile(send func(*pb.FileMessage) error, tree merkle.MerkleTree, seq int32, encrypt func(io.Writer) (*pb.FileDescriptor, error)) (*pb.FileDescriptor, error) {
+	hasher, err := tree.NewContentHasher()
+	if err != nil {
+		return nil, err
+	}
+	writer := storage.NewFileMessageWriter(send, seq)
+	descriptor, err := encrypt(io.MultiWriter(hasher, writer))
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	contentHash := hasher.Sum()
+	descriptor.Seq = seq
+	descriptor.ContentHash = contentHash[:]
+	return descriptor, nil
+}
+
 func verifyMerkleRootSignature(resp *pb.MerkleRoot, pubKey ed25519.PublicKey) error {
 	signedMessage := &pb.SignMerkleRootServer{
 		Nonce:      resp.Nonce,
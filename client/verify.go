@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// VerifySavedProof verifies a file against a saved proof without contacting the server. The root is either given in hex,
+// or read from a local bank. Plaintext files are encrypted again with the parameters of the proof before verification.
+func VerifySavedProof(bankhome, proofPath, filePath, rootHex, serverName, bankName string, plaintext bool) error {
+	savedProof, err := storage.ReadSavedProof(proofPath)
+	if err != nil {
+		return err
+	}
+
+	var bank *pb.ClientBankDescriptor
+	if serverName != "" || bankName != "" {
+		if bankExist, err := storage.Client_BankExists(bankhome, serverName, bankName); err != nil {
+			return err
+		} else if !bankExist {
+			return errors.New(fmt.Sprintf("Bank %v:%v does not exist", serverName, bankName))
+		}
+		bank, err = storage.Client_ReadBankDescriptor(bankhome, serverName, bankName)
+		if err != nil {
+			return err
+		}
+		if bank.TreeMode != savedProof.TreeMode || bank.TreeVersion != savedProof.TreeVersion ||
+			bank.HashAlgorithm != savedProof.HashAlgorithm || bank.ChunkSize != savedProof.ChunkSize {
+			return errors.New(fmt.Sprintf("Proof was not saved from bank %v:%v, tree parameters differ", serverName, bankName))
+		}
+	}
+
+	// root to verify against
+	var root []byte
+	if rootHex != "" {
+		if root, err = hex.DecodeString(rootHex); err != nil {
+			return errors.New(fmt.Sprintf("Invalid merkle root: %v", err))
+		}
+	} else if bank != nil {
+		root = bank.MerkleRoot
+	} else {
+		return errors.New("A merkle root or a bank is required to verify a proof")
+	}
+	if len(root) != 32 {
+		return errors.New("Invalid merkle root format")
+	}
+	if len(savedProof.MerkleRoot) == 32 && [32]byte(savedProof.MerkleRoot) != [32]byte(root) {
+		return errors.New(fmt.Sprintf("Proof was saved for merkle root %x, not %x", savedProof.MerkleRoot, root))
+	}
+
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	if plaintext {
+		if file, err = encryptForVerification(file, savedProof, bank); err != nil {
+			return err
+		}
+	}
+
+	merkleProof := merkle.MerkleProof{
+		Mode:      merkle.TreeMode(savedProof.TreeMode),
+		Version:   merkle.TreeVersion(savedProof.TreeVersion),
+		Hash:      cr.HashAlgorithm(savedProof.HashAlgorithm),
+		ChunkSize: int(savedProof.ChunkSize),
+		Index:     int(savedProof.LeafIndex),
+		TreeSize:  int(savedProof.TreeSize),
+	}
+	for _, hash := range savedProof.Hashes {
+		if len(hash) != 32 {
+			return errors.New("Invalid merkle proof format")
+		}
+		merkleProof.Hashes = append(merkleProof.Hashes, [32]byte(hash))
+	}
+	if !merkleProof.VerifyFileProof(file, [32]byte(root)) {
+		if plaintext {
+			return errors.New("Invalid merkle proof, or wrong bank password")
+		}
+		return errors.New("Invalid merkle proof")
+	}
+
+	if merkle.TreeMode(savedProof.TreeMode).IsIndexed() {
+		fmt.Printf("Verified %s as file %d (%s) of merkle root %x\n", filePath, savedProof.FileNumber, savedProof.FileName, root)
+	} else {
+		fmt.Printf("Verified %s as a file of merkle root %x\n", filePath, root)
+	}
+	return nil
+}
+
+// encrypts a plaintext with the key and iv of the file it was downloaded as
+func encryptForVerification(plaintext []byte, savedProof *pb.SavedProof, bank *pb.ClientBankDescriptor) ([]byte, error) {
+	if len(savedProof.Salt) == 0 || len(savedProof.Iv) == 0 {
+		return nil, errors.New("Proof has no encryption parameters, verify the ciphertext instead")
+	}
+	// compressing again may not give back the same bytes
+	if savedProof.Compression != pb.Compression_NO_COMPRESSION {
+		return nil, errors.New("File was compressed before encryption, verify the ciphertext instead")
+	}
+	// banks shared with the user have no bank password
+	if bank != nil && isSharedBank(bank) {
+		return nil, errors.New("Bank is shared with you, verify the ciphertext instead")
+	}
+	fmt.Printf("Enter bank password: ")
+	passphrase, err := cr.ReadPassphrase()
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	// password can only be checked when the bank is available
+	if bank != nil {
+		if _, err := cr.SafeImportPrivateKey(bank.PrivKey, []byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("Error occured while decrypting bank key: %v\n", err)
+		}
+	}
+	keys, err := unlockFileKeys([]byte(passphrase), savedProof.DescriptorVersion, savedProof.MasterKey, cr.CipherSuite(savedProof.CipherSuite))
+	passphrase = "" // passphrase will hopefully be garbage-collected
+	if err != nil {
+		return nil, err
+	}
+	aeskey, err := keys.fileKey(savedProof.Kdf, savedProof.Salt, savedProof.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aad := fileAAD(savedProof.BankKeyHash, savedProof.DescriptorVersion, savedProof.FileNumber, savedProof.Bound)
+	return reencryptFile(cr.CipherSuite(savedProof.CipherSuite), plaintext, aeskey, savedProof.Iv, aad, savedProof.SegmentSize)
+}
@@ -3,14 +3,18 @@ package cryptography
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/sha1"
-
-	"golang.org/x/crypto/pbkdf2"
+	"encoding/binary"
+	"errors"
 )
 
+// EncryptData encrypts with a key derived by PBKDF2, as done for banks created before KDF parameters were stored
 func EncryptData(data []byte, passphrase []byte) (ct, salt, iv []byte, err error) {
-	// generate random parameters
-	salt, err = randomBytes(8)
+	return EncryptDataWithKDF(data, passphrase, KDFParams{KDF: PBKDF2})
+}
+
+func EncryptDataWithKDF(data []byte, passphrase []byte, params KDFParams) (ct, salt, iv []byte, err error) {
+	// derive key from passphrase and random salt
+	derivedKey, salt, err := params.NewKey(passphrase, AES128GCM.KeySize())
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -19,23 +23,30 @@ func EncryptData(data []byte, passphrase []byte) (ct, salt, iv []byte, err error
 		return nil, nil, nil, err
 	}
 
-	// derive key from passphrase
-	derivedKey := DeriveKey(passphrase, salt)
+	ct, err = EncryptDataWithKey(data, derivedKey, iv)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ct, salt, iv, nil
+}
 
+// EncryptDataWithKey encrypts with known parameters, giving back the ciphertext produced by EncryptData
+func EncryptDataWithKey(data, aeskey, iv []byte) ([]byte, error) {
 	// create instance of cipher
-	blockCipher, err := aes.NewCipher(derivedKey)
+	blockCipher, err := aes.NewCipher(aeskey)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 	aesgcm, err := cipher.NewGCM(blockCipher)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
+	}
+	if len(iv) != aesgcm.NonceSize() {
+		return nil, errors.New("invalid iv length")
 	}
 
 	// encrypt
-	ct = aesgcm.Seal(nil, iv, data, nil)
-
-	return ct, salt, iv, nil
+	return aesgcm.Seal(nil, iv, data, nil), nil
 }
 
 func DecryptData(data, aeskey, iv []byte) ([]byte, error) {
@@ -58,7 +69,29 @@ func DecryptData(data, aeskey, iv []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
-func DeriveKey(passphrase, salt []byte) []byte {
-	// derive key from passphrase with salt
-	return pbkdf2.Key(passphrase, salt, 4096, 16, sha1.New)
+// DecryptDataRange decrypts part of a ciphertext produced by EncryptData, ctRange starting at offset.
+// The GCM tag is not checked: the range must be authenticated by other means, such as a merkle proof of the ciphertext.
+func DecryptDataRange(ctRange, aeskey, iv []byte, offset int64) ([]byte, error) {
+	if len(iv) != 12 {
+		return nil, errors.New("invalid iv length")
+	}
+	if offset < 0 {
+		return nil, errors.New("invalid offset")
+	}
+	blockCipher, err := aes.NewCipher(aeskey)
+	if err != nil {
+		return nil, err
+	}
+	// GCM encrypts block i of the plaintext with counter i+2
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, iv)
+	binary.BigEndian.PutUint32(counter[12:], uint32(offset/aes.BlockSize+2))
+	stream := cipher.NewCTR(blockCipher, counter)
+	// skip beginning of first block
+	skip := make([]byte, offset%aes.BlockSize)
+	stream.XORKeyStream(skip, skip)
+
+	plaintext := make([]byte, len(ctRange))
+	stream.XORKeyStream(plaintext, ctRange)
+	return plaintext, nil
 }
@@ -27,3 +27,53 @@ func TestEncryptDecrypt(t *testing.T) {
 		return
 	}
 }
+
+func TestDecryptRange(t *testing.T) {
+	passphrase := []byte("testpassword")
+	var dataToEncrypt []byte
+	for i := 0; i < 1000; i++ {
+		dataToEncrypt = append(dataToEncrypt, byte(i))
+	}
+
+	encryptedData, salt, iv, err := EncryptData(dataToEncrypt, passphrase)
+	if err != nil {
+		t.Errorf("Error occured during encrypton: %v", err)
+		return
+	}
+
+	key := DeriveKey(passphrase, salt)
+	for _, r := range [][2]int{{0, 1000}, {0, 16}, {5, 1}, {15, 2}, {16, 300}, {999, 1}, {333, 667}} {
+		start, end := r[0], r[0]+r[1]
+		decryptedRange, err := DecryptDataRange(encryptedData[start:end], key, iv, int64(start))
+		if err != nil {
+			t.Errorf("Error occured during decryption: %v", err)
+			return
+		}
+		if !slices.Equal(decryptedRange, dataToEncrypt[start:end]) {
+			t.Errorf("Decrypted range %v-%v different from original", start, end)
+			return
+		}
+	}
+}
+
+func TestEncryptWithKnownKey(t *testing.T) {
+	passphrase := []byte("testpassword")
+	dataToEncrypt := []byte("DATA TO ENCRYPT")
+
+	encryptedData, salt, iv, err := EncryptData(dataToEncrypt, passphrase)
+	if err != nil {
+		t.Errorf("Error occured during encrypton: %v", err)
+		return
+	}
+	reencryptedData, err := EncryptDataWithKey(dataToEncrypt, DeriveKey(passphrase, salt), iv)
+	if err != nil {
+		t.Errorf("Error occured during encrypton: %v", err)
+		return
+	}
+	if !slices.Equal(reencryptedData, encryptedData) {
+		t.Errorf("Encryption with known key and iv gives a different ciphertext")
+	}
+	if _, err := EncryptDataWithKey(dataToEncrypt, DeriveKey(passphrase, salt), iv[:8]); err == nil {
+		t.Errorf("Encryption should fail with invalid iv")
+	}
+}
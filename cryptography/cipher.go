@@ -0,0 +1,103 @@
+package cryptography
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// CipherSuite is the AEAD encrypting the files of a bank, and wrapping its master key. Banks created before cipher
+// suites use AES-128-GCM
+type CipherSuite int32
+
+const (
+	AES128GCM         CipherSuite = 0
+	AES256GCM         CipherSuite = 1
+	XChaCha20Poly1305 CipherSuite = 2
+)
+
+func (c CipherSuite) Validate() error {
+	switch c {
+	case AES128GCM, AES256GCM, XChaCha20Poly1305:
+		return nil
+	default:
+		return fmt.Errorf("unknown cipher suite %v", int32(c))
+	}
+}
+
+func (c CipherSuite) KeySize() int {
+	switch c {
+	case AES256GCM:
+		return 32
+	case XChaCha20Poly1305:
+		return chacha20poly1305.KeySize
+	default:
+		return 16
+	}
+}
+
+func (c CipherSuite) NewAEAD(key []byte) (cipher.AEAD, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if len(key) != c.KeySize() {
+		return nil, fmt.Errorf("invalid key length for %v", c)
+	}
+	if c == XChaCha20Poly1305 {
+		return chacha20poly1305.NewX(key)
+	}
+	blockCipher, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(blockCipher)
+}
+
+// WrapKey encrypts a key with a key encryption key, the random nonce is prepended to the wrapped key
+func (c CipherSuite) WrapKey(kek, key []byte) ([]byte, error) {
+	return c.Seal(kek, key, nil)
+}
+
+func (c CipherSuite) UnwrapKey(kek, wrappedKey []byte) ([]byte, error) {
+	return c.Open(kek, wrappedKey, nil)
+}
+
+// Seal encrypts plaintext bound to aad, the random nonce is prepended to the ciphertext
+func (c CipherSuite) Seal(key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := c.NewAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func (c CipherSuite) Open(key, ciphertext, aad []byte) ([]byte, error) {
+	aead, err := c.NewAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], aad)
+}
+
+func (c CipherSuite) String() string {
+	switch c {
+	case AES128GCM:
+		return "aes-128-gcm"
+	case AES256GCM:
+		return "aes-256-gcm"
+	case XChaCha20Poly1305:
+		return "xchacha20-poly1305"
+	default:
+		return fmt.Sprintf("unknown cipher suite %d", int32(c))
+	}
+}
@@ -2,12 +2,16 @@ package cryptography
 
 import (
 	"crypto"
+	"crypto/ecdh"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha512"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"fmt"
 
+	"filippo.io/edwards25519"
 	"github.com/youmark/pkcs8"
 	"google.golang.org/protobuf/proto"
 )
@@ -92,3 +96,43 @@ func VerifySignature(m proto.Message, key ed25519.PublicKey, signature []byte) e
 
 	return nil
 }
+
+// PublicKeyToString encodes a public key in base58, for it to be given to other users
+func PublicKeyToString(key ed25519.PublicKey) string {
+	return Base58Encode(key)
+}
+
+func PublicKeyFromString(key string) (ed25519.PublicKey, error) {
+	decodedKey, err := Base58Decode(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %v: %v", key, err)
+	}
+	if len(decodedKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key %v: wrong length", key)
+	}
+	if _, err := new(edwards25519.Point).SetBytes(decodedKey); err != nil {
+		return nil, fmt.Errorf("invalid public key %v: %v", key, err)
+	}
+	return decodedKey, nil
+}
+
+// X25519PrivateKey converts an ed25519 private key to the X25519 key of the same key pair, so that secrets can be
+// encrypted to the owner of an ed25519 key
+func X25519PrivateKey(key ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid private key length")
+	}
+	// the scalar of the key pair, clamped by X25519
+	hash := sha512.Sum512(key.Seed())
+	defer clear(hash[:])
+	return ecdh.X25519().NewPrivateKey(hash[:32])
+}
+
+// X25519PublicKey converts an ed25519 public key to its X25519 public key, the montgomery form of its point
+func X25519PublicKey(key ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	point, err := new(edwards25519.Point).SetBytes(key)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPublicKey(point.BytesMontgomery())
+}
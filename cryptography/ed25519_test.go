@@ -77,3 +77,36 @@ func TestSignVerify(t *testing.T) {
 		return
 	}
 }
+
+func TestX25519Conversion(t *testing.T) {
+	pubKey, privKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Errorf("Error occured when generating keypair: %v", err)
+		return
+	}
+	x25519PrivKey, err := X25519PrivateKey(privKey)
+	if err != nil {
+		t.Errorf("Error occured when converting private key: %v", err)
+		return
+	}
+	x25519PubKey, err := X25519PublicKey(pubKey)
+	if err != nil {
+		t.Errorf("Error occured when converting public key: %v", err)
+		return
+	}
+	if !x25519PrivKey.PublicKey().Equal(x25519PubKey) {
+		t.Errorf("Converted public key is not the key of the converted private key")
+	}
+
+	decodedPubKey, err := PublicKeyFromString(PublicKeyToString(pubKey))
+	if err != nil {
+		t.Errorf("Error occured when decoding public key: %v", err)
+		return
+	}
+	if !slices.Equal(decodedPubKey, pubKey) {
+		t.Errorf("Decoded public key different from original")
+	}
+	if _, err := PublicKeyFromString("notakey"); err == nil {
+		t.Errorf("Decoding should fail with an invalid key")
+	}
+}
@@ -1,6 +1,92 @@
 package cryptography
 
-import "crypto/sha256"
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+type HashAlgorithm int32
+
+const (
+	SHA256     HashAlgorithm = 0
+	SHA512_256 HashAlgorithm = 1
+	BLAKE2b256 HashAlgorithm = 2
+	// legacy Keccak-256 as used by Ethereum, gives trees that can be verified by OpenZeppelin's MerkleProof
+	Keccak256 HashAlgorithm = 3
+)
+
+// Hasher computes 32 bytes digests with a given algorithm, and is safe for concurrent use.
+// It is a concrete type so that calls are not dynamically dispatched, which lets callers hash buffers allocated on the stack.
+type Hasher struct {
+	algorithm HashAlgorithm
+	newHash   func() hash.Hash
+}
+
+func NewHasher(algorithm HashAlgorithm) (Hasher, error) {
+	switch algorithm {
+	case SHA256:
+		return Hasher{algorithm, sha256.New}, nil
+	case SHA512_256:
+		return Hasher{algorithm, sha512.New512_256}, nil
+	case BLAKE2b256:
+		return Hasher{algorithm, newBlake2b256}, nil
+	case Keccak256:
+		return Hasher{algorithm, sha3.NewLegacyKeccak256}, nil
+	}
+	return Hasher{}, errors.New(fmt.Sprintf("unknown hash algorithm %v", algorithm))
+}
+
+func (h Hasher) HashOnce(data []byte) [32]byte {
+	// one-shot digests do not allocate
+	switch h.algorithm {
+	case SHA256:
+		return sha256.Sum256(data)
+	case SHA512_256:
+		return sha512.Sum512_256(data)
+	case BLAKE2b256:
+		return blake2b.Sum256(data)
+	}
+	digest := h.newHash()
+	// writing data to the digest interface would move it to the heap, even on the paths above
+	digest.Write(append([]byte(nil), data...))
+	return [32]byte(digest.Sum(nil))
+}
+
+func (h Hasher) HashTwice(data []byte) [32]byte {
+	hash := h.HashOnce(data)
+	return h.HashOnce(hash[:])
+}
+
+func (h Hasher) HashOnceFromReader(r io.Reader) ([32]byte, error) {
+	// hash data as it is read, without loading it entirely in memory
+	digest := h.newHash()
+	if _, err := io.Copy(digest, r); err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(digest.Sum(nil)), nil
+}
+
+// New returns a digest of the algorithm, for data that is hashed in several writes
+func (h Hasher) New() hash.Hash {
+	return h.newHash()
+}
+
+func (h Hasher) Algorithm() HashAlgorithm {
+	return h.algorithm
+}
+
+func newBlake2b256() hash.Hash {
+	// only fails for keys longer than 64 bytes
+	digest, _ := blake2b.New256(nil)
+	return digest
+}
 
 func HashTwice(data []byte) [32]byte {
 	hash := sha256.Sum256(data)
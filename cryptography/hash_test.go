@@ -0,0 +1,39 @@
+package cryptography
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHasherVectors(t *testing.T) {
+	// digests of "abc"
+	vectors := map[HashAlgorithm]string{
+		SHA256:     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		SHA512_256: "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23",
+		BLAKE2b256: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		Keccak256:  "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+	}
+	for algorithm, expected := range vectors {
+		hasher, err := NewHasher(algorithm)
+		if err != nil {
+			t.Errorf("Error occured when creating hasher: %v", err)
+			return
+		}
+		digest := hasher.HashOnce([]byte("abc"))
+		if hex.EncodeToString(digest[:]) != expected {
+			t.Errorf("Invalid digest for algorithm %v", algorithm)
+		}
+		fromReader, err := hasher.HashOnceFromReader(bytes.NewReader([]byte("abc")))
+		if err != nil {
+			t.Errorf("Error occured when hashing from reader: %v", err)
+			return
+		}
+		if fromReader != digest {
+			t.Errorf("Digest from reader different from digest of buffer for algorithm %v", algorithm)
+		}
+	}
+	if _, err := NewHasher(HashAlgorithm(42)); err == nil {
+		t.Errorf("NewHasher should return error for unknown algorithm")
+	}
+}
@@ -0,0 +1,116 @@
+package cryptography
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Single-shot HPKE in base mode (RFC 9180), with DHKEM(X25519, HKDF-SHA256), HKDF-SHA256 and ChaCha20-Poly1305. It
+// encrypts secrets to the X25519 key of a recipient, without a key shared beforehand.
+
+const (
+	hpkeKEMX25519HKDFSHA256  = 0x0020
+	hpkeKDFHKDFSHA256        = 0x0001
+	hpkeAEADChaCha20Poly1305 = 0x0003
+	hpkeModeBase             = 0x00
+)
+
+var (
+	hpkeKEMSuiteID = []byte{'K', 'E', 'M', 0x00, hpkeKEMX25519HKDFSHA256}
+	hpkeSuiteID    = []byte{'H', 'P', 'K', 'E', 0x00, hpkeKEMX25519HKDFSHA256, 0x00, hpkeKDFHKDFSHA256, 0x00, hpkeAEADChaCha20Poly1305}
+)
+
+// HPKESeal encrypts plaintext to the key of the recipient, bound to info and aad. Returns the encapsulated key, that
+// the recipient needs along with the ciphertext
+func HPKESeal(recipient *ecdh.PublicKey, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hpkeSeal(ephemeral, recipient, info, aad, plaintext)
+}
+
+func hpkeSeal(ephemeral *ecdh.PrivateKey, recipient *ecdh.PublicKey, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+	dh, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc = ephemeral.PublicKey().Bytes()
+	key, nonce, err := hpkeKeySchedule(dh, enc, recipient.Bytes(), info)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func HPKEOpen(recipient *ecdh.PrivateKey, enc, info, aad, ciphertext []byte) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().NewPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := recipient.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	key, nonce, err := hpkeKeySchedule(dh, enc, recipient.PublicKey().Bytes(), info)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, errors.New("could not decrypt, wrong recipient key")
+	}
+	return plaintext, nil
+}
+
+// derives the key and nonce of the first message from the shared secret of the KEM
+func hpkeKeySchedule(dh, enc, recipient, info []byte) (key, nonce []byte, err error) {
+	kemContext := append(append([]byte{}, enc...), recipient...)
+	eaePRK := hpkeLabeledExtract(hpkeKEMSuiteID, nil, "eae_prk", dh)
+	sharedSecret, err := hpkeLabeledExpand(hpkeKEMSuiteID, eaePRK, "shared_secret", kemContext, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pskIDHash := hpkeLabeledExtract(hpkeSuiteID, nil, "psk_id_hash", nil)
+	infoHash := hpkeLabeledExtract(hpkeSuiteID, nil, "info_hash", info)
+	keyScheduleContext := append(append([]byte{hpkeModeBase}, pskIDHash...), infoHash...)
+	secret := hpkeLabeledExtract(hpkeSuiteID, sharedSecret, "secret", nil)
+	if key, err = hpkeLabeledExpand(hpkeSuiteID, secret, "key", keyScheduleContext, chacha20poly1305.KeySize); err != nil {
+		return nil, nil, err
+	}
+	if nonce, err = hpkeLabeledExpand(hpkeSuiteID, secret, "base_nonce", keyScheduleContext, chacha20poly1305.NonceSize); err != nil {
+		return nil, nil, err
+	}
+	return key, nonce, nil
+}
+
+func hpkeLabeledExtract(suiteID, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := append(append(append([]byte("HPKE-v1"), suiteID...), label...), ikm...)
+	return hkdf.Extract(sha256.New, labeledIKM, salt)
+}
+
+func hpkeLabeledExpand(suiteID, prk []byte, label string, info []byte, length int) ([]byte, error) {
+	labeledInfo := binary.BigEndian.AppendUint16(nil, uint16(length))
+	labeledInfo = append(append(append(append(labeledInfo, "HPKE-v1"...), suiteID...), label...), info...)
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, labeledInfo), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+package cryptography
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestHPKESealOpen(t *testing.T) {
+	recipient, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Error occured during key generation: %v", err)
+		return
+	}
+	plaintext := []byte("DATA TO ENCRYPT")
+	enc, ciphertext, err := HPKESeal(recipient.PublicKey(), []byte("info"), []byte("aad"), plaintext)
+	if err != nil {
+		t.Errorf("Error occured during encryption: %v", err)
+		return
+	}
+	decrypted, err := HPKEOpen(recipient, enc, []byte("info"), []byte("aad"), ciphertext)
+	if err != nil {
+		t.Errorf("Error occured during decryption: %v", err)
+		return
+	}
+	if !slices.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypted data different from original")
+	}
+
+	if _, err := HPKEOpen(recipient, enc, []byte("other info"), []byte("aad"), ciphertext); err == nil {
+		t.Errorf("Decryption with other info should fail")
+	}
+	if _, err := HPKEOpen(recipient, enc, []byte("info"), []byte("other aad"), ciphertext); err == nil {
+		t.Errorf("Decryption with other associated data should fail")
+	}
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	if _, err := HPKEOpen(other, enc, []byte("info"), []byte("aad"), ciphertext); err == nil {
+		t.Errorf("Decryption by another recipient should fail")
+	}
+}
+
+// ciphertext checked against the HPKE implementation of the Go standard library
+func TestHPKEKnownAnswer(t *testing.T) {
+	ephemeralKey, _ := hex.DecodeString("8341425cafede9d24b0599aefdfdeff1c1526ed75b07217eb99bf8c0b7498b81")
+	recipientKey, _ := hex.DecodeString("665d0698dbc8fb95afc25c3a4d9cf280d87a585b7999243ca6008fd03258975f")
+	expectedEnc := "e29d7521911498b837ed692d12a81587898e0ac3f6208eac1069bca82fb6b633"
+	expectedCiphertext := "dca6df2949165681e19c821e80b4f3630731963011163da91e8fb8532cec"
+
+	ephemeral, _ := ecdh.X25519().NewPrivateKey(ephemeralKey)
+	recipient, _ := ecdh.X25519().NewPrivateKey(recipientKey)
+	enc, ciphertext, err := hpkeSeal(ephemeral, recipient.PublicKey(), []byte("merkle-filebank test"), []byte("aad"), []byte("secret message"))
+	if err != nil {
+		t.Errorf("Error occured during encryption: %v", err)
+		return
+	}
+	if hex.EncodeToString(enc) != expectedEnc {
+		t.Errorf("Encapsulated key is %x, expected %v", enc, expectedEnc)
+	}
+	if hex.EncodeToString(ciphertext) != expectedCiphertext {
+		t.Errorf("Ciphertext is %x, expected %v", ciphertext, expectedCiphertext)
+	}
+}
@@ -0,0 +1,122 @@
+package cryptography
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+type KDF int32
+
+const (
+	// PBKDF2-HMAC-SHA1 with 4096 iterations and 8 bytes salts, used by banks created before KDF parameters were stored
+	PBKDF2   KDF = 0
+	Argon2id KDF = 1
+)
+
+// KDFParams are the parameters deriving the key of a file from the bank passphrase. Memory is in KiB,
+// time, memory and threads are only used by Argon2id
+type KDFParams struct {
+	KDF     KDF
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// parameters recommended by OWASP for Argon2id, each file key is derived with its own salt
+var DefaultArgon2idParams = KDFParams{KDF: Argon2id, Time: 2, Memory: 19 * 1024, Threads: 1}
+
+const (
+	derivedKeySize = 16
+	argon2SaltSize = 16
+	// bounds of the parameters read from descriptors, so that a descriptor cannot exhaust memory
+	maxArgon2Memory = 4 << 20
+	maxArgon2Time   = 64
+)
+
+func (p KDFParams) Validate() error {
+	switch p.KDF {
+	case PBKDF2:
+		return nil
+	case Argon2id:
+		if p.Threads < 1 {
+			return errors.New("argon2id needs at least one thread")
+		}
+		if p.Time < 1 || p.Time > maxArgon2Time {
+			return fmt.Errorf("argon2id time %v out of range 1-%v", p.Time, maxArgon2Time)
+		}
+		if p.Memory < 8*uint32(p.Threads) || p.Memory > maxArgon2Memory {
+			return fmt.Errorf("argon2id memory %v KiB out of range %v-%v KiB", p.Memory, 8*uint32(p.Threads), maxArgon2Memory)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown KDF %v", p.KDF)
+	}
+}
+
+func (p KDFParams) SaltSize() int {
+	if p.KDF == Argon2id {
+		return argon2SaltSize
+	}
+	return 8
+}
+
+func (p KDFParams) DeriveKey(passphrase, salt []byte, keySize int) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if p.KDF == Argon2id {
+		return argon2.IDKey(passphrase, salt, p.Time, p.Memory, p.Threads, uint32(keySize)), nil
+	}
+	return pbkdf2.Key(passphrase, salt, 4096, keySize, sha1.New), nil
+}
+
+// NewKey derives a key from the passphrase and a new salt
+func (p KDFParams) NewKey(passphrase []byte, keySize int) (key, salt []byte, err error) {
+	salt, err = randomBytes(p.SaltSize())
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err = p.DeriveKey(passphrase, salt, keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, salt, nil
+}
+
+// WrapKey wraps a key with the cipher suite, keyed by the key derived from the passphrase and a new salt
+func (p KDFParams) WrapKey(passphrase, key []byte, suite CipherSuite) (salt, wrappedKey []byte, err error) {
+	kek, salt, err := p.NewKey(passphrase, suite.KeySize())
+	if err != nil {
+		return nil, nil, err
+	}
+	wrappedKey, err = suite.WrapKey(kek, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return salt, wrappedKey, nil
+}
+
+// UnwrapKey unwraps a key wrapped by WrapKey
+func (p KDFParams) UnwrapKey(passphrase, salt, wrappedKey []byte, suite CipherSuite) ([]byte, error) {
+	kek, err := p.DeriveKey(passphrase, salt, suite.KeySize())
+	if err != nil {
+		return nil, err
+	}
+	return suite.UnwrapKey(kek, wrappedKey)
+}
+
+func (p KDFParams) String() string {
+	if p.KDF == Argon2id {
+		return fmt.Sprintf("argon2id (time %d, memory %d KiB, threads %d)", p.Time, p.Memory, p.Threads)
+	}
+	return "pbkdf2-sha1 (4096 iterations)"
+}
+
+func DeriveKey(passphrase, salt []byte) []byte {
+	// derive key from passphrase with salt
+	return pbkdf2.Key(passphrase, salt, 4096, derivedKeySize, sha1.New)
+}
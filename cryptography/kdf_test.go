@@ -0,0 +1,95 @@
+package cryptography
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// small parameters, keeps tests fast
+var testArgon2idParams = KDFParams{KDF: Argon2id, Time: 1, Memory: 64, Threads: 1}
+
+func TestEncryptDecryptArgon2id(t *testing.T) {
+	passphrase := []byte("testpassword")
+	dataToEncrypt := []byte("DATA TO ENCRYPT")
+
+	encryptedData, salt, iv, err := EncryptDataWithKDF(dataToEncrypt, passphrase, testArgon2idParams)
+	if err != nil {
+		t.Errorf("Error occured during encrypton: %v", err)
+		return
+	}
+	if len(salt) != argon2SaltSize {
+		t.Errorf("Argon2id salt should be %v bytes long, got %v", argon2SaltSize, len(salt))
+	}
+	key, err := testArgon2idParams.DeriveKey(passphrase, salt, derivedKeySize)
+	if err != nil {
+		t.Errorf("Error occured during derivation: %v", err)
+		return
+	}
+	decryptedData, err := DecryptData(encryptedData, key, iv)
+	if err != nil {
+		t.Errorf("Error occured during decryption: %v", err)
+		return
+	}
+	if !slices.Equal(decryptedData, dataToEncrypt) {
+		t.Errorf("Decrypted data different from original")
+	}
+	// parameters are part of the derivation
+	otherParams := testArgon2idParams
+	otherParams.Time = 2
+	otherKey, _ := otherParams.DeriveKey(passphrase, salt, derivedKeySize)
+	if slices.Equal(key, otherKey) {
+		t.Errorf("Keys derived with different parameters should differ")
+	}
+}
+
+func TestPBKDF2ParamsMatchLegacyDerivation(t *testing.T) {
+	passphrase := []byte("testpassword")
+	salt := []byte("saltsalt")
+	key, err := KDFParams{KDF: PBKDF2}.DeriveKey(passphrase, salt, derivedKeySize)
+	if err != nil {
+		t.Errorf("Error occured during derivation: %v", err)
+		return
+	}
+	if !slices.Equal(key, DeriveKey(passphrase, salt)) {
+		t.Errorf("PBKDF2 parameters should derive the legacy key")
+	}
+}
+
+func TestInvalidKDFParams(t *testing.T) {
+	for _, params := range []KDFParams{
+		{KDF: Argon2id, Time: 0, Memory: 64, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: 64, Threads: 0},
+		{KDF: Argon2id, Time: 1, Memory: 7, Threads: 1},
+		{KDF: Argon2id, Time: 1, Memory: maxArgon2Memory + 1, Threads: 1},
+		{KDF: 5},
+	} {
+		if _, err := params.DeriveKey([]byte("testpassword"), make([]byte, 16), derivedKeySize); err == nil {
+			t.Errorf("Derivation with parameters %+v should fail", params)
+		}
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	passphrase := []byte("testpassword")
+	for _, suite := range []CipherSuite{AES128GCM, AES256GCM, XChaCha20Poly1305} {
+		key := make([]byte, suite.KeySize())
+		copy(key, "0123456789abcdef")
+		salt, wrappedKey, err := testArgon2idParams.WrapKey(passphrase, key, suite)
+		if err != nil {
+			t.Errorf("Error occured during wrapping with %v: %v", suite, err)
+			continue
+		}
+		unwrappedKey, err := testArgon2idParams.UnwrapKey(passphrase, salt, wrappedKey, suite)
+		if err != nil {
+			t.Errorf("Error occured during unwrapping with %v: %v", suite, err)
+			continue
+		}
+		if !slices.Equal(unwrappedKey, key) {
+			t.Errorf("Unwrapped key different from original with %v", suite)
+		}
+		if _, err := testArgon2idParams.UnwrapKey([]byte("wrongpassword"), salt, wrappedKey, suite); err == nil {
+			t.Errorf("Unwrapping with a wrong password should fail with %v", suite)
+		}
+	}
+}
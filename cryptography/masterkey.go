@@ -0,0 +1,69 @@
+package cryptography
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Banks of descriptor v2 have a random master key, wrapped with the key derived from the passphrase. The key of each
+// file is derived from the master key and the salt of the file by HKDF, so the slow KDF only runs once per bank.
+
+const (
+	masterKeySize   = 32
+	fileKeySaltSize = 16
+)
+
+var fileKeyInfo = []byte("merkle-filebank file key")
+
+func NewMasterKey() ([]byte, error) {
+	return randomBytes(masterKeySize)
+}
+
+// DeriveFileKey derives the key of a file from the master key of its bank and the salt of the file
+func (c CipherSuite) DeriveFileKey(masterKey, salt []byte) ([]byte, error) {
+	if len(masterKey) != masterKeySize {
+		return nil, errors.New("invalid master key length")
+	}
+	if len(salt) != fileKeySaltSize {
+		return nil, errors.New("invalid salt length")
+	}
+	fileKey := make([]byte, c.KeySize())
+	if _, err := io.ReadFull(hkdf.New(sha256.New, masterKey, salt, fileKeyInfo), fileKey); err != nil {
+		return nil, err
+	}
+	return fileKey, nil
+}
+
+// NewFileKey derives the key of a new file from the master key and a new salt
+func (c CipherSuite) NewFileKey(masterKey []byte) (fileKey, salt []byte, err error) {
+	salt, err = randomBytes(fileKeySaltSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileKey, err = c.DeriveFileKey(masterKey, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileKey, salt, nil
+}
+
+// EncryptDataWithMasterKey encrypts in a single AES-128-GCM call, with the key derived from the master key and a new salt
+func EncryptDataWithMasterKey(data, masterKey []byte) (ct, salt, iv []byte, err error) {
+	// generate random parameters
+	fileKey, salt, err := AES128GCM.NewFileKey(masterKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	iv, err = randomBytes(12)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ct, err = EncryptDataWithKey(data, fileKey, iv)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ct, salt, iv, nil
+}
@@ -0,0 +1,67 @@
+package cryptography
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestEncryptDecryptMasterKey(t *testing.T) {
+	masterKey, err := NewMasterKey()
+	if err != nil {
+		t.Errorf("Error occured during master key generation: %v", err)
+		return
+	}
+	dataToEncrypt := []byte("DATA TO ENCRYPT")
+
+	encryptedData, salt, iv, err := EncryptDataWithMasterKey(dataToEncrypt, masterKey)
+	if err != nil {
+		t.Errorf("Error occured during encrypton: %v", err)
+		return
+	}
+	fileKey, err := AES128GCM.DeriveFileKey(masterKey, salt)
+	if err != nil {
+		t.Errorf("Error occured during derivation: %v", err)
+		return
+	}
+	decryptedData, err := DecryptData(encryptedData, fileKey, iv)
+	if err != nil {
+		t.Errorf("Error occured during decryption: %v", err)
+		return
+	}
+	if !slices.Equal(decryptedData, dataToEncrypt) {
+		t.Errorf("Decrypted data different from original")
+	}
+}
+
+func TestDeriveFileKey(t *testing.T) {
+	masterKey := make([]byte, masterKeySize)
+	salt1 := make([]byte, fileKeySaltSize)
+	salt2 := make([]byte, fileKeySaltSize)
+	salt2[0] = 1
+
+	for _, suite := range []CipherSuite{AES128GCM, AES256GCM, XChaCha20Poly1305} {
+		key1, err := suite.DeriveFileKey(masterKey, salt1)
+		if err != nil {
+			t.Errorf("Error occured during derivation: %v", err)
+			return
+		}
+		if len(key1) != suite.KeySize() {
+			t.Errorf("File key of %v should be %v bytes long, got %v", suite, suite.KeySize(), len(key1))
+		}
+		key1Again, _ := suite.DeriveFileKey(masterKey, salt1)
+		if !slices.Equal(key1, key1Again) {
+			t.Errorf("Derivation should be deterministic")
+		}
+		key2, _ := suite.DeriveFileKey(masterKey, salt2)
+		if slices.Equal(key1, key2) {
+			t.Errorf("Files with different salts should have different keys")
+		}
+		if _, err := suite.DeriveFileKey(masterKey[:16], salt1); err == nil {
+			t.Errorf("Derivation with a short master key should fail")
+		}
+		if _, err := suite.DeriveFileKey(masterKey, salt1[:8]); err == nil {
+			t.Errorf("Derivation with a short salt should fail")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cryptography
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// PassphraseSource is where passphrases are read from when commands run without a terminal. At most one source can be
+// set, passphrases are read from the terminal when none is
+type PassphraseSource struct {
+	// name of an environment variable holding the passphrase
+	Env string
+	// file whose first line is the passphrase
+	File string
+	// file descriptor inherited from the parent process, whose first line is the passphrase. -1 if unset
+	Fd int
+	// shell command printing the passphrase on its first line, such as 'pass show filebank'
+	Command string
+	// passphrase already read, sources are read once per process
+	passphrase string
+}
+
+var passphraseSource = &PassphraseSource{Fd: -1}
+
+// SetPassphraseSource makes ReadPassphrase read from source instead of the terminal
+func SetPassphraseSource(source PassphraseSource) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+	passphraseSource = &source
+	return nil
+}
+
+func (s *PassphraseSource) Validate() error {
+	set := 0
+	for _, isSet := range []bool{s.Env != "", s.File != "", s.Fd >= 0, s.Command != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set > 1 {
+		return errors.New("only one passphrase source can be used")
+	}
+	return nil
+}
+
+// ReadPassphrase reads a passphrase from the source set by SetPassphraseSource, or from the terminal
+func ReadPassphrase() (string, error) {
+	return passphraseSource.ReadPassphrase()
+}
+
+// ReadPassphrase reads a passphrase from the source, or from the terminal when no source is set
+func (s *PassphraseSource) ReadPassphrase() (string, error) {
+	passphrase, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) < 6 {
+		return "", errors.New("Length must be at least 6 characters")
+	}
+	return passphrase, nil
+}
+
+func (s *PassphraseSource) read() (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+	var passphrase string
+	var err error
+	switch {
+	case s.Env != "":
+		var ok bool
+		if passphrase, ok = os.LookupEnv(s.Env); !ok {
+			err = fmt.Errorf("environment variable %v is not set", s.Env)
+		}
+	case s.File != "":
+		passphrase, err = readPassphraseFile(s.File)
+	case s.Fd >= 0:
+		passphrase, err = readFirstLine(os.NewFile(uintptr(s.Fd), fmt.Sprintf("fd %v", s.Fd)))
+	case s.Command != "":
+		passphrase, err = readPassphraseCommand(s.Command)
+	default:
+		// the terminal is read each time, so that different passphrases can be entered
+		bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return "", err
+		}
+		return string(bytePassword), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	s.passphrase = passphrase
+	return passphrase, nil
+}
+
+func readPassphraseFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	return readFirstLine(file)
+}
+
+func readPassphraseCommand(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	// lets the command prompt, e.g. for a gpg pin
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("passphrase command failed: %v", err)
+	}
+	return readFirstLine(io.NopCloser(bytes.NewReader(output)))
+}
+
+// readFirstLine reads and closes file
+func readFirstLine(file io.ReadCloser) (string, error) {
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", errors.New("empty passphrase source")
+	}
+	return strings.TrimSuffix(scanner.Text(), "\r"), nil
+}
@@ -0,0 +1,71 @@
+package cryptography
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPassphrase = "testpassword"
+
+func readTestPassphrase(t *testing.T, name string, source PassphraseSource) {
+	if err := SetPassphraseSource(source); err != nil {
+		t.Fatalf("Error occured while setting %v source: %v", name, err)
+	}
+	defer SetPassphraseSource(PassphraseSource{Fd: -1})
+	// sources are read once, later reads return the same passphrase
+	for i := 0; i < 2; i++ {
+		passphrase, err := ReadPassphrase()
+		if err != nil {
+			t.Errorf("Error occured while reading %v source: %v", name, err)
+			return
+		}
+		if passphrase != testPassphrase {
+			t.Errorf("Passphrase read from %v source should be %q, got %q", name, testPassphrase, passphrase)
+		}
+	}
+}
+
+func TestReadPassphraseSources(t *testing.T) {
+	t.Setenv("FILEBANKD_TEST_PASSPHRASE", testPassphrase)
+	readTestPassphrase(t, "env", PassphraseSource{Env: "FILEBANKD_TEST_PASSPHRASE", Fd: -1})
+
+	path := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(path, []byte(testPassphrase+"\r\nsecond line\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	readTestPassphrase(t, "file", PassphraseSource{File: path, Fd: -1})
+
+	readTestPassphrase(t, "command", PassphraseSource{Command: "echo " + testPassphrase, Fd: -1})
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString(testPassphrase)
+	w.Close()
+	readTestPassphrase(t, "fd", PassphraseSource{Fd: int(r.Fd())})
+}
+
+func TestReadPassphraseSourceErrors(t *testing.T) {
+	if err := SetPassphraseSource(PassphraseSource{Env: "A", File: "b", Fd: -1}); err == nil {
+		t.Errorf("Setting more than one source should fail")
+	}
+	t.Setenv("FILEBANKD_TEST_PASSPHRASE", "short")
+	sources := map[string]PassphraseSource{
+		"unset env":      {Env: "FILEBANKD_TEST_UNSET", Fd: -1},
+		"short":          {Env: "FILEBANKD_TEST_PASSPHRASE", Fd: -1},
+		"missing file":   {File: filepath.Join(t.TempDir(), "missing"), Fd: -1},
+		"failed command": {Command: "exit 1", Fd: -1},
+		"empty command":  {Command: "true", Fd: -1},
+	}
+	for name, source := range sources {
+		if err := SetPassphraseSource(source); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadPassphrase(); err == nil {
+			t.Errorf("Reading from %v source should fail", name)
+		}
+	}
+	SetPassphraseSource(PassphraseSource{Fd: -1})
+}
@@ -0,0 +1,55 @@
+package cryptography
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// The recovery phrase of a bank holds the seed of its private key and its master key, as two BIP39 mnemonics of 24
+// words each. Each half carries the BIP39 checksum of its 32 bytes, so that mistyped words are detected.
+
+const recoveryPhraseWords = 48
+
+func EncodeRecoveryPhrase(privKey ed25519.PrivateKey, masterKey []byte) (string, error) {
+	if len(privKey) != ed25519.PrivateKeySize {
+		return "", errors.New("invalid private key length")
+	}
+	if len(masterKey) != masterKeySize {
+		return "", errors.New("invalid master key length")
+	}
+	seedWords, err := bip39.NewMnemonic(privKey.Seed())
+	if err != nil {
+		return "", err
+	}
+	masterKeyWords, err := bip39.NewMnemonic(masterKey)
+	if err != nil {
+		return "", err
+	}
+	return seedWords + " " + masterKeyWords, nil
+}
+
+// DecodeRecoveryPhrase gives back the private key and master key of a recovery phrase. Words can be separated by any
+// whitespace, and are not case sensitive
+func DecodeRecoveryPhrase(phrase string) (ed25519.PrivateKey, []byte, error) {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) != recoveryPhraseWords {
+		return nil, nil, fmt.Errorf("recovery phrase should have %v words, got %v", recoveryPhraseWords, len(words))
+	}
+	half := recoveryPhraseWords / 2
+	seed, err := bip39.EntropyFromMnemonic(strings.Join(words[:half], " "))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid words 1-%v of recovery phrase: %v", half, err)
+	}
+	masterKey, err := bip39.EntropyFromMnemonic(strings.Join(words[half:], " "))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid words %v-%v of recovery phrase: %v", half+1, recoveryPhraseWords, err)
+	}
+	if len(seed) != ed25519.SeedSize || len(masterKey) != masterKeySize {
+		return nil, nil, errors.New("invalid recovery phrase length")
+	}
+	return ed25519.NewKeyFromSeed(seed), masterKey, nil
+}
@@ -0,0 +1,86 @@
+package cryptography
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestRecoveryPhrase(t *testing.T) {
+	_, privKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Errorf("Error occured during key generation: %v", err)
+		return
+	}
+	masterKey, err := NewMasterKey()
+	if err != nil {
+		t.Errorf("Error occured during master key generation: %v", err)
+		return
+	}
+
+	phrase, err := EncodeRecoveryPhrase(privKey, masterKey)
+	if err != nil {
+		t.Errorf("Error occured during encoding: %v", err)
+		return
+	}
+	words := strings.Fields(phrase)
+	if len(words) != recoveryPhraseWords {
+		t.Errorf("Recovery phrase should have %v words, got %v", recoveryPhraseWords, len(words))
+	}
+
+	// words are read in any case and with any separator
+	decodedPrivKey, decodedMasterKey, err := DecodeRecoveryPhrase(" " + strings.ToUpper(strings.Join(words, "\n ")) + "\n")
+	if err != nil {
+		t.Errorf("Error occured during decoding: %v", err)
+		return
+	}
+	if !slices.Equal(decodedPrivKey, privKey) {
+		t.Errorf("Decoded private key different from original")
+	}
+	if !slices.Equal(decodedMasterKey, masterKey) {
+		t.Errorf("Decoded master key different from original")
+	}
+
+	// swapping two words breaks a checksum
+	swapped := slices.Clone(words)
+	swapped[30], swapped[31] = swapped[31], swapped[30]
+	if swapped[30] != swapped[31] {
+		if _, _, err := DecodeRecoveryPhrase(strings.Join(swapped, " ")); err == nil {
+			t.Errorf("Decoding should fail with swapped words")
+		}
+	}
+	if _, _, err := DecodeRecoveryPhrase(strings.Join(words[:24], " ")); err == nil {
+		t.Errorf("Decoding should fail with missing words")
+	}
+	if _, _, err := DecodeRecoveryPhrase(strings.Join(append(slices.Clone(words[:47]), "notaword"), " ")); err == nil {
+		t.Errorf("Decoding should fail with unknown words")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	for _, suite := range []CipherSuite{AES128GCM, AES256GCM, XChaCha20Poly1305} {
+		key, err := randomBytes(suite.KeySize())
+		if err != nil {
+			t.Errorf("Error occured during key generation: %v", err)
+			return
+		}
+		plaintext := []byte("DATA TO ENCRYPT")
+		ciphertext, err := suite.Seal(key, plaintext, []byte("aad"))
+		if err != nil {
+			t.Errorf("Error occured during encryption with %v: %v", suite, err)
+			return
+		}
+		decrypted, err := suite.Open(key, ciphertext, []byte("aad"))
+		if err != nil {
+			t.Errorf("Error occured during decryption with %v: %v", suite, err)
+			return
+		}
+		if !slices.Equal(decrypted, plaintext) {
+			t.Errorf("Decrypted data different from original with %v", suite)
+		}
+		if _, err := suite.Open(key, ciphertext, []byte("other aad")); err == nil {
+			t.Errorf("Decryption with other associated data should fail with %v", suite)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cryptography
+
+import (
+	"errors"
+)
+
+// Shamir secret sharing over GF(2^8), each byte of the secret being shared with its own polynomial. A share is its x
+// coordinate followed by the values of the polynomials at x. Fewer shares than the threshold give back a wrong secret
+// without error, so secrets must be verified after they are combined.
+
+// SplitSecret splits secret into parts shares, any threshold of which give back the secret
+func SplitSecret(secret []byte, parts, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("cannot split an empty secret")
+	}
+	if threshold < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	}
+	if parts < threshold {
+		return nil, errors.New("parts cannot be less than threshold")
+	}
+	if parts > 255 {
+		return nil, errors.New("parts cannot exceed 255")
+	}
+
+	shares := make([][]byte, parts)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+	coefficients := make([]byte, threshold)
+	defer clear(coefficients)
+	for j, secretByte := range secret {
+		// random polynomial of degree threshold-1, whose value at 0 is the byte of the secret
+		random, err := randomBytes(threshold - 1)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[0] = secretByte
+		copy(coefficients[1:], random)
+		clear(random)
+		for _, share := range shares {
+			share[j+1] = gfEvaluate(coefficients, share[0])
+		}
+	}
+	return shares, nil
+}
+
+// CombineShares gives back the secret from shares, by interpolating the polynomials at 0
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+	length := len(shares[0])
+	if length < 2 {
+		return nil, errors.New("invalid share length")
+	}
+	seen := make(map[byte]bool)
+	for _, share := range shares {
+		if len(share) != length {
+			return nil, errors.New("shares have different lengths")
+		}
+		if share[0] == 0 || seen[share[0]] {
+			return nil, errors.New("invalid or duplicate share")
+		}
+		seen[share[0]] = true
+	}
+
+	secret := make([]byte, length-1)
+	for i, share := range shares {
+		// lagrange basis polynomial of the share, evaluated at 0
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = gfMul(basis, gfMul(other[0], gfInverse(other[0]^share[0])))
+			}
+		}
+		for k := range secret {
+			secret[k] ^= gfMul(basis, share[k+1])
+		}
+	}
+	return secret, nil
+}
+
+// evaluates the polynomial at x with Horner's method
+func gfEvaluate(coefficients []byte, x byte) byte {
+	result := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// multiplication in GF(2^8) with the AES polynomial, without branches on the operands
+func gfMul(a, b byte) byte {
+	var result byte
+	for i := 0; i < 8; i++ {
+		result ^= a & -(b & 1)
+		b >>= 1
+		a = a<<1 ^ 0x1b&-(a>>7)
+	}
+	return result
+}
+
+// inverse in GF(2^8), as a^254
+func gfInverse(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 7; i++ {
+		a = gfMul(a, a)
+		result = gfMul(result, a)
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package cryptography
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSplitCombineSecret(t *testing.T) {
+	secret := []byte("SECRET TO SPLIT BETWEEN PARTS")
+	shares, err := SplitSecret(secret, 5, 3)
+	if err != nil {
+		t.Errorf("Error occured during split: %v", err)
+		return
+	}
+	if len(shares) != 5 {
+		t.Errorf("Split should give 5 shares, got %v", len(shares))
+		return
+	}
+
+	// any 3 shares give back the secret
+	for a := 0; a < 5; a++ {
+		for b := a + 1; b < 5; b++ {
+			for c := b + 1; c < 5; c++ {
+				combined, err := CombineShares([][]byte{shares[c], shares[a], shares[b]})
+				if err != nil {
+					t.Errorf("Error occured during combination: %v", err)
+					return
+				}
+				if !slices.Equal(combined, secret) {
+					t.Errorf("Shares %v, %v and %v give a secret different from original", a, b, c)
+				}
+			}
+		}
+	}
+	combined, err := CombineShares(shares)
+	if err != nil || !slices.Equal(combined, secret) {
+		t.Errorf("All shares should give back the secret")
+	}
+
+	// fewer shares than the threshold give another secret
+	combined, err = CombineShares(shares[:2])
+	if err != nil {
+		t.Errorf("Error occured during combination: %v", err)
+	} else if slices.Equal(combined, secret) {
+		t.Errorf("2 shares should not give back the secret")
+	}
+
+	if _, err := CombineShares([][]byte{shares[0], shares[0], shares[1]}); err == nil {
+		t.Errorf("Combination should fail with duplicate shares")
+	}
+	if _, err := CombineShares([][]byte{shares[0], shares[1][:10]}); err == nil {
+		t.Errorf("Combination should fail with shares of different lengths")
+	}
+	if _, err := SplitSecret(secret, 2, 3); err == nil {
+		t.Errorf("Split should fail with less parts than threshold")
+	}
+	if _, err := SplitSecret(secret, 3, 1); err == nil {
+		t.Errorf("Split should fail with a threshold of 1")
+	}
+}
+
+func TestGFInverse(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if gfMul(byte(a), gfInverse(byte(a))) != 1 {
+			t.Errorf("Inverse of %v is wrong", a)
+		}
+	}
+}
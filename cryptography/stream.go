@@ -0,0 +1,216 @@
+package cryptography
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Files are encrypted as a STREAM of segments sealed with the cipher suite of their bank: the plaintext is split in
+// segments of segmentSize bytes, each sealed with the nonce prefix || segment number (4 bytes) || final flag (1 byte). The flag is set on the last
+// segment only, so that segments cannot be reordered, dropped or truncated. Each segment is authenticated on its
+// own, so files are encrypted and decrypted in constant memory, and any range of segments can be decrypted.
+// The associated data of all segments is the associated data of the file, as returned by FileAAD.
+
+const (
+	DefaultSegmentSize = 64 << 10
+	// size of the tags of all cipher suites
+	streamTagSize = 16
+	// size of the segment number and final flag at the end of nonces
+	streamNonceSuffixSize = 5
+)
+
+var fileAADLabel = []byte("merkle-filebank file")
+
+type streamCipher struct {
+	aead        cipher.AEAD
+	noncePrefix []byte
+	aad         []byte
+	segmentSize int
+}
+
+func (c CipherSuite) newStreamCipher(key, noncePrefix, aad []byte, segmentSize int) (*streamCipher, error) {
+	if segmentSize < 1 {
+		return nil, errors.New("invalid segment size")
+	}
+	aead, err := c.NewAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(noncePrefix) != aead.NonceSize()-streamNonceSuffixSize {
+		return nil, errors.New("invalid nonce prefix length")
+	}
+	return &streamCipher{aead: aead, noncePrefix: noncePrefix, aad: aad, segmentSize: segmentSize}, nil
+}
+
+func (s *streamCipher) nonce(segment int64, final bool) ([]byte, error) {
+	if segment < 0 || segment > math.MaxUint32 {
+		return nil, errors.New("too many segments")
+	}
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, uint32(segment))
+	if final {
+		return append(nonce, 1), nil
+	}
+	return append(nonce, 0), nil
+}
+
+func (s *streamCipher) seal(dst, plaintext []byte, segment int64, final bool) ([]byte, error) {
+	nonce, err := s.nonce(segment, final)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(dst, nonce, plaintext, s.aad), nil
+}
+
+func (s *streamCipher) open(dst, ciphertext []byte, segment int64, final bool) ([]byte, error) {
+	nonce, err := s.nonce(segment, final)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.aead.Open(dst, nonce, ciphertext, s.aad)
+	if err != nil {
+		return nil, fmt.Errorf("segment %v: %v", segment, err)
+	}
+	return plaintext, nil
+}
+
+// NewStreamNoncePrefix returns a random nonce prefix, of 7 bytes for AES-GCM and 19 bytes for XChaCha20-Poly1305
+func (c CipherSuite) NewStreamNoncePrefix() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c == XChaCha20Poly1305 {
+		return randomBytes(chacha20poly1305.NonceSizeX - streamNonceSuffixSize)
+	}
+	return randomBytes(12 - streamNonceSuffixSize)
+}
+
+// FileAAD returns the associated data binding the ciphertext of a file to the hash of the public key of its bank,
+// its number and the version of the bank descriptor, so that a file cannot be served in place of another
+func FileAAD(bankKeyHash []byte, seq uint64, descriptorVersion uint32) []byte {
+	aad := make([]byte, 0, len(fileAADLabel)+len(bankKeyHash)+12)
+	aad = append(aad, fileAADLabel...)
+	aad = append(aad, bankKeyHash...)
+	aad = binary.BigEndian.AppendUint64(aad, seq)
+	return binary.BigEndian.AppendUint32(aad, descriptorVersion)
+}
+
+// EncryptStream encrypts src to dst segment by segment. Empty plaintexts give a single empty final segment
+func (c CipherSuite) EncryptStream(dst io.Writer, src io.Reader, key, noncePrefix, aad []byte, segmentSize int) error {
+	s, err := c.newStreamCipher(key, noncePrefix, aad, segmentSize)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(src)
+	plaintext := make([]byte, segmentSize)
+	ciphertext := make([]byte, 0, segmentSize+streamTagSize)
+	for segment := int64(0); ; segment++ {
+		n, err := io.ReadFull(reader, plaintext)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		// a full segment is final if nothing follows it
+		final := n < segmentSize
+		if !final {
+			if _, err := reader.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if ciphertext, err = s.seal(ciphertext[:0], plaintext[:n], segment, final); err != nil {
+			return err
+		}
+		if _, err := dst.Write(ciphertext); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// DecryptStream decrypts src to dst segment by segment. Segments written to dst are authentic, but dst is only
+// complete when no error is returned
+func (c CipherSuite) DecryptStream(dst io.Writer, src io.Reader, key, noncePrefix, aad []byte, segmentSize int) error {
+	s, err := c.newStreamCipher(key, noncePrefix, aad, segmentSize)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(src)
+	ciphertext := make([]byte, segmentSize+streamTagSize)
+	plaintext := make([]byte, 0, segmentSize)
+	for segment := int64(0); ; segment++ {
+		n, err := io.ReadFull(reader, ciphertext)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		final := n < len(ciphertext)
+		if !final {
+			if _, err := reader.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if plaintext, err = s.open(plaintext[:0], ciphertext[:n], segment, final); err != nil {
+			return err
+		}
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// DecryptStreamSegments decrypts consecutive segments of a file of nbSegments segments, starting at firstSegment
+func (c CipherSuite) DecryptStreamSegments(ctSegments, key, noncePrefix, aad []byte, segmentSize int, firstSegment, nbSegments int64) ([]byte, error) {
+	s, err := c.newStreamCipher(key, noncePrefix, aad, segmentSize)
+	if err != nil {
+		return nil, err
+	}
+	var plaintext []byte
+	segment := firstSegment
+	for len(ctSegments) > 0 {
+		if segment >= nbSegments {
+			return nil, errors.New("segments beyond the end of the file")
+		}
+		end := min(len(ctSegments), segmentSize+streamTagSize)
+		if plaintext, err = s.open(plaintext, ctSegments[:end], segment, segment == nbSegments-1); err != nil {
+			return nil, err
+		}
+		ctSegments = ctSegments[end:]
+		segment++
+	}
+	return plaintext, nil
+}
+
+// StreamCiphertextSize returns the size of the encryption of a plaintext
+func StreamCiphertextSize(plaintextSize int64, segmentSize int) int64 {
+	return plaintextSize + StreamNbSegments(plaintextSize, segmentSize)*streamTagSize
+}
+
+// StreamPlaintextSize returns the size of the plaintext of a ciphertext
+func StreamPlaintextSize(ciphertextSize int64, segmentSize int) (int64, error) {
+	sealedSize := int64(segmentSize + streamTagSize)
+	nbSegments := (ciphertextSize + sealedSize - 1) / sealedSize
+	if ciphertextSize < streamTagSize || ciphertextSize-(nbSegments-1)*sealedSize < streamTagSize {
+		return 0, errors.New("invalid ciphertext size")
+	}
+	return ciphertextSize - nbSegments*streamTagSize, nil
+}
+
+// StreamNbSegments returns the number of segments of a plaintext, empty plaintexts have one segment
+func StreamNbSegments(plaintextSize int64, segmentSize int) int64 {
+	return max(1, (plaintextSize+int64(segmentSize)-1)/int64(segmentSize))
+}
@@ -0,0 +1,122 @@
+package cryptography
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+const testSegmentSize = 64
+
+var testAAD = FileAAD(make([]byte, 32), 1, 0)
+
+var testCipherSuites = []CipherSuite{AES128GCM, AES256GCM, XChaCha20Poly1305}
+
+func encryptTestStream(t *testing.T, suite CipherSuite, plaintext []byte) (ct, key, noncePrefix []byte) {
+	key = make([]byte, suite.KeySize())
+	noncePrefix, err := suite.NewStreamNoncePrefix()
+	if err != nil {
+		t.Fatalf("Error occured during nonce generation: %v", err)
+	}
+	var buff bytes.Buffer
+	if err := suite.EncryptStream(&buff, bytes.NewReader(plaintext), key, noncePrefix, testAAD, testSegmentSize); err != nil {
+		t.Fatalf("Error occured during encrypton: %v", err)
+	}
+	return buff.Bytes(), key, noncePrefix
+}
+
+func TestEncryptDecryptStream(t *testing.T) {
+	for _, suite := range testCipherSuites {
+		for _, size := range []int{0, 1, testSegmentSize - 1, testSegmentSize, testSegmentSize + 1, 3 * testSegmentSize, 1000} {
+			plaintext := make([]byte, size)
+			for i := range plaintext {
+				plaintext[i] = byte(i)
+			}
+			ct, key, noncePrefix := encryptTestStream(t, suite, plaintext)
+			if int64(len(ct)) != StreamCiphertextSize(int64(size), testSegmentSize) {
+				t.Errorf("Ciphertext of %v bytes should be %v bytes long, got %v", size, StreamCiphertextSize(int64(size), testSegmentSize), len(ct))
+			}
+			if plaintextSize, err := StreamPlaintextSize(int64(len(ct)), testSegmentSize); err != nil || plaintextSize != int64(size) {
+				t.Errorf("Plaintext size of %v bytes ciphertext should be %v, got %v (%v)", len(ct), size, plaintextSize, err)
+			}
+
+			var decrypted bytes.Buffer
+			if err := suite.DecryptStream(&decrypted, bytes.NewReader(ct), key, noncePrefix, testAAD, testSegmentSize); err != nil {
+				t.Errorf("Error occured during decryption of %v bytes: %v", size, err)
+				continue
+			}
+			if !slices.Equal(decrypted.Bytes(), plaintext) {
+				t.Errorf("Decrypted data of %v bytes different from original with %v", size, suite)
+			}
+		}
+	}
+}
+
+func TestDecryptStreamTampered(t *testing.T) {
+	for _, suite := range testCipherSuites {
+		plaintext := make([]byte, 3*testSegmentSize+10)
+		ct, key, noncePrefix := encryptTestStream(t, suite, plaintext)
+		sealedSize := testSegmentSize + streamTagSize
+
+		tampered := map[string][]byte{
+			// truncated on a segment boundary, last remaining segment is not final
+			"truncated": ct[:2*sealedSize],
+			"empty":     {},
+			"reordered": bytes.Join([][]byte{ct[sealedSize : 2*sealedSize], ct[:sealedSize], ct[2*sealedSize:]}, nil),
+			"flipped":   bytes.Join([][]byte{ct[:5], {ct[5] ^ 1}, ct[6:]}, nil),
+		}
+		for name, ct := range tampered {
+			if err := suite.DecryptStream(&bytes.Buffer{}, bytes.NewReader(ct), key, noncePrefix, testAAD, testSegmentSize); err == nil {
+				t.Errorf("Decryption of %v ciphertext should fail with %v", name, suite)
+			}
+		}
+	}
+}
+
+func TestDecryptStreamSegments(t *testing.T) {
+	for _, suite := range testCipherSuites {
+		plaintext := make([]byte, 5*testSegmentSize+10)
+		for i := range plaintext {
+			plaintext[i] = byte(i)
+		}
+		ct, key, noncePrefix := encryptTestStream(t, suite, plaintext)
+		sealedSize := testSegmentSize + streamTagSize
+		nbSegments := StreamNbSegments(int64(len(plaintext)), testSegmentSize)
+
+		for _, r := range [][2]int{{0, 1}, {2, 3}, {4, 6}, {5, 6}, {0, 6}} {
+			first, last := r[0], r[1]
+			ctSegments := ct[first*sealedSize : min(last*sealedSize, len(ct))]
+			decrypted, err := suite.DecryptStreamSegments(ctSegments, key, noncePrefix, testAAD, testSegmentSize, int64(first), nbSegments)
+			if err != nil {
+				t.Errorf("Error occured during decryption of segments %v-%v: %v", first, last, err)
+				continue
+			}
+			if !slices.Equal(decrypted, plaintext[first*testSegmentSize:min(last*testSegmentSize, len(plaintext))]) {
+				t.Errorf("Decrypted segments %v-%v different from original", first, last)
+			}
+		}
+		// segments must be decrypted at their position
+		if _, err := suite.DecryptStreamSegments(ct[sealedSize:2*sealedSize], key, noncePrefix, testAAD, testSegmentSize, 2, nbSegments); err == nil {
+			t.Errorf("Decryption of segment at another position should fail")
+		}
+	}
+}
+
+func TestDecryptStreamOtherAAD(t *testing.T) {
+	for _, suite := range testCipherSuites {
+		plaintext := make([]byte, 2*testSegmentSize)
+		ct, key, noncePrefix := encryptTestStream(t, suite, plaintext)
+
+		for name, aad := range map[string][]byte{
+			"other bank":    FileAAD(bytes.Repeat([]byte{1}, 32), 1, 0),
+			"other file":    FileAAD(make([]byte, 32), 2, 0),
+			"other version": FileAAD(make([]byte, 32), 1, 1),
+			"no":            nil,
+		} {
+			if err := suite.DecryptStream(&bytes.Buffer{}, bytes.NewReader(ct), key, noncePrefix, aad, testSegmentSize); err == nil {
+				t.Errorf("Decryption with %v associated data should fail", name)
+			}
+		}
+	}
+}
@@ -2,12 +2,9 @@ package cryptography
 
 import (
 	"crypto/rand"
-	"errors"
 	"io"
-	"syscall"
 
 	"github.com/akamensky/base58"
-	"golang.org/x/term"
 )
 
 func Random12BytesNonce() ([]byte, error) {
@@ -30,15 +27,3 @@ func Base58Encode(data []byte) string {
 func Base58Decode(data string) ([]byte, error) {
 	return base58.Decode(data)
 }
-
-func ReadPassphrase() (string, error) {
-	bytePassword, err := term.ReadPassword(int(syscall.Stdin))
-	if err != nil {
-		return "", err
-	}
-
-	if len(bytePassword) < 6 {
-		return "", errors.New("Length must be at least 6 characters")
-	}
-	return string(bytePassword), nil
-}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oteffahi/merkle-filebank/client"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Keep unlocked bank keys in a local agent",
+	Long: `Run a local agent holding the unlocked keys of banks, so that their password is entered once instead of at
+each pull or push. The agent listens on <home>/agent.sock, or on the path in $` + client.AgentSocketEnv + `.
+Commands use the agent when it holds the keys of their bank, and ask for the bank password otherwise.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		return nil
+	},
+}
+
+var startAgentCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Run agent",
+	Long:  `Run agent in the foreground until it is interrupted. Keys are only kept in memory, and forgotten when the agent stops.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			cmd.Help()
+			return
+		}
+		// verify home directory
+		ok, err := storage.IsHomeWellFormed(homepath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		} else if !ok {
+			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+			return
+		}
+
+		if err := client.RunAgent(client.AgentSocketPath(homepath), timeout); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var addAgentCmd = &cobra.Command{
+	Use:   "add [flags]",
+	Short: "Unlock bank and add its keys to agent",
+	Long:  `Unlock bank with its password, and hand its keys to the running agent`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, bankName, ok := getAgentBankFlags(cmd)
+		if !ok {
+			return
+		}
+
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallAgentAddBank(homepath, serverName, bankName, timeout); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var removeAgentCmd = &cobra.Command{
+	Use:   "remove [flags]",
+	Short: "Remove bank keys from agent",
+	Long:  `Make the running agent forget the keys of a bank`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, bankName, ok := getAgentBankFlags(cmd)
+		if !ok {
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallAgentRemoveBank(homepath, serverName, bankName); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var listAgentCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List banks held by agent",
+	Long:  `List the banks whose keys are held by the running agent, and when they will be forgotten`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallAgentListBanks(homepath); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.AddCommand(startAgentCmd, addAgentCmd, removeAgentCmd, listAgentCmd)
+
+	startAgentCmd.Flags().Duration("timeout", time.Hour, "time after which the keys of added banks are forgotten, 0 keeps them until the agent stops")
+
+	for _, cmd := range []*cobra.Command{addAgentCmd, removeAgentCmd} {
+		cmd.Flags().StringP("bank-name", "b", "", "unique local name for the filebank")
+		cmd.Flags().StringP("server", "s", "", "unique local name for the server")
+	}
+	addAgentCmd.Flags().Duration("timeout", 0, "time after which the keys of the bank are forgotten, 0 uses the timeout of the agent")
+}
+
+// reads the server and bank-name flags, printing help when they are missing
+func getAgentBankFlags(cmd *cobra.Command) (string, string, bool) {
+	serverName, err := cmd.Flags().GetString("server")
+	if err != nil {
+		fmt.Printf("%v\n\n", err)
+		cmd.Help()
+		return "", "", false
+	}
+	if serverName == "" {
+		fmt.Printf("Missing flag: server flag is required\n\n")
+		cmd.Help()
+		return "", "", false
+	}
+
+	bankName, err := cmd.Flags().GetString("bank-name")
+	if err != nil {
+		fmt.Printf("%v\n\n", err)
+		cmd.Help()
+		return "", "", false
+	}
+	if bankName == "" {
+		fmt.Printf("Missing flag: bank-name flag is required\n\n")
+		cmd.Help()
+		return "", "", false
+	}
+	return serverName, bankName, true
+}
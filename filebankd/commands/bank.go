@@ -5,31 +5,747 @@ import (
 	"strconv"
 
 	"github.com/oteffahi/merkle-filebank/client"
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
 	"github.com/oteffahi/merkle-filebank/storage"
 	"github.com/spf13/cobra"
 )
 
+var treeModes = map[string]pb.TreeMode{
+	"sorted":  pb.TreeMode_SORTED_TREE,
+	"indexed": pb.TreeMode_INDEXED_TREE,
+	"mmr":     pb.TreeMode_MOUNTAIN_RANGE,
+}
+
+var hashAlgorithms = map[string]pb.HashAlgorithm{
+	"sha256":      pb.HashAlgorithm_SHA256,
+	"sha512-256":  pb.HashAlgorithm_SHA512_256,
+	"blake2b-256": pb.HashAlgorithm_BLAKE2B_256,
+	"keccak256":   pb.HashAlgorithm_KECCAK256,
+}
+
+var kdfs = map[string]cr.KDF{
+	"pbkdf2":   cr.PBKDF2,
+	"argon2id": cr.Argon2id,
+}
+
+var compressions = map[string]pb.Compression{
+	"none": pb.Compression_NO_COMPRESSION,
+	"gzip": pb.Compression_GZIP,
+	"zstd": pb.Compression_ZSTD,
+}
+
+var cipherSuites = map[string]cr.CipherSuite{
+	"aes-128-gcm":        cr.AES128GCM,
+	"aes-256-gcm":        cr.AES256GCM,
+	"xchacha20-poly1305": cr.XChaCha20Poly1305,
+}
+
 var bankCmd = &cobra.Command{
 	Use:   "bank",
 	Short: "Manage banks",
 	Long: `- Create new bank on server
-- Download a file from a bank on server`,
+- Append files to a bank on server
+- Download files from a bank on server`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
-var createBankCmd = &cobra.Command{
-	Use:   "create [flags] [paths...]",
-	Short: "Create new bank on server",
-	Long: `Encrypts files, generates merkle tree, uploads files to server, saves merkle root and cryptographic parameters.
-
-Args:
-  paths: Space-seperated paths to files or directories. Files will be added recursively from directories.
-         Does not support regular expressions.`,
+var createBankCmd = &cobra.Command{
+	Use:   "create [flags] [paths...]",
+	Short: "Create new bank on server",
+	Long: `Encrypts files, generates merkle tree, uploads files to server, saves merkle root and cryptographic parameters.
+
+Args:
+  paths: Space-seperated paths to files or directories. Files will be added recursively from directories.
+         Does not support regular expressions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Printf("Missing positional arguments: at least one filepath is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// verify home directory
+		ok, err := storage.IsHomeWellFormed(homepath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		} else if !ok {
+			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+			return
+		}
+
+		var paths []string
+		for _, arg := range args {
+			content, err := storage.GetAllFilesPaths(arg)
+			if err != nil {
+				fmt.Printf("Error while processing positional argument %v:\n%v\n\n", arg, err)
+				cmd.Help()
+				return
+			}
+			paths = append(paths, content...)
+		}
+
+		treeName, err := cmd.Flags().GetString("tree")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		// new banks use the latest tree format, or the format of OpenZeppelin's StandardMerkleTree
+		treeVersion := pb.TreeVersion_TREE_V2
+		treeMode, ok := treeModes[treeName]
+		if treeName == "openzeppelin" {
+			treeMode, treeVersion, ok = pb.TreeMode_SORTED_TREE, pb.TreeVersion_TREE_OPENZEPPELIN, true
+		}
+		if !ok {
+			fmt.Printf("Unknown tree mode '%v'\n\n", treeName)
+			cmd.Help()
+			return
+		}
+
+		hashName, err := cmd.Flags().GetString("hash")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		hashAlgorithm, ok := hashAlgorithms[hashName]
+		if !ok {
+			fmt.Printf("Unknown hash algorithm '%v'\n\n", hashName)
+			cmd.Help()
+			return
+		}
+		if treeVersion == pb.TreeVersion_TREE_OPENZEPPELIN {
+			if cmd.Flags().Changed("hash") && hashAlgorithm != pb.HashAlgorithm_KECCAK256 {
+				fmt.Printf("OpenZeppelin trees only use keccak256\n\n")
+				cmd.Help()
+				return
+			}
+			hashAlgorithm = pb.HashAlgorithm_KECCAK256
+		}
+
+		chunkSize, err := cmd.Flags().GetInt32("chunk-size")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		// sorted trees cannot be chunked, only fail if chunking was explicitly requested
+		if treeMode == pb.TreeMode_SORTED_TREE && !cmd.Flags().Changed("chunk-size") {
+			chunkSize = 0
+		}
+
+		kdfParams, err := getKDFParams(cmd)
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		cipherName, err := cmd.Flags().GetString("cipher")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		cipherSuite, ok := cipherSuites[cipherName]
+		if !ok {
+			fmt.Printf("Unknown cipher suite '%v'\n\n", cipherName)
+			cmd.Help()
+			return
+		}
+
+		compressionName, err := cmd.Flags().GetString("compression")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		compression, ok := compressions[compressionName]
+		if !ok {
+			fmt.Printf("Unknown compression '%v'\n\n", compressionName)
+			cmd.Help()
+			return
+		}
+
+		if err := client.CallUploadFiles(homepath, serverName, bankName, paths, treeMode, treeVersion, hashAlgorithm, chunkSize, kdfParams, cipherSuite, compression); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var pushBankCmd = &cobra.Command{
+	Use:   "push [flags] [paths...]",
+	Short: "Append files to existing bank on server",
+	Long: `Encrypts files, uploads them to server after the files already in the bank, verifies that the new merkle root
+extends the saved one with a consistency proof, saves the new merkle root and cryptographic parameters.
+Only banks using indexed trees can be extended.
+
+Args:
+  paths: Space-seperated paths to files or directories. Files will be added recursively from directories.
+         Does not support regular expressions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Printf("Missing positional arguments: at least one filepath is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// verify home directory
+		ok, err := storage.IsHomeWellFormed(homepath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		} else if !ok {
+			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+			return
+		}
+
+		var paths []string
+		for _, arg := range args {
+			content, err := storage.GetAllFilesPaths(arg)
+			if err != nil {
+				fmt.Printf("Error while processing positional argument %v:\n%v\n\n", arg, err)
+				cmd.Help()
+				return
+			}
+			paths = append(paths, content...)
+		}
+
+		if err := client.CallAppendFiles(homepath, serverName, bankName, paths); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var pullBankCmd = &cobra.Command{
+	Use:   "pull [flags] [fileNumbers...]",
+	Short: "Download files from server bank",
+	Long: `Downloads files from a server's bank, verifies merkle proof, decrypts files.
+When several files are requested, a single merkle multiproof is verified for all of them.
+On banks with chunked trees, --offset and --length download and verify a byte range of a single file.
+The merkle proof of a single file can be saved with --save-proof, to be checked offline with 'filebankd verify'.
+
+Args:
+  fileNumbers: space-separated identifiers of the files in the bank`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Printf("Missing positional arguments: at least one fileNumber is required\n\n")
+			cmd.Help()
+			return
+		}
+		var fileNums []int
+		for _, arg := range args {
+			fileNum, err := strconv.ParseInt(arg, 10, 0)
+			if err != nil {
+				fmt.Printf("Positional arguments %v is not a valid int value\n\n", arg)
+				cmd.Help()
+				return
+			}
+			fileNums = append(fileNums, int(fileNum))
+		}
+
+		offset, err := cmd.Flags().GetInt64("offset")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		length, err := cmd.Flags().GetInt64("length")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if length < 0 || offset < 0 {
+			fmt.Printf("Invalid range: offset and length cannot be negative\n\n")
+			cmd.Help()
+			return
+		}
+		if length > 0 && len(fileNums) != 1 {
+			fmt.Printf("Ranged downloads require exactly one fileNumber\n\n")
+			cmd.Help()
+			return
+		}
+		if length == 0 && cmd.Flags().Changed("offset") {
+			fmt.Printf("Missing flag: a positive length flag is required with offset\n\n")
+			cmd.Help()
+			return
+		}
+
+		proofPath, err := cmd.Flags().GetString("save-proof")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if proofPath != "" && length > 0 {
+			fmt.Printf("Proofs of ranged downloads cannot be saved\n\n")
+			cmd.Help()
+			return
+		}
+		proofFormat, err := cmd.Flags().GetString("proof-format")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if proofFormat != "json" && proofFormat != "binary" {
+			fmt.Printf("Unknown proof format '%v'\n\n", proofFormat)
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// verify home directory
+		ok, err := storage.IsHomeWellFormed(homepath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		} else if !ok {
+			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+			return
+		}
+
+		if length > 0 {
+			if err := client.CallDownloadFileRange(homepath, serverName, bankName, fileNums[0], offset, length); err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
+		if err := client.CallDownloadFiles(homepath, serverName, bankName, fileNums, proofPath, proofFormat == "json"); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var rekdfBankCmd = &cobra.Command{
+	Use:   "rekdf [flags]",
+	Short: "Derive the keys of a bank again with another KDF",
+	Long: `Derives the keys of a bank again from the bank password, with the KDF and parameters given by flags.
+The master key of the bank is wrapped with the new derived key. On banks created before master keys, the keys of all
+files are derived again, and files added later use the same KDF. Files are not uploaded again: their keys are kept,
+and stored wrapped with the new derived keys in the local bank descriptor.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		kdfParams, err := getKDFParams(cmd)
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallRederiveKeys(homepath, serverName, bankName, kdfParams); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var passwdBankCmd = &cobra.Command{
+	Use:   "passwd [flags]",
+	Short: "Change the password of a bank",
+	Long: `Encrypts the private key and the master key of a bank with a new password. On banks created before master keys,
+the keys of all files are wrapped with keys derived from the new password. Nothing changes on the server, and copies
+of the bank descriptor made before still open with the old password.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		newSource, err := getPassphraseSource(cmd, "new-passphrase")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallChangePassphrase(homepath, serverName, bankName, &newSource); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var rotateBankCmd = &cobra.Command{
+	Use:   "rotate [flags]",
+	Short: "Encrypt a bank again under new keys",
+	Long: `Downloads and decrypts all the files of a bank, encrypts them again under a new master key, and moves the bank
+to a new key pair protected by a new password. The old key signs a handover of the bank to the new key, which the
+server keeps with the new bank before removing the old one. Banks created before master keys are upgraded.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		newSource, err := getPassphraseSource(cmd, "new-passphrase")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		kdfParams, err := getKDFParams(cmd)
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		cipherName, err := cmd.Flags().GetString("cipher")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		var cipherSuite *cr.CipherSuite
+		if cipherName != "" {
+			suite, ok := cipherSuites[cipherName]
+			if !ok {
+				fmt.Printf("Unknown cipher suite '%v'\n\n", cipherName)
+				cmd.Help()
+				return
+			}
+			cipherSuite = &suite
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallRotateBank(homepath, serverName, bankName, &newSource, kdfParams, cipherSuite); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var backupBankCmd = &cobra.Command{
+	Use:   "backup [flags]",
+	Short: "Print the recovery phrase of a bank",
+	Long: `Prints the private key and master key of a bank as a recovery phrase of 48 words, and stores the manifest of the
+bank on the server. The manifest holds the parameters of the files, encrypted with a key derived from the master
+key, and is updated when files are pushed. The bank can be restored from the phrase with 'bank restore'. Banks
+created before master keys must be upgraded with 'bank rotate' first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		qr, err := cmd.Flags().GetBool("qr")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallBackupBank(homepath, serverName, bankName, qr); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var restoreBankCmd = &cobra.Command{
+	Use:   "restore [flags]",
+	Short: "Restore a bank from its recovery phrase",
+	Long: `Rebuilds the descriptor of a bank from its recovery phrase and the manifest stored on the server, under a new
+bank name. The keys of the restored bank are protected by a new password. The server must have been added with
+'server add' first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		phraseFile, err := cmd.Flags().GetString("phrase-file")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		newSource, err := getPassphraseSource(cmd, "new-passphrase")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		kdfParams, err := getKDFParams(cmd)
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallRestoreBank(homepath, serverName, bankName, phraseFile, &newSource, kdfParams); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var splitBankCmd = &cobra.Command{
+	Use:   "split [flags]",
+	Short: "Split the keys of a bank into shares for the team",
+	Long: `Splits the private key and master key of a bank into Shamir shares, one for each recipient, any threshold of
+which rebuild the bank with 'bank combine'. Each share is encrypted to the identity key of its recipient, as printed
+by 'identity show', and written to the output directory. The manifest of the bank is stored on the server. Banks
+created before master keys must be upgraded with 'bank rotate' first.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 {
-			fmt.Printf("Missing positional arguments: at least one filepath is required\n\n")
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
 			cmd.Help()
 			return
 		}
@@ -58,60 +774,183 @@ Args:
 			return
 		}
 
+		recipients, err := cmd.Flags().GetStringArray("recipient")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		nbShares, err := cmd.Flags().GetInt("shares")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if nbShares != len(recipients) {
+			fmt.Printf("%v shares require %v recipients, got %v\n\n", nbShares, nbShares, len(recipients))
+			cmd.Help()
+			return
+		}
+		threshold, err := cmd.Flags().GetInt("threshold")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if threshold < 2 || threshold > nbShares {
+			fmt.Printf("Threshold must be between 2 and the number of shares\n\n")
+			cmd.Help()
+			return
+		}
+
+		outDir, err := cmd.Flags().GetString("out")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
 		homepath, err := getHomePath(cmd)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
-		// verify home directory
-		ok, err := storage.IsHomeWellFormed(homepath)
-		if err != nil {
+
+		if err := client.CallSplitBank(homepath, serverName, bankName, threshold, recipients, outDir); err != nil {
 			fmt.Println(err)
 			return
-		} else if !ok {
-			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+		}
+	},
+}
+
+var reshareBankCmd = &cobra.Command{
+	Use:   "reshare [flags] share",
+	Short: "Forward a bank share to another identity",
+	Long: `Decrypts a bank share encrypted to your identity key, and encrypts it again to the identity key of the user who
+will combine the shares.
+
+Args:
+  share: Path to the share file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Printf("Invalid positional arguments: exactly one share is required\n\n")
+			cmd.Help()
 			return
 		}
 
-		var paths []string
-		for _, arg := range args {
-			content, err := storage.GetAllFilesPaths(arg)
-			if err != nil {
-				fmt.Printf("Error while processing positional argument %v:\n%v\n\n", arg, err)
-				cmd.Help()
-				return
-			}
-			paths = append(paths, content...)
+		recipient, err := cmd.Flags().GetString("to")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if recipient == "" {
+			fmt.Printf("Missing flag: to flag is required\n\n")
+			cmd.Help()
+			return
+		}
+		outPath, err := cmd.Flags().GetString("out")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if outPath == "" {
+			fmt.Printf("Missing flag: out flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
 		}
 
-		if err := client.CallUploadFiles(homepath, serverName, bankName, paths); err != nil {
+		if err := client.CallReshareBank(homepath, args[0], recipient, outPath); err != nil {
 			fmt.Println(err)
 			return
 		}
 	},
 }
 
-var pullBankCmd = &cobra.Command{
-	Use:   "pull [flags] [fileNumber]",
-	Short: "Download file from server bank",
-	Long: `Downloads a file from a server's bank, verifies merkle proof, decrypts file.
+var combineBankCmd = &cobra.Command{
+	Use:   "combine [flags] shares...",
+	Short: "Rebuild a bank from shares of its keys",
+	Long: `Rebuilds the private key and master key of a bank from shares encrypted to your identity key, and the bank
+descriptor from the manifest stored on the server. The keys of the bank are protected by a new password. Shares
+encrypted to other users are forwarded to you by their owners with 'bank reshare'.
 
 Args:
-  fileNumber: identifier of the file in the bank`,
+  shares: Space-seperated paths to share files.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 {
-			fmt.Printf("Missing positional arguments: fileNumber is required\n\n")
+		if len(args) < 2 {
+			fmt.Printf("Missing positional arguments: at least two shares are required\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		newSource, err := getPassphraseSource(cmd, "new-passphrase")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
 			cmd.Help()
 			return
 		}
-		if len(args) > 1 {
-			fmt.Printf("Unexpected positional arguments after %v\n\n", args[0])
+
+		kdfParams, err := getKDFParams(cmd)
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
 			cmd.Help()
 			return
 		}
-		fileNum, err := strconv.ParseInt(args[0], 10, 0)
+
+		homepath, err := getHomePath(cmd)
 		if err != nil {
-			fmt.Printf("Positional arguments %v is not a valid int value\n\n", args[0])
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallCombineBank(homepath, serverName, bankName, args, &newSource, kdfParams); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var grantBankCmd = &cobra.Command{
+	Use:   "grant [flags]",
+	Short: "Share a bank with other identities",
+	Long: `Shares a bank with recipients, as printed by 'identity show'. The master key of the bank is encrypted to the
+identity key of each recipient, and the recipients are stored on the server with the manifest of the bank. Recipients
+then fetch the bank with 'bank fetch' and pull its files, but cannot push files. Banks created before master keys must
+be upgraded with 'bank rotate' first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
 			cmd.Help()
 			return
 		}
@@ -140,22 +979,149 @@ Args:
 			return
 		}
 
+		recipients, err := cmd.Flags().GetStringArray("recipient")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if len(recipients) == 0 {
+			fmt.Printf("Missing flag: recipient flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
 		homepath, err := getHomePath(cmd)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
-		// verify home directory
-		ok, err := storage.IsHomeWellFormed(homepath)
+
+		if err := client.CallGrantBank(homepath, serverName, bankName, recipients); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var revokeBankCmd = &cobra.Command{
+	Use:   "revoke [flags]",
+	Short: "Stop sharing a bank with identities",
+	Long: `Removes recipients of a bank, locally and on the server, which then refuses their requests. The master key they
+already unwrapped stays valid for the files they could copy: rotate the bank with 'bank rotate' to change it, the
+remaining recipients then fetch the bank from its new address.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		recipients, err := cmd.Flags().GetStringArray("recipient")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if len(recipients) == 0 {
+			fmt.Printf("Missing flag: recipient flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
 		if err != nil {
 			fmt.Println(err)
 			return
-		} else if !ok {
-			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+		}
+
+		if err := client.CallRevokeBank(homepath, serverName, bankName, recipients); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var fetchBankCmd = &cobra.Command{
+	Use:   "fetch [flags]",
+	Short: "Fetch a bank shared with your identity",
+	Long: `Writes the descriptor of a bank shared with your identity key by its owner with 'bank grant', from the address of
+the bank and its manifest stored on the server. Fetching the bank again updates it with the files pushed since, or
+moves it to the new address given by its owner after a rotation. Shared banks are pulled with your identity password.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" {
+			fmt.Printf("Missing flag: server flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if bankName == "" {
+			fmt.Printf("Missing flag: bank-name flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		address, err := cmd.Flags().GetString("address")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if address == "" {
+			fmt.Printf("Missing flag: address flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
 			return
 		}
 
-		if err := client.CallDownloadFiles(homepath, serverName, bankName, int(fileNum)); err != nil {
+		if err := client.CallFetchBank(homepath, serverName, bankName, address); err != nil {
 			fmt.Println(err)
 			return
 		}
@@ -233,8 +1199,75 @@ var listBankCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(bankCmd)
-	bankCmd.AddCommand(createBankCmd, pullBankCmd, listBankCmd)
+	bankCmd.AddCommand(createBankCmd, pushBankCmd, pullBankCmd, rekdfBankCmd, passwdBankCmd, rotateBankCmd, backupBankCmd, restoreBankCmd, splitBankCmd, reshareBankCmd, combineBankCmd, grantBankCmd, revokeBankCmd, fetchBankCmd, listBankCmd)
 
 	bankCmd.PersistentFlags().StringP("bank-name", "b", "", "unique local name for the filebank")
 	bankCmd.PersistentFlags().StringP("server", "s", "", "unique local name for the server")
+
+	createBankCmd.Flags().String("tree", "indexed", "merkle tree mode: 'indexed' binds each file to its number, 'mmr' is an indexed tree stored as a merkle mountain range for append-only banks, 'sorted' is the legacy mode, 'openzeppelin' is a sorted tree in the format of OpenZeppelin's StandardMerkleTree")
+	createBankCmd.Flags().String("hash", "sha256", "merkle tree hash function: 'sha256', 'sha512-256', 'blake2b-256' or 'keccak256'")
+	createBankCmd.Flags().Int32("chunk-size", 1<<20, "size in bytes of the chunks hashed in each file's chunk tree, 0 disables chunking (indexed trees only)")
+	createBankCmd.Flags().String("cipher", "aes-256-gcm", "encryption of the files: 'aes-256-gcm', 'aes-128-gcm' or 'xchacha20-poly1305' (for hosts without AES instructions)")
+	createBankCmd.Flags().String("compression", "none", "compression of the files before encryption: 'none', 'gzip' or 'zstd'. Files that are already compressed are stored as they are")
+	addKDFFlags(createBankCmd)
+	addKDFFlags(rekdfBankCmd)
+	addKDFFlags(rotateBankCmd)
+	addKDFFlags(restoreBankCmd)
+	addKDFFlags(combineBankCmd)
+	rotateBankCmd.Flags().String("cipher", "", "encryption of the files: 'aes-256-gcm', 'aes-128-gcm' or 'xchacha20-poly1305', the cipher of the bank when empty")
+
+	addPassphraseFlags(passwdBankCmd.Flags(), "new-passphrase", "the new bank password")
+	addPassphraseFlags(rotateBankCmd.Flags(), "new-passphrase", "the new bank password")
+	addPassphraseFlags(restoreBankCmd.Flags(), "new-passphrase", "the new bank password")
+	addPassphraseFlags(combineBankCmd.Flags(), "new-passphrase", "the new bank password")
+
+	backupBankCmd.Flags().Bool("qr", false, "also print the recovery phrase as a QR code")
+	restoreBankCmd.Flags().String("phrase-file", "", "file holding the recovery phrase, read from the terminal when empty")
+
+	splitBankCmd.Flags().IntP("shares", "n", 0, "number of shares, one for each recipient")
+	splitBankCmd.Flags().IntP("threshold", "k", 0, "number of shares rebuilding the bank")
+	splitBankCmd.Flags().StringArray("recipient", nil, "identity public key of a recipient, repeated for each recipient")
+	splitBankCmd.Flags().StringP("out", "o", ".", "directory where the shares are written")
+	reshareBankCmd.Flags().String("to", "", "identity public key of the user the share is forwarded to")
+	reshareBankCmd.Flags().StringP("out", "o", "", "path where the forwarded share is written")
+	grantBankCmd.Flags().StringArray("recipient", nil, "identity public key of a recipient, repeated for each recipient")
+	revokeBankCmd.Flags().StringArray("recipient", nil, "identity public key of a recipient, repeated for each recipient")
+	fetchBankCmd.Flags().String("address", "", "address of the bank, printed to its owner by 'bank grant'")
+
+	pullBankCmd.Flags().Int64("offset", 0, "start of the byte range to download")
+	pullBankCmd.Flags().Int64("length", 0, "length of the byte range to download, 0 downloads whole files")
+	pullBankCmd.Flags().String("save-proof", "", "path where the merkle proof of the pulled file is saved")
+	pullBankCmd.Flags().String("proof-format", "json", "format of the saved proof: 'json' or 'binary'")
+}
+
+func addKDFFlags(cmd *cobra.Command) {
+	cmd.Flags().String("kdf", "argon2id", "function deriving the master key of the bank from the bank password: 'argon2id' or 'pbkdf2' (legacy)")
+	cmd.Flags().Uint32("kdf-time", cr.DefaultArgon2idParams.Time, "argon2id number of passes")
+	cmd.Flags().Uint32("kdf-memory", cr.DefaultArgon2idParams.Memory, "argon2id memory in KiB")
+	cmd.Flags().Uint8("kdf-threads", cr.DefaultArgon2idParams.Threads, "argon2id number of threads")
+}
+
+func getKDFParams(cmd *cobra.Command) (cr.KDFParams, error) {
+	kdfName, err := cmd.Flags().GetString("kdf")
+	if err != nil {
+		return cr.KDFParams{}, err
+	}
+	kdf, ok := kdfs[kdfName]
+	if !ok {
+		return cr.KDFParams{}, fmt.Errorf("Unknown KDF '%v'", kdfName)
+	}
+	if kdf == cr.PBKDF2 {
+		return cr.KDFParams{KDF: cr.PBKDF2}, nil
+	}
+	params := cr.KDFParams{KDF: kdf}
+	if params.Time, err = cmd.Flags().GetUint32("kdf-time"); err != nil {
+		return cr.KDFParams{}, err
+	}
+	if params.Memory, err = cmd.Flags().GetUint32("kdf-memory"); err != nil {
+		return cr.KDFParams{}, err
+	}
+	if params.Threads, err = cmd.Flags().GetUint8("kdf-threads"); err != nil {
+		return cr.KDFParams{}, err
+	}
+	return params, params.Validate()
 }
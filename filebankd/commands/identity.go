@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/oteffahi/merkle-filebank/client"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"github.com/spf13/cobra"
+)
+
+var identityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Manage the identity key receiving bank shares",
+	Long: `Manage the identity key of the user. Its public key is given to the team, who encrypt shares of bank keys to
+it with 'bank split'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		return nil
+	},
+}
+
+var createIdentityCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Generate identity key",
+	Long:  `Generate the identity key of the user, protected by a password, and print its public key`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		newSource, err := getPassphraseSource(cmd, "new-passphrase")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// verify home directory
+		ok, err := storage.IsHomeWellFormed(homepath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		} else if !ok {
+			fmt.Printf("Home %v does not exist or is malformed. You can use 'init' to fix it.\n", homepath)
+			return
+		}
+
+		if err := client.CallCreateIdentity(homepath, &newSource); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var showIdentityCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print identity public key",
+	Long:  `Print the public key of the identity key, to be given to the team`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.CallShowIdentity(homepath); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(identityCmd)
+	identityCmd.AddCommand(createIdentityCmd, showIdentityCmd)
+
+	addPassphraseFlags(createIdentityCmd.Flags(), "new-passphrase", "the identity password")
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
 	"github.com/oteffahi/merkle-filebank/storage"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
@@ -18,6 +20,9 @@ Files are encrypted before upload to server, and merkle trees are used to guaran
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setPassphraseSource(cmd)
+	},
 }
 
 var initCmd = &cobra.Command{
@@ -69,6 +74,7 @@ func init() {
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.PersistentFlags().String("home", userHome+"/.filebankd", "root directory for MerkleFileBank storage")
+	addPassphraseFlags(rootCmd.PersistentFlags(), "passphrase", "passphrases")
 }
 
 func getHomePath(cmd *cobra.Command) (string, error) {
@@ -78,3 +84,37 @@ func getHomePath(cmd *cobra.Command) (string, error) {
 	}
 	return homepath, nil
 }
+
+func setPassphraseSource(cmd *cobra.Command) error {
+	source, err := getPassphraseSource(cmd, "passphrase")
+	if err != nil {
+		return err
+	}
+	return cr.SetPassphraseSource(source)
+}
+
+// adds the flags of a passphrase source, named prefix-env, prefix-file, prefix-fd and prefix-command
+func addPassphraseFlags(flags *pflag.FlagSet, prefix, what string) {
+	flags.String(prefix+"-env", "", "read "+what+" from this environment variable")
+	flags.String(prefix+"-file", "", "read "+what+" from the first line of this file")
+	flags.Int(prefix+"-fd", -1, "read "+what+" from the first line of this file descriptor")
+	flags.String(prefix+"-command", "", "read "+what+" from the first line printed by this shell command, e.g. 'pass show filebank'")
+}
+
+func getPassphraseSource(cmd *cobra.Command, prefix string) (cr.PassphraseSource, error) {
+	var source cr.PassphraseSource
+	var err error
+	if source.Env, err = cmd.Flags().GetString(prefix + "-env"); err != nil {
+		return source, err
+	}
+	if source.File, err = cmd.Flags().GetString(prefix + "-file"); err != nil {
+		return source, err
+	}
+	if source.Fd, err = cmd.Flags().GetInt(prefix + "-fd"); err != nil {
+		return source, err
+	}
+	if source.Command, err = cmd.Flags().GetString(prefix + "-command"); err != nil {
+		return source, err
+	}
+	return source, source.Validate()
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/oteffahi/merkle-filebank/client"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Export and import merkle trees of banks",
+	Long:  `Export and import merkle trees of banks in the dump format of OpenZeppelin's StandardMerkleTree (@openzeppelin/merkle-tree).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var treeDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Dump the merkle tree of a bank",
+	Long: `Dumps the merkle tree of a bank created with '--tree openzeppelin', as StandardMerkleTree's tree.dump() would.
+Each file is the value [contentHash] of leaf encoding ["bytes32"]. The dump can be loaded with StandardMerkleTree.load().`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Printf("Unexpected positional arguments\n\n")
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if serverName == "" || bankName == "" {
+			fmt.Printf("Missing flag: server and bank-name flags are required\n\n")
+			cmd.Help()
+			return
+		}
+		outPath, err := cmd.Flags().GetString("output")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.DumpBankTree(homepath, serverName, bankName, outPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+var treeImportCmd = &cobra.Command{
+	Use:   "import [flags] <dump>",
+	Short: "Validate a dump of a merkle tree",
+	Long: `Validates a dump of StandardMerkleTree, as StandardMerkleTree.load() does, and prints its merkle root.
+With --server and --bank-name, the dump must also be the tree of the files of the bank.
+
+Args:
+  dump: path of the JSON dump`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Printf("Missing positional argument: dump is required\n\n")
+			cmd.Help()
+			return
+		}
+		if len(args) > 1 {
+			fmt.Printf("Unexpected positional arguments after %v\n\n", args[0])
+			cmd.Help()
+			return
+		}
+
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if (serverName == "") != (bankName == "") {
+			fmt.Printf("Missing flag: server and bank-name flags are required together\n\n")
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.ImportTreeDump(homepath, args[0], serverName, bankName); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(treeCmd)
+	treeCmd.AddCommand(treeDumpCmd)
+	treeCmd.AddCommand(treeImportCmd)
+
+	treeDumpCmd.Flags().StringP("server", "s", "", "unique local name of the server of the bank")
+	treeDumpCmd.Flags().StringP("bank-name", "b", "", "unique local name of the bank")
+	treeDumpCmd.Flags().StringP("output", "o", "", "path of the dump, written to stdout by default")
+
+	treeImportCmd.Flags().StringP("server", "s", "", "unique local name of the server of the bank to compare with")
+	treeImportCmd.Flags().StringP("bank-name", "b", "", "unique local name of the bank to compare with")
+}
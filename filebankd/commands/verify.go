@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/oteffahi/merkle-filebank/client"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [flags] <file>",
+	Short: "Verify a file against a saved merkle proof, offline",
+	Long: `Verifies a file against a merkle proof saved with 'bank pull --save-proof', without contacting the server.
+The merkle root is given with --root, or read from a local bank with --server and --bank-name.
+The file is the encrypted file stored by the server, or the downloaded file with --plaintext. Plaintext files
+are encrypted again before verification, which requires the bank password.
+
+Args:
+  file: path of the file to verify`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Printf("Missing positional argument: file is required\n\n")
+			cmd.Help()
+			return
+		}
+		if len(args) > 1 {
+			fmt.Printf("Unexpected positional arguments after %v\n\n", args[0])
+			cmd.Help()
+			return
+		}
+
+		proofPath, err := cmd.Flags().GetString("proof")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if proofPath == "" {
+			fmt.Printf("Missing flag: proof flag is required\n\n")
+			cmd.Help()
+			return
+		}
+
+		rootHex, err := cmd.Flags().GetString("root")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		serverName, err := cmd.Flags().GetString("server")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		bankName, err := cmd.Flags().GetString("bank-name")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+		if (serverName == "") != (bankName == "") {
+			fmt.Printf("Missing flag: server and bank-name flags are required together\n\n")
+			cmd.Help()
+			return
+		}
+		if rootHex == "" && bankName == "" {
+			fmt.Printf("Missing flag: root flag or server and bank-name flags are required\n\n")
+			cmd.Help()
+			return
+		}
+
+		plaintext, err := cmd.Flags().GetBool("plaintext")
+		if err != nil {
+			fmt.Printf("%v\n\n", err)
+			cmd.Help()
+			return
+		}
+
+		homepath, err := getHomePath(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if err := client.VerifySavedProof(homepath, proofPath, args[0], rootHex, serverName, bankName, plaintext); err != nil {
+			fmt.Println(err)
+			return
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().String("proof", "", "path of the proof saved by 'bank pull --save-proof', in JSON or binary format")
+	verifyCmd.Flags().String("root", "", "merkle root in hex to verify against")
+	verifyCmd.Flags().StringP("server", "s", "", "unique local name of the server of the bank holding the merkle root")
+	verifyCmd.Flags().StringP("bank-name", "b", "", "unique local name of the bank holding the merkle root")
+	verifyCmd.Flags().Bool("plaintext", false, "file is the decrypted download, encrypt it again before verification")
+}
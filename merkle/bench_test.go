@@ -0,0 +1,174 @@
+package merkle
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+var benchSizes = []int{1 << 10, 1 << 14, 1 << 17, 1 << 20}
+
+// previous implementation of merkleTreeFromLeafs, one goroutine per pair of leafs, kept as a reference for benchmarks
+func goroutinePerPairTreeFromLeafs(h treeHashing, leafs [][32]byte) [][32]byte {
+	if len(leafs) == 1 {
+		return leafs[:1]
+	}
+	sort.Slice(leafs, func(i, j int) bool {
+		return cr.CompareHashes(leafs[i], leafs[j])
+	})
+	treeLen := len(leafs)*2 - 1
+	tree := make([][32]byte, treeLen)
+	for i, leaf := range leafs {
+		tree[treeLen-1-i] = leaf
+	}
+	atomicBuffers := make([]atomic.Pointer[[32]byte], treeLen-len(leafs))
+	notifyEnd := make(chan struct{})
+	defer close(notifyEnd)
+	for i := treeLen - 1; i > treeLen-len(leafs); i -= 2 {
+		go goroutinePerPairWorker(h, tree, atomicBuffers, i, notifyEnd)
+	}
+	<-notifyEnd
+	return tree
+}
+
+func goroutinePerPairWorker(h treeHashing, tree [][32]byte, atomicBuffers []atomic.Pointer[[32]byte], index1 int, ch chan<- struct{}) {
+	index2 := index1 + 1
+	if index1%2 == 0 {
+		index2 = index1 - 1
+	}
+	load := func(i int) *[32]byte {
+		if i >= len(atomicBuffers) {
+			return &tree[i]
+		}
+		return atomicBuffers[i].Load()
+	}
+	elm1, elm2 := load(index1), load(index2)
+	if elm1 == nil || elm2 == nil {
+		return
+	}
+	resultIndex := min(index1, index2) / 2
+	tree[resultIndex] = h.sortedNodeHash(*elm1, *elm2)
+	atomicBuffers[resultIndex].Store(&tree[resultIndex])
+	if resultIndex == 0 {
+		ch <- struct{}{}
+	} else {
+		goroutinePerPairWorker(h, tree, atomicBuffers, resultIndex, ch)
+	}
+}
+
+func benchLeafs(h treeHashing, nbLeafs int) [][32]byte {
+	leafs := make([][32]byte, nbLeafs)
+	for i := range leafs {
+		leafs[i] = h.indexedLeafHash(i+1, h.contentHash([]byte(fmt.Sprintf("TEST%d", i))))
+	}
+	return leafs
+}
+
+func TestLevelBuildMatchesGoroutinePerPair(t *testing.T) {
+	h, _ := newTreeHashing(TreeV2, cr.SHA256, 0)
+	// sizes around the parallel threshold and uneven levels
+	for _, nbLeafs := range []int{1, 2, 3, 5, minHashesPerWorker + 1, 4*minHashesPerWorker + 3, 50000} {
+		leafs := benchLeafs(h, nbLeafs)
+		expected := goroutinePerPairTreeFromLeafs(h, append([][32]byte{}, leafs...))
+		got := merkleTreeFromLeafs(h, append([][32]byte{}, leafs...))
+		if len(got) != len(expected) {
+			t.Errorf("tree of %v leafs has %v nodes, expected %v", nbLeafs, len(got), len(expected))
+			continue
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("tree of %v leafs differs at node %v", nbLeafs, i)
+				break
+			}
+		}
+	}
+}
+
+func TestParallelIndexedTree(t *testing.T) {
+	h, _ := newTreeHashing(TreeV2, cr.SHA256, 0)
+	nbLeafs := 8*minHashesPerWorker + 5
+	tree := MerkleTree{Hashes: indexedTreeFromLeafs(h, benchLeafs(h, nbLeafs)), Mode: IndexedTree, Version: TreeV2}
+	// consistency proofs recompute subtree roots sequentially from the stored nodes
+	for _, oldSize := range []int{1, minHashesPerWorker, nbLeafs - 1} {
+		proof, err := tree.GenerateConsistencyProof(oldSize)
+		if err != nil {
+			t.Errorf("error when generating consistency proof: %v", err)
+			t.FailNow()
+		}
+		oldTree := MerkleTree{Hashes: indexedTreeFromLeafs(h, benchLeafs(h, oldSize)), Mode: IndexedTree, Version: TreeV2}
+		if !proof.VerifyConsistency(oldTree.GetMerkleRoot(), tree.GetMerkleRoot()) {
+			t.Errorf("failed to verify consistency from %v to %v leafs", oldSize, nbLeafs)
+		}
+	}
+}
+
+func BenchmarkSortedTreeFromLeafs(b *testing.B) {
+	h, _ := newTreeHashing(TreeV2, cr.SHA256, 0)
+	for _, nbLeafs := range benchSizes {
+		leafs := benchLeafs(h, nbLeafs)
+		buffer := make([][32]byte, nbLeafs)
+		b.Run(fmt.Sprintf("levels/%d", nbLeafs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(buffer, leafs)
+				merkleTreeFromLeafs(h, buffer)
+			}
+		})
+		b.Run(fmt.Sprintf("goroutine-per-pair/%d", nbLeafs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(buffer, leafs)
+				goroutinePerPairTreeFromLeafs(h, buffer)
+			}
+		})
+	}
+}
+
+func BenchmarkIndexedTreeFromLeafs(b *testing.B) {
+	h, _ := newTreeHashing(TreeV2, cr.SHA256, 0)
+	for _, nbLeafs := range benchSizes {
+		leafs := benchLeafs(h, nbLeafs)
+		b.Run(fmt.Sprintf("%d", nbLeafs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				indexedTreeFromLeafs(h, leafs)
+			}
+		})
+	}
+}
+
+func BenchmarkBuildMerkleTree(b *testing.B) {
+	for _, nbFiles := range benchSizes {
+		files := make([][]byte, nbFiles)
+		for i := range files {
+			files[i] = []byte(fmt.Sprintf("TEST%d", i))
+		}
+		for _, mode := range treeModes {
+			b.Run(fmt.Sprintf("mode%d/%d", mode, nbFiles), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					tree := MerkleTree{Mode: mode, Version: TreeV2}
+					if err := tree.BuildMerkleTree(files); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkNodeHash(b *testing.B) {
+	for _, algorithm := range []cr.HashAlgorithm{cr.SHA256, cr.SHA512_256, cr.BLAKE2b256, cr.Keccak256} {
+		h, _ := newTreeHashing(TreeV2, algorithm, 0)
+		left, right := h.hasher.HashOnce([]byte("left")), h.hasher.HashOnce([]byte("right"))
+		b.Run(fmt.Sprintf("algorithm%d", algorithm), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				left = h.nodeHash(left, right)
+			}
+		})
+	}
+}
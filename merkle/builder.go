@@ -0,0 +1,228 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// MerkleTreeBuilder accumulates leafs one file at a time. Leafs of an existing tree can be
+// loaded so that new files are appended without re-hashing the files already in the tree.
+// Mountain ranges are extended as files are added, and their existing nodes are never hashed again.
+// Indexed trees keep their levels between builds, so that only the right edge of the tree is hashed again.
+// Leafs of sorted trees move whenever a file is added, so their nodes are recomputed on every build.
+type MerkleTreeBuilder struct {
+	leafs [][32]byte
+	nodes [][32]byte
+	// levels of the indexed tree as of the last build, from the leafs to the root, and number of leafs they cover
+	levels  [][][32]byte
+	nbBuilt int
+	tree    MerkleTree
+	hashing treeHashing
+}
+
+func NewMerkleTreeBuilder(mode TreeMode, version TreeVersion, algorithm cr.HashAlgorithm, chunkSize int) (*MerkleTreeBuilder, error) {
+	tree := MerkleTree{
+		Mode:      mode,
+		Version:   version,
+		Hash:      algorithm,
+		ChunkSize: chunkSize,
+	}
+	hashing, err := tree.hashing()
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleTreeBuilder{
+		tree:    tree,
+		hashing: hashing,
+	}, nil
+}
+
+func NewMerkleTreeBuilderFromTree(tree MerkleTree) (*MerkleTreeBuilder, error) {
+	if tree.Mode == MountainRangeTree {
+		if mmrNbLeafs(len(tree.Hashes)) < 1 {
+			return nil, errors.New("malformed mountain range")
+		}
+		builder, err := NewMerkleTreeBuilder(tree.Mode, tree.Version, tree.Hash, tree.ChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		// nodes of the tree may be memory-mapped, keep a copy to append to
+		builder.nodes = slices.Clone(tree.Hashes)
+		return builder, nil
+	}
+	if tree.Mode == IndexedTree {
+		return newIndexedTreeBuilderFromTree(tree)
+	}
+	leafs, err := tree.GetLeafs()
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewMerkleTreeBuilder(tree.Mode, tree.Version, tree.Hash, tree.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	builder.leafs = leafs
+	return builder, nil
+}
+
+func newIndexedTreeBuilderFromTree(tree MerkleTree) (*MerkleTreeBuilder, error) {
+	nbLeafs, node, err := tree.indexedNodes()
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewMerkleTreeBuilder(tree.Mode, tree.Version, tree.Hash, tree.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	// nodes of the tree may be memory-mapped, keep a copy of each level to append to
+	sizes := indexedTreeLevels(nbLeafs)
+	builder.levels = make([][][32]byte, len(sizes))
+	for level, size := range sizes {
+		builder.levels[level] = make([][32]byte, size)
+		for i := range builder.levels[level] {
+			builder.levels[level][i] = node(level, i)
+		}
+	}
+	builder.leafs = builder.levels[0]
+	builder.nbBuilt = nbLeafs
+	return builder, nil
+}
+
+func (b *MerkleTreeBuilder) AddFile(file []byte) {
+	b.addContentHash(b.hashing.contentHash(file))
+}
+
+func (b *MerkleTreeBuilder) AddFileFromReader(r io.Reader) error {
+	contentHash, err := b.hashing.contentHashFromReader(r)
+	if err != nil {
+		return err
+	}
+	b.addContentHash(contentHash)
+	return nil
+}
+
+// AddContentHash adds a file from the hash of its content, as returned by MerkleTree.ContentHash
+func (b *MerkleTreeBuilder) AddContentHash(contentHash [32]byte) {
+	b.addContentHash(contentHash)
+}
+
+func (b *MerkleTreeBuilder) NbLeafs() int {
+	if b.tree.Mode == MountainRangeTree {
+		return mmrNbLeafs(len(b.nodes))
+	}
+	return len(b.leafs)
+}
+
+func (b *MerkleTreeBuilder) Build() (*MerkleTree, error) {
+	if b.NbLeafs() == 0 {
+		return nil, errors.New("cannot create tree from empty builder")
+	}
+	if b.tree.Mode == MountainRangeTree {
+		tree := b.tree
+		tree.Hashes = slices.Clone(b.nodes)
+		return &tree, nil
+	}
+	if b.tree.Mode == IndexedTree {
+		b.buildIndexedLevels()
+		tree := b.tree
+		tree.Hashes = b.flattenIndexedLevels()
+		return &tree, nil
+	}
+	// merkleTreeFromLeafs sorts its input, work on a copy so that the builder can keep growing
+	leafs := make([][32]byte, len(b.leafs))
+	copy(leafs, b.leafs)
+	tree := b.tree
+	tree.Hashes = tree.treeFromLeafs(b.hashing, leafs)
+	return &tree, nil
+}
+
+func (b *MerkleTreeBuilder) addContentHash(contentHash [32]byte) {
+	// leafs of indexed trees commit to the sequence number of the file, starting from 1
+	nbLeafs := b.NbLeafs()
+	leaf := b.tree.leafFromContentHash(b.hashing, nbLeafs+1, contentHash)
+	if b.tree.Mode == MountainRangeTree {
+		b.nodes = mmrAppend(b.hashing, b.nodes, nbLeafs, leaf)
+		return
+	}
+	b.leafs = append(b.leafs, leaf)
+}
+
+// buildIndexedLevels hashes the nodes that depend on leafs added since the last build, the other nodes are kept
+func (b *MerkleTreeBuilder) buildIndexedLevels() {
+	sizes := indexedTreeLevels(len(b.leafs))
+	if len(b.levels) == 0 {
+		b.levels = make([][][32]byte, 1)
+	}
+	b.levels[0] = b.leafs
+	changed := b.nbBuilt
+	for level := 1; level < len(sizes); level++ {
+		// parents of the first new node of the level below, nodes on their left are unchanged
+		changed /= 2
+		if level == len(b.levels) {
+			b.levels = append(b.levels, nil)
+		}
+		nodes := append(b.levels[level][:changed], make([][32]byte, sizes[level]-changed)...)
+		below := b.levels[level-1]
+		hashInParallel(changed, sizes[level], func(i int) {
+			if 2*i+1 < len(below) {
+				nodes[i] = b.hashing.nodeHash(below[2*i], below[2*i+1])
+			} else {
+				// no sibling, promote node
+				nodes[i] = below[2*i]
+			}
+		})
+		b.levels[level] = nodes
+	}
+	b.nbBuilt = len(b.leafs)
+}
+
+func (b *MerkleTreeBuilder) flattenIndexedLevels() [][32]byte {
+	sizes := indexedTreeLevels(len(b.leafs))
+	offsets := indexedTreeOffsets(sizes)
+	tree := make([][32]byte, offsets[0]+len(b.leafs))
+	for level := range sizes {
+		copy(tree[offsets[level]:], b.levels[level])
+	}
+	return tree
+}
+
+func LoadMerkleTree(hashes [][]byte, mode TreeMode, version TreeVersion, algorithm cr.HashAlgorithm, chunkSize int) (*MerkleTree, error) {
+	// convert from slice of slices to slice of arrays
+	tree := make([][32]byte, len(hashes))
+	for i, hash := range hashes {
+		if len(hash) != 32 {
+			return nil, fmt.Errorf("invalid hash length %v at index %v", len(hash), i)
+		}
+		tree[i] = [32]byte(hash)
+	}
+	return LoadMerkleTreeFromNodes(tree, mode, version, algorithm, chunkSize)
+}
+
+// LoadMerkleTreeFromNodes uses the nodes without copying them, so that nodes of a memory-mapped tree are only read when needed
+func LoadMerkleTreeFromNodes(nodes [][32]byte, mode TreeMode, version TreeVersion, algorithm cr.HashAlgorithm, chunkSize int) (*MerkleTree, error) {
+	if _, err := newTreeHashing(version, algorithm, chunkSize); err != nil {
+		return nil, err
+	}
+	if mode == IndexedTree {
+		if len(nodes) == 0 || indexedTreeNbLeafs(len(nodes)) == -1 {
+			return nil, fmt.Errorf("invalid tree size %v", len(nodes))
+		}
+	} else if mode == MountainRangeTree {
+		if mmrNbLeafs(len(nodes)) < 1 {
+			return nil, fmt.Errorf("invalid mountain range size %v", len(nodes))
+		}
+	} else if len(nodes)%2 == 0 {
+		return nil, fmt.Errorf("invalid tree size %v", len(nodes))
+	}
+	return &MerkleTree{
+		Hashes:    nodes,
+		Mode:      mode,
+		Version:   version,
+		Hash:      algorithm,
+		ChunkSize: chunkSize,
+	}, nil
+}
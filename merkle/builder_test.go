@@ -0,0 +1,193 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+var treeModes = []TreeMode{SortedTree, IndexedTree, MountainRangeTree}
+
+var treeVersions = []TreeVersion{TreeV1, TreeV2}
+
+func TestNoEmptyBuilder(t *testing.T) {
+	for _, mode := range treeModes {
+		builder, err := NewMerkleTreeBuilder(mode, TreeV2, cr.SHA256, 0)
+		if err != nil {
+			t.Errorf("error when creating builder: %v", err)
+			t.FailNow()
+		}
+		if _, err := builder.Build(); err == nil {
+			t.Errorf("builder should return error when no leaf was added")
+		}
+	}
+}
+
+func TestBuilderMatchesFullBuild(t *testing.T) {
+	for _, mode := range treeModes {
+		for _, version := range treeVersions {
+			testBuilderMatchesFullBuild(t, mode, version)
+		}
+	}
+}
+
+func testBuilderMatchesFullBuild(t *testing.T, mode TreeMode, version TreeVersion) {
+	for i := 1; i <= 20; i++ {
+		var files [][]byte
+		builder, err := NewMerkleTreeBuilder(mode, version, cr.SHA256, 0)
+		if err != nil {
+			t.Errorf("error when creating builder: %v", err)
+			t.FailNow()
+		}
+		for j := 0; j < i; j++ {
+			file := []byte(fmt.Sprintf("TEST%d", j))
+			files = append(files, file)
+			if j%2 == 0 {
+				builder.AddFile(file)
+			} else if err := builder.AddFileFromReader(bytes.NewReader(file)); err != nil {
+				t.Errorf("error when adding file from reader: %v", err)
+				t.FailNow()
+			}
+		}
+		expected := MerkleTree{Mode: mode, Version: version}
+		if err := expected.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		tree, err := builder.Build()
+		if err != nil {
+			t.Errorf("error when building tree: %v", err)
+			t.FailNow()
+		}
+		if tree.GetMerkleRoot() != expected.GetMerkleRoot() {
+			t.Errorf("merkle roots do not match for %v files in mode %v version %v", i, mode, version)
+		}
+	}
+}
+
+func TestAppendToLoadedTree(t *testing.T) {
+	for _, mode := range treeModes {
+		for _, version := range treeVersions {
+			testAppendToLoadedTree(t, mode, version)
+		}
+	}
+}
+
+func testAppendToLoadedTree(t *testing.T, mode TreeMode, version TreeVersion) {
+	var files [][]byte
+	for i := 0; i < 50; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	initial := MerkleTree{Mode: mode, Version: version}
+	if err := initial.BuildMerkleTree(files[:30]); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+
+	// simulate a tree read from a bank descriptor
+	var serialized [][]byte
+	for i := 0; i < len(initial.Hashes); i++ {
+		hash := initial.Hashes[i]
+		serialized = append(serialized, hash[:])
+	}
+	loaded, err := LoadMerkleTree(serialized, mode, version, cr.SHA256, 0)
+	if err != nil {
+		t.Errorf("error when loading tree: %v", err)
+		t.FailNow()
+	}
+
+	builder, err := NewMerkleTreeBuilderFromTree(*loaded)
+	if err != nil {
+		t.Errorf("error when loading builder: %v", err)
+		t.FailNow()
+	}
+	for _, file := range files[30:] {
+		builder.AddFile(file)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Errorf("error when building tree: %v", err)
+		t.FailNow()
+	}
+
+	expected := MerkleTree{Mode: mode, Version: version}
+	if err := expected.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	if tree.GetMerkleRoot() != expected.GetMerkleRoot() {
+		t.Errorf("appended tree root different from full rebuild in mode %v version %v", mode, version)
+	}
+	for i, file := range files {
+		var proof *MerkleProof
+		if mode.IsIndexed() {
+			proof, err = tree.GenerateProofForFileNum(i + 1)
+		} else {
+			proof, err = tree.GenerateProofForFile(file)
+		}
+		if err != nil {
+			t.Errorf("error when generating proof: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyFileProof(file, expected.GetMerkleRoot()) {
+			t.Errorf("failed to verify proof for file %v", i)
+		}
+	}
+}
+
+func TestLoadMalformedTree(t *testing.T) {
+	if _, err := LoadMerkleTree([][]byte{make([]byte, 32), make([]byte, 32)}, SortedTree, TreeV2, cr.SHA256, 0); err == nil {
+		t.Errorf("loading sorted tree with even number of nodes should return error")
+	}
+	// no indexed tree has 2 nodes
+	if _, err := LoadMerkleTree([][]byte{make([]byte, 32), make([]byte, 32)}, IndexedTree, TreeV2, cr.SHA256, 0); err == nil {
+		t.Errorf("loading indexed tree with invalid number of nodes should return error")
+	}
+	if _, err := LoadMerkleTree([][]byte{make([]byte, 31)}, SortedTree, TreeV2, cr.SHA256, 0); err == nil {
+		t.Errorf("loading tree with invalid hash length should return error")
+	}
+	if _, err := LoadMerkleTree([][]byte{make([]byte, 32)}, SortedTree, TreeV2, cr.HashAlgorithm(42), 0); err == nil {
+		t.Errorf("loading tree with unknown hash algorithm should return error")
+	}
+}
+
+func TestBuildAfterEachAppend(t *testing.T) {
+	for _, mode := range treeModes {
+		builder, err := NewMerkleTreeBuilder(mode, TreeV2, cr.SHA256, 0)
+		if err != nil {
+			t.Errorf("error when creating builder: %v", err)
+			t.FailNow()
+		}
+		var files [][]byte
+		for i := 0; i < 40; i++ {
+			// build after a varying number of new files, so that the right edge of the tree changes shape
+			for j := 0; j <= i%3; j++ {
+				file := []byte(fmt.Sprintf("TEST%d-%d", i, j))
+				files = append(files, file)
+				builder.AddFile(file)
+			}
+			tree, err := builder.Build()
+			if err != nil {
+				t.Errorf("error when building tree: %v", err)
+				t.FailNow()
+			}
+			expected := MerkleTree{Mode: mode, Version: TreeV2}
+			if err := expected.BuildMerkleTree(files); err != nil {
+				t.Errorf("error when generating tree: %v", err)
+				t.FailNow()
+			}
+			if len(tree.Hashes) != len(expected.Hashes) {
+				t.Errorf("tree of %v files has %v nodes instead of %v in mode %v", len(files), len(tree.Hashes), len(expected.Hashes), mode)
+				continue
+			}
+			for k := range tree.Hashes {
+				if tree.Hashes[k] != expected.Hashes[k] {
+					t.Errorf("node %v of tree of %v files differs from full rebuild in mode %v", k, len(files), mode)
+					break
+				}
+			}
+		}
+	}
+}
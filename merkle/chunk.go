@@ -0,0 +1,141 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// Chunked trees split each file in chunks of ChunkSize bytes. The chunks of a file are the leafs of an indexed tree,
+// the chunk tree, whose root replaces the hash of the file content in the leaf of the file. A range of a file can then
+// be verified from the chunks covering it, a multiproof of these chunks and a proof of the chunk tree root.
+
+// BuildChunkTree reads a file and returns its chunk tree. An empty file has a single empty chunk.
+func BuildChunkTree(r io.Reader, chunkSize int, version TreeVersion, algorithm cr.HashAlgorithm) (*MerkleTree, error) {
+	if chunkSize < 1 {
+		return nil, errors.New("chunk size must be positive")
+	}
+	h, err := newTreeHashing(version, algorithm, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return h.chunkTree(r)
+}
+
+// number of chunks of a file of the given size
+func NbChunks(fileSize int64, chunkSize int) int {
+	if fileSize == 0 {
+		return 1
+	}
+	return int((fileSize + int64(chunkSize) - 1) / int64(chunkSize))
+}
+
+// ChunksOfRange returns the first and last chunks covering a range of a file, numbered from 0
+func ChunksOfRange(offset, length, fileSize int64, chunkSize int) (int64, int64, error) {
+	// offset+length may overflow, compare the length to the remaining size instead
+	if chunkSize < 1 || offset < 0 || length < 1 || offset > fileSize || length > fileSize-offset {
+		return 0, 0, fmt.Errorf("invalid range of %v bytes at offset %v in file of size %v", length, offset, fileSize)
+	}
+	return offset / int64(chunkSize), (offset + length - 1) / int64(chunkSize), nil
+}
+
+func (h treeHashing) chunkRoot(r io.Reader) ([32]byte, error) {
+	tree, err := h.chunkTree(r)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return tree.GetMerkleRoot(), nil
+}
+
+func (h treeHashing) chunkTree(r io.Reader) (*MerkleTree, error) {
+	hasher := h.newContentHasher()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return nil, err
+	}
+	hasher.Sum()
+	return hasher.chunkTree, nil
+}
+
+// ContentHasher computes the content hash of a file as it is written, so that files are hashed without being held in
+// memory. Chunked files are split in chunks as they are written, and their chunk tree is kept.
+type ContentHasher struct {
+	h      treeHashing
+	digest hash.Hash
+	// leafs of the chunks already written, and number of bytes of the current chunk
+	leafs     [][32]byte
+	chunkLen  int
+	chunkTree *MerkleTree
+}
+
+// NewContentHasher returns a ContentHasher for the files of the tree
+func (m MerkleTree) NewContentHasher() (*ContentHasher, error) {
+	h, err := m.hashing()
+	if err != nil {
+		return nil, err
+	}
+	return h.newContentHasher(), nil
+}
+
+func (h treeHashing) newContentHasher() *ContentHasher {
+	return &ContentHasher{h: h, digest: h.hasher.New()}
+}
+
+func (c *ContentHasher) Write(p []byte) (int, error) {
+	if c.chunkTree != nil {
+		return 0, errors.New("content hasher already summed")
+	}
+	n := len(p)
+	if c.h.chunkSize == 0 {
+		c.digest.Write(p)
+		return n, nil
+	}
+	for len(p) > 0 {
+		// a full chunk is only ended by the next byte, the last chunk of a file is never empty
+		if c.chunkLen == c.h.chunkSize {
+			c.endChunk()
+		}
+		size := min(len(p), c.h.chunkSize-c.chunkLen)
+		c.digest.Write(p[:size])
+		c.chunkLen += size
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// Sum ends the file and returns its content hash, the root of its chunk tree when files are chunked
+func (c *ContentHasher) Sum() [32]byte {
+	if c.h.chunkSize == 0 {
+		return [32]byte(c.digest.Sum(nil))
+	}
+	if c.chunkTree == nil {
+		// an empty file has a single empty chunk
+		if c.chunkLen > 0 || len(c.leafs) == 0 {
+			c.endChunk()
+		}
+		// chunks are leafs of a tree without chunking
+		chunkHashing := treeHashing{version: c.h.version, hasher: c.h.hasher}
+		c.chunkTree = &MerkleTree{
+			Hashes:  indexedTreeFromLeafs(chunkHashing, c.leafs),
+			Mode:    IndexedTree,
+			Version: c.h.version,
+			Hash:    c.h.hasher.Algorithm(),
+		}
+	}
+	return c.chunkTree.GetMerkleRoot()
+}
+
+// ChunkTree ends the file and returns its chunk tree, nil when files are not chunked
+func (c *ContentHasher) ChunkTree() *MerkleTree {
+	c.Sum()
+	return c.chunkTree
+}
+
+func (c *ContentHasher) endChunk() {
+	chunkHashing := treeHashing{version: c.h.version, hasher: c.h.hasher}
+	c.leafs = append(c.leafs, chunkHashing.indexedLeafHash(len(c.leafs)+1, [32]byte(c.digest.Sum(nil))))
+	c.digest.Reset()
+	c.chunkLen = 0
+}
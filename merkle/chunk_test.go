@@ -0,0 +1,196 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func chunkTestFiles(chunkSize int) [][]byte {
+	var files [][]byte
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 5*chunkSize + 3} {
+		files = append(files, bytes.Repeat([]byte(fmt.Sprintf("%d", size%10)), size))
+	}
+	return files
+}
+
+func TestNominalChunkedTree(t *testing.T) {
+	chunkSize := 16
+	files := chunkTestFiles(chunkSize)
+	for _, mode := range treeModes {
+		tree := MerkleTree{Mode: mode, Version: TreeV2, ChunkSize: chunkSize}
+		if err := tree.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		unchunked := MerkleTree{Mode: mode, Version: TreeV2}
+		if err := unchunked.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		if tree.GetMerkleRoot() == unchunked.GetMerkleRoot() {
+			t.Errorf("chunked and unchunked trees should have different roots in mode %v", mode)
+		}
+
+		builder, err := NewMerkleTreeBuilder(mode, TreeV2, tree.Hash, chunkSize)
+		if err != nil {
+			t.Errorf("error when creating builder: %v", err)
+			t.FailNow()
+		}
+		for _, file := range files {
+			if err := builder.AddFileFromReader(bytes.NewReader(file)); err != nil {
+				t.Errorf("error when adding file from reader: %v", err)
+				t.FailNow()
+			}
+		}
+		built, err := builder.Build()
+		if err != nil {
+			t.Errorf("error when building tree: %v", err)
+			t.FailNow()
+		}
+		if built.GetMerkleRoot() != tree.GetMerkleRoot() {
+			t.Errorf("builder root different from full build in mode %v", mode)
+		}
+
+		for i, file := range files {
+			var proof *MerkleProof
+			if mode.IsIndexed() {
+				proof, err = tree.GenerateProofForFileNum(i + 1)
+			} else {
+				proof, err = tree.GenerateProofForFile(file)
+			}
+			if err != nil {
+				t.Errorf("error when generating proof: %v", err)
+				t.FailNow()
+			}
+			if !proof.VerifyFileProof(file, tree.GetMerkleRoot()) {
+				t.Errorf("failed to verify proof for file %v in mode %v", i+1, mode)
+			}
+		}
+	}
+}
+
+func TestChunkRangeProof(t *testing.T) {
+	chunkSize := 16
+	files := chunkTestFiles(chunkSize)
+	tree := MerkleTree{Mode: IndexedTree, Version: TreeV2, ChunkSize: chunkSize}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	// last file has 6 chunks, prove chunks 2 to 4
+	file := files[5]
+	chunkTree, err := BuildChunkTree(bytes.NewReader(file), chunkSize, TreeV2, tree.Hash)
+	if err != nil {
+		t.Errorf("error when generating chunk tree: %v", err)
+		t.FailNow()
+	}
+	if chunkTree.NbLeafs() != NbChunks(int64(len(file)), chunkSize) {
+		t.Errorf("chunk tree has %v leafs, expected %v", chunkTree.NbLeafs(), NbChunks(int64(len(file)), chunkSize))
+	}
+	chunksProof, err := chunkTree.GenerateMultiProofForFileNums([]int{2, 3, 4})
+	if err != nil {
+		t.Errorf("error when generating multiproof: %v", err)
+		t.FailNow()
+	}
+	chunks := [][]byte{file[chunkSize : 2*chunkSize], file[2*chunkSize : 3*chunkSize], file[3*chunkSize : 4*chunkSize]}
+	if !chunksProof.VerifyFilesMultiProof(chunks, chunkTree.GetMerkleRoot()) {
+		t.Errorf("failed to verify chunks multiproof")
+	}
+	fileProof, err := tree.GenerateProofForFileNum(6)
+	if err != nil {
+		t.Errorf("error when generating proof: %v", err)
+		t.FailNow()
+	}
+	if !fileProof.VerifyContentHashProof(chunkTree.GetMerkleRoot(), tree.GetMerkleRoot()) {
+		t.Errorf("failed to verify chunk tree root proof")
+	}
+
+	chunks[1] = bytes.Repeat([]byte("X"), chunkSize)
+	if chunksProof.VerifyFilesMultiProof(chunks, chunkTree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with altered chunk, got success")
+	}
+	if _, err := BuildChunkTree(bytes.NewReader(file), 0, TreeV2, tree.Hash); err == nil {
+		t.Errorf("BuildChunkTree should return error for empty chunks")
+	}
+}
+
+func TestChunksOfRange(t *testing.T) {
+	first, last, err := ChunksOfRange(20, 30, 100, 16)
+	if err != nil {
+		t.Errorf("error when getting chunks of range: %v", err)
+		t.FailNow()
+	}
+	if first != 1 || last != 3 {
+		t.Errorf("range 20-50 is covered by chunks %v-%v, expected 1-3", first, last)
+	}
+	if _, last, err := ChunksOfRange(0, 100, 100, 16); err != nil || last != 6 {
+		t.Errorf("range covering the whole file should end at chunk 6, got %v with error %v", last, err)
+	}
+	invalid := [][2]int64{
+		{-1, 10},
+		{0, 0},
+		{90, 11},
+		{101, 1},
+		// offset+length overflows to a negative sum
+		{math.MaxInt64, 1},
+		{1, math.MaxInt64},
+	}
+	for _, r := range invalid {
+		if _, _, err := ChunksOfRange(r[0], r[1], 100, 16); err == nil {
+			t.Errorf("range of %v bytes at offset %v should return error", r[1], r[0])
+		}
+	}
+}
+
+func TestContentHasher(t *testing.T) {
+	chunkSize := 16
+	for _, file := range chunkTestFiles(chunkSize) {
+		// chunk tree is an indexed tree of the chunks of the file
+		var chunks [][]byte
+		for i := 0; i < len(file); i += chunkSize {
+			chunks = append(chunks, file[i:min(i+chunkSize, len(file))])
+		}
+		if len(chunks) == 0 {
+			chunks = [][]byte{{}}
+		}
+		expected := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+		if err := expected.BuildMerkleTree(chunks); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+
+		tree := MerkleTree{Mode: IndexedTree, Version: TreeV2, ChunkSize: chunkSize}
+		// writes of every size, crossing chunk boundaries or not
+		for _, writeSize := range []int{1, 7, chunkSize, chunkSize + 5, len(file) + 1} {
+			hasher, err := tree.NewContentHasher()
+			if err != nil {
+				t.Errorf("error when creating content hasher: %v", err)
+				t.FailNow()
+			}
+			for i := 0; i < len(file); i += writeSize {
+				hasher.Write(file[i:min(i+writeSize, len(file))])
+			}
+			if hasher.Sum() != expected.GetMerkleRoot() {
+				t.Errorf("wrong content hash for file of size %v written by %v bytes", len(file), writeSize)
+			}
+			if !slices.Equal(hasher.ChunkTree().Hashes, expected.Hashes) {
+				t.Errorf("wrong chunk tree for file of size %v written by %v bytes", len(file), writeSize)
+			}
+		}
+
+		unchunked := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+		hasher, err := unchunked.NewContentHasher()
+		if err != nil {
+			t.Errorf("error when creating content hasher: %v", err)
+			t.FailNow()
+		}
+		hasher.Write(file)
+		contentHash, _ := unchunked.ContentHash(file)
+		if hasher.Sum() != contentHash || hasher.ChunkTree() != nil {
+			t.Errorf("wrong content hash for unchunked file of size %v", len(file))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// MerkleConsistencyProof proves that the tree of OldSize leafs is a prefix of the tree of NewSize leafs,
+// following RFC 6962. Only indexed trees and mountain ranges can be proven consistent, since leafs of
+// sorted trees are reordered when the tree grows.
+type MerkleConsistencyProof struct {
+	Hashes  [][32]byte
+	OldSize int
+	NewSize int
+	Version TreeVersion
+	Hash    cr.HashAlgorithm
+}
+
+func (m MerkleTree) GenerateConsistencyProof(oldSize int) (*MerkleConsistencyProof, error) {
+	if !m.Mode.IsIndexed() {
+		return nil, errors.New("consistency proofs are only supported by indexed trees")
+	}
+	nbLeafs, node, err := m.indexedNodes()
+	if err != nil {
+		return nil, err
+	}
+	if oldSize < 1 || oldSize > nbLeafs {
+		return nil, fmt.Errorf("old tree size %v out of range, tree has %v leafs", oldSize, nbLeafs)
+	}
+	proof := [][32]byte{}
+	if oldSize < nbLeafs {
+		proof = consistencySubProof(node, oldSize, 0, nbLeafs, true)
+	}
+	return &MerkleConsistencyProof{
+		Hashes:  proof,
+		OldSize: oldSize,
+		NewSize: nbLeafs,
+		Version: m.Version,
+		Hash:    m.Hash,
+	}, nil
+}
+
+// verifies that oldRoot is the root of the first p.OldSize leafs of the tree of root newRoot
+func (p MerkleConsistencyProof) VerifyConsistency(oldRoot [32]byte, newRoot [32]byte) bool {
+	if p.OldSize < 1 || p.OldSize > p.NewSize {
+		return false
+	}
+	if p.OldSize == p.NewSize {
+		return len(p.Hashes) == 0 && oldRoot == newRoot
+	}
+	h, err := newTreeHashing(p.Version, p.Hash, 0)
+	if err != nil {
+		return false
+	}
+	// verification algorithm of RFC 9162, section 2.1.4.2
+	proof := p.Hashes
+	if p.OldSize&(p.OldSize-1) == 0 {
+		// old tree is a complete subtree of the new tree, its root is not part of the proof
+		proof = append([][32]byte{oldRoot}, proof...)
+	}
+	if len(proof) == 0 {
+		return false
+	}
+	fn := p.OldSize - 1
+	sn := p.NewSize - 1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr := proof[0]
+	sr := proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = h.nodeHash(c, fr)
+			sr = h.nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = h.nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && fr == oldRoot && sr == newRoot
+}
+
+func (p MerkleConsistencyProof) GetProofInHex() []string {
+	var hexProof []string
+	for _, hash := range p.Hashes {
+		hexProof = append(hexProof, hex.EncodeToString(hash[:]))
+	}
+	return hexProof
+}
+
+// SUBPROOF of RFC 6962, for the leafs [start, end) of the tree
+func consistencySubProof(node func(level int, index int) [32]byte, oldSize int, start int, end int, complete bool) [][32]byte {
+	size := end - start
+	if oldSize == size {
+		if complete {
+			return [][32]byte{}
+		}
+		return [][32]byte{indexedSubtreeHash(node, start, end)}
+	}
+	// largest power of two smaller than size
+	k := 1 << (bits.Len(uint(size-1)) - 1)
+	if oldSize <= k {
+		proof := consistencySubProof(node, oldSize, start, start+k, complete)
+		return append(proof, indexedSubtreeHash(node, start+k, end))
+	}
+	proof := consistencySubProof(node, oldSize-k, start+k, end, false)
+	return append(proof, indexedSubtreeHash(node, start, start+k))
+}
+
+// root of the subtree holding the leafs [start, end). Subtrees reached by consistency proofs are always
+// nodes of the tree: they start on a multiple of their width, and are either complete or on the right edge.
+func indexedSubtreeHash(node func(level int, index int) [32]byte, start int, end int) [32]byte {
+	level := bits.Len(uint(end - start - 1))
+	return node(level, start>>level)
+}
@@ -0,0 +1,110 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+func TestNominalConsistencyProof(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 33; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	for _, version := range treeVersions {
+		var roots [][32]byte
+		var trees []MerkleTree
+		for n := 1; n <= len(files); n++ {
+			tree := MerkleTree{Mode: IndexedTree, Version: version}
+			if err := tree.BuildMerkleTree(files[:n]); err != nil {
+				t.Errorf("error when generating tree: %v", err)
+				t.FailNow()
+			}
+			roots = append(roots, tree.GetMerkleRoot())
+			trees = append(trees, tree)
+		}
+		for n := 1; n <= len(files); n++ {
+			for m := 1; m <= n; m++ {
+				proof, err := trees[n-1].GenerateConsistencyProof(m)
+				if err != nil {
+					t.Errorf("error when generating consistency proof: %v", err)
+					t.FailNow()
+				}
+				if !proof.VerifyConsistency(roots[m-1], roots[n-1]) {
+					t.Errorf("failed to verify consistency from %v to %v leafs", m, n)
+				}
+			}
+		}
+	}
+}
+
+func TestFailConsistencyProofVerification(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 20; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	oldTree := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+	if err := oldTree.BuildMerkleTree(files[:7]); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	newTree := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+	if err := newTree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	proof, err := newTree.GenerateConsistencyProof(7)
+	if err != nil {
+		t.Errorf("error when generating consistency proof: %v", err)
+		t.FailNow()
+	}
+	if !proof.VerifyConsistency(oldTree.GetMerkleRoot(), newTree.GetMerkleRoot()) {
+		t.Errorf("failed to verify consistency proof")
+	}
+
+	// one of the stored files was rewritten
+	rewritten := append([][]byte{}, files...)
+	rewritten[3] = []byte("REWRITTEN")
+	rewrittenTree := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+	if err := rewrittenTree.BuildMerkleTree(rewritten); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	rewrittenProof, err := rewrittenTree.GenerateConsistencyProof(7)
+	if err != nil {
+		t.Errorf("error when generating consistency proof: %v", err)
+		t.FailNow()
+	}
+	if rewrittenProof.VerifyConsistency(oldTree.GetMerkleRoot(), rewrittenTree.GetMerkleRoot()) {
+		t.Errorf("expected consistency verification to fail for rewritten tree, got success")
+	}
+
+	altered := *proof
+	altered.OldSize = 6
+	if altered.VerifyConsistency(oldTree.GetMerkleRoot(), newTree.GetMerkleRoot()) {
+		t.Errorf("expected consistency verification to fail with altered size, got success")
+	}
+	altered = *proof
+	altered.Hashes = altered.Hashes[1:]
+	if altered.VerifyConsistency(oldTree.GetMerkleRoot(), newTree.GetMerkleRoot()) {
+		t.Errorf("expected consistency verification to fail with missing hash, got success")
+	}
+	altered = *proof
+	altered.Hash = cr.Keccak256
+	if altered.VerifyConsistency(oldTree.GetMerkleRoot(), newTree.GetMerkleRoot()) {
+		t.Errorf("expected consistency verification to fail with altered hash algorithm, got success")
+	}
+
+	if _, err := newTree.GenerateConsistencyProof(21); err == nil {
+		t.Errorf("GenerateConsistencyProof should return error for old size larger than tree")
+	}
+	sortedTree := MerkleTree{Mode: SortedTree}
+	if err := sortedTree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	if _, err := sortedTree.GenerateConsistencyProof(7); err == nil {
+		t.Errorf("GenerateConsistencyProof should return error for sorted trees")
+	}
+}
@@ -0,0 +1,128 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+type TreeVersion int32
+
+const (
+	// leafs are hashed twice and nodes once, without domain separation
+	TreeV1 TreeVersion = 0
+	// leafs and nodes are prefixed before hashing, as in RFC 6962, and leafs are hashed once
+	TreeV2 TreeVersion = 1
+	// leafs and nodes of OpenZeppelin's StandardMerkleTree, with keccak256 only. A leaf is the double hash of the ABI
+	// encoding of its values: the content hash as bytes32 in sorted trees, preceded by the sequence number as uint256
+	// in indexed trees. Nodes are hashed without prefix
+	TreeOpenZeppelin TreeVersion = 2
+)
+
+const (
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+// treeHashing gathers the format version, hash function and chunk size used for the leafs and nodes of a tree
+type treeHashing struct {
+	version   TreeVersion
+	hasher    cr.Hasher
+	chunkSize int
+}
+
+func newTreeHashing(version TreeVersion, algorithm cr.HashAlgorithm, chunkSize int) (treeHashing, error) {
+	hasher, err := cr.NewHasher(algorithm)
+	if err != nil {
+		return treeHashing{}, err
+	}
+	if chunkSize < 0 {
+		return treeHashing{}, fmt.Errorf("invalid chunk size %v", chunkSize)
+	}
+	if version < TreeV1 || version > TreeOpenZeppelin {
+		return treeHashing{}, fmt.Errorf("unknown tree version %v", version)
+	}
+	if version == TreeOpenZeppelin && algorithm != cr.Keccak256 {
+		return treeHashing{}, errors.New("openzeppelin trees only use keccak256")
+	}
+	return treeHashing{version: version, hasher: hasher, chunkSize: chunkSize}, nil
+}
+
+// hash committed by the leaf of a file, the root of its chunk tree when files are chunked
+func (h treeHashing) contentHash(file []byte) [32]byte {
+	if h.chunkSize > 0 {
+		// reading from memory cannot fail
+		root, _ := h.chunkRoot(bytes.NewReader(file))
+		return root
+	}
+	return h.hasher.HashOnce(file)
+}
+
+func (h treeHashing) contentHashFromReader(r io.Reader) ([32]byte, error) {
+	if h.chunkSize > 0 {
+		return h.chunkRoot(r)
+	}
+	return h.hasher.HashOnceFromReader(r)
+}
+
+func (h treeHashing) sortedLeafHash(contentHash [32]byte) [32]byte {
+	if h.version == TreeOpenZeppelin {
+		// ABI encoding of a bytes32 is the value itself
+		return h.hasher.HashTwice(contentHash[:])
+	}
+	if h.version == TreeV2 {
+		var buffer [33]byte
+		buffer[0] = leafPrefix
+		copy(buffer[1:], contentHash[:])
+		return h.hasher.HashOnce(buffer[:])
+	}
+	// equivalent to hashing the file content twice
+	return h.hasher.HashOnce(contentHash[:])
+}
+
+func (h treeHashing) indexedLeafHash(seq int, contentHash [32]byte) [32]byte {
+	if h.version == TreeOpenZeppelin {
+		// ABI encoding of (uint256, bytes32)
+		var buffer [64]byte
+		binary.BigEndian.PutUint32(buffer[28:32], uint32(seq))
+		copy(buffer[32:], contentHash[:])
+		return h.hasher.HashTwice(buffer[:])
+	}
+	if h.version == TreeV2 {
+		var buffer [37]byte
+		buffer[0] = leafPrefix
+		binary.BigEndian.PutUint32(buffer[1:5], uint32(seq))
+		copy(buffer[5:], contentHash[:])
+		return h.hasher.HashOnce(buffer[:])
+	}
+	var buffer [36]byte
+	binary.BigEndian.PutUint32(buffer[:4], uint32(seq))
+	copy(buffer[4:], contentHash[:])
+	return h.hasher.HashTwice(buffer[:])
+}
+
+func (h treeHashing) nodeHash(left [32]byte, right [32]byte) [32]byte {
+	if h.version == TreeV2 {
+		var buffer [65]byte
+		buffer[0] = nodePrefix
+		copy(buffer[1:33], left[:])
+		copy(buffer[33:], right[:])
+		return h.hasher.HashOnce(buffer[:])
+	}
+	var buffer [64]byte
+	copy(buffer[:32], left[:])
+	copy(buffer[32:], right[:])
+	return h.hasher.HashOnce(buffer[:])
+}
+
+// hashes a pair of nodes in sorted order, as done in sorted trees
+func (h treeHashing) sortedNodeHash(hash1 [32]byte, hash2 [32]byte) [32]byte {
+	if cr.CompareHashes(hash1, hash2) {
+		return h.nodeHash(hash1, hash2)
+	}
+	return h.nodeHash(hash2, hash1)
+}
@@ -0,0 +1,133 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+func TestTreeV2Shape(t *testing.T) {
+	// testData
+	files := [][]byte{[]byte("TEST1"), []byte("TEST2"), []byte("TEST3")}
+	var sortedLeafs, indexedLeafs [][32]byte
+	for i, file := range files {
+		contentHash := cr.HashOnce(file)
+		sortedLeafs = append(sortedLeafs, cr.HashOnce(append([]byte{0x00}, contentHash[:]...)))
+		seq := binary.BigEndian.AppendUint32([]byte{0x00}, uint32(i+1))
+		indexedLeafs = append(indexedLeafs, cr.HashOnce(append(seq, contentHash[:]...)))
+	}
+	hashNode := func(left, right [32]byte) [32]byte {
+		buffer := append([]byte{0x01}, left[:]...)
+		return cr.HashOnce(append(buffer, right[:]...))
+	}
+
+	indexed := MerkleTree{Mode: IndexedTree, Version: TreeV2}
+	if err := indexed.BuildMerkleTree(files); err != nil {
+		t.Errorf("error occured when building tree: %v", err)
+		t.FailNow()
+	}
+	if indexed.GetMerkleRoot() != hashNode(hashNode(indexedLeafs[0], indexedLeafs[1]), indexedLeafs[2]) {
+		t.Errorf("indexed merkle roots do not match")
+	}
+
+	sorted := MerkleTree{Mode: SortedTree, Version: TreeV2}
+	if err := sorted.BuildMerkleTree(files[:2]); err != nil {
+		t.Errorf("error occured when building tree: %v", err)
+		t.FailNow()
+	}
+	left, right := sortedLeafs[0], sortedLeafs[1]
+	if !cr.CompareHashes(left, right) {
+		left, right = right, left
+	}
+	if sorted.GetMerkleRoot() != hashNode(left, right) {
+		t.Errorf("sorted merkle roots do not match")
+	}
+}
+
+func TestTreeVersionsDiffer(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 10; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	for _, mode := range treeModes {
+		treeV1 := MerkleTree{Mode: mode, Version: TreeV1}
+		treeV2 := MerkleTree{Mode: mode, Version: TreeV2}
+		if err := treeV1.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		if err := treeV2.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		if treeV1.GetMerkleRoot() == treeV2.GetMerkleRoot() {
+			t.Errorf("trees of different versions should have different roots in mode %v", mode)
+		}
+		var proof *MerkleProof
+		var err error
+		if mode.IsIndexed() {
+			proof, err = treeV2.GenerateProofForFileNum(4)
+		} else {
+			proof, err = treeV2.GenerateProofForFile(files[3])
+		}
+		if err != nil {
+			t.Errorf("error when generating proof: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyFileProof(files[3], treeV2.GetMerkleRoot()) {
+			t.Errorf("failed to verify proof in mode %v", mode)
+		}
+		// proof verified with the hashing rules of the other version
+		proof.Version = TreeV1
+		if proof.VerifyFileProof(files[3], treeV2.GetMerkleRoot()) {
+			t.Errorf("expected proof verification to fail with altered version, got success")
+		}
+	}
+}
+
+func TestHashAlgorithms(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 10; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	algorithms := []cr.HashAlgorithm{cr.SHA256, cr.SHA512_256, cr.BLAKE2b256, cr.Keccak256}
+	for _, mode := range treeModes {
+		roots := make(map[[32]byte]bool)
+		for _, algorithm := range algorithms {
+			tree := MerkleTree{Mode: mode, Version: TreeV2, Hash: algorithm}
+			if err := tree.BuildMerkleTree(files); err != nil {
+				t.Errorf("error when generating tree: %v", err)
+				t.FailNow()
+			}
+			roots[tree.GetMerkleRoot()] = true
+			var proof *MerkleProof
+			var err error
+			if mode.IsIndexed() {
+				proof, err = tree.GenerateProofForFileNum(6)
+			} else {
+				proof, err = tree.GenerateProofForFile(files[5])
+			}
+			if err != nil {
+				t.Errorf("error when generating proof: %v", err)
+				t.FailNow()
+			}
+			if !proof.VerifyFileProof(files[5], tree.GetMerkleRoot()) {
+				t.Errorf("failed to verify proof with algorithm %v in mode %v", algorithm, mode)
+			}
+			// proof verified with another hash function
+			proof.Hash = (algorithm + 1) % cr.HashAlgorithm(len(algorithms))
+			if proof.VerifyFileProof(files[5], tree.GetMerkleRoot()) {
+				t.Errorf("expected proof verification to fail with altered hash algorithm, got success")
+			}
+		}
+		if len(roots) != len(algorithms) {
+			t.Errorf("trees built with different hash algorithms should have different roots in mode %v", mode)
+		}
+	}
+	tree := MerkleTree{Hash: cr.HashAlgorithm(42)}
+	if err := tree.BuildMerkleTree(files); err == nil {
+		t.Errorf("BuildMerkleTree should return error for unknown hash algorithm")
+	}
+}
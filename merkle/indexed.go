@@ -0,0 +1,285 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Indexed trees keep leafs in file order, and each leaf commits to the sequence number of its file.
+// Levels are built by hashing pairs of nodes from left to right, and a node without sibling is promoted
+// to the next level unchanged, which gives the same shape as RFC 6962 trees.
+// Levels are stored from the root down to the leafs, so that the root is the first node of the tree.
+
+// sizes of the levels of an indexed tree, from the leafs to the root
+func indexedTreeLevels(nbLeafs int) []int {
+	levels := []int{nbLeafs}
+	for size := nbLeafs; size > 1; {
+		size = (size + 1) / 2
+		levels = append(levels, size)
+	}
+	return levels
+}
+
+// position of the first node of each level in the tree buffer
+func indexedTreeOffsets(levels []int) []int {
+	offsets := make([]int, len(levels))
+	offset := 0
+	for level := len(levels) - 1; level >= 0; level-- {
+		offsets[level] = offset
+		offset += levels[level]
+	}
+	return offsets
+}
+
+func indexedTreeSize(nbLeafs int) int {
+	size := 0
+	for _, levelSize := range indexedTreeLevels(nbLeafs) {
+		size += levelSize
+	}
+	return size
+}
+
+// returns -1 if no indexed tree has the given number of nodes
+func indexedTreeNbLeafs(treeSize int) int {
+	low := 1
+	high := treeSize
+	// tree size is strictly increasing with the number of leafs
+	for low <= high {
+		median := (low + high) / 2
+		size := indexedTreeSize(median)
+		if size == treeSize {
+			return median
+		} else if size < treeSize {
+			low = median + 1
+		} else {
+			high = median - 1
+		}
+	}
+	return -1
+}
+
+func indexedTreeFromLeafs(h treeHashing, leafs [][32]byte) [][32]byte {
+	levels := indexedTreeLevels(len(leafs))
+	offsets := indexedTreeOffsets(levels)
+	tree := make([][32]byte, offsets[0]+len(leafs))
+	copy(tree[offsets[0]:], leafs)
+	for level := 1; level < len(levels); level++ {
+		hashInParallel(0, levels[level], func(i int) {
+			left := offsets[level-1] + 2*i
+			if 2*i+1 < levels[level-1] {
+				tree[offsets[level]+i] = h.nodeHash(tree[left], tree[left+1])
+			} else {
+				// no sibling, promote node
+				tree[offsets[level]+i] = tree[left]
+			}
+		})
+	}
+	return tree
+}
+
+// number of leafs and nodes of the tree by level and position in level, for indexed trees and mountain ranges
+func (m MerkleTree) indexedNodes() (int, func(level int, index int) [32]byte, error) {
+	if m.Mode == MountainRangeTree {
+		h, err := m.hashing()
+		if err != nil {
+			return -1, nil, err
+		}
+		nbLeafs := mmrNbLeafs(len(m.Hashes))
+		if nbLeafs < 1 {
+			return -1, nil, errors.New("malformed mountain range")
+		}
+		return nbLeafs, m.mmrIndexedNodes(h, nbLeafs), nil
+	}
+	nbLeafs := indexedTreeNbLeafs(len(m.Hashes))
+	if nbLeafs == -1 {
+		return -1, nil, errors.New("malformed indexed tree")
+	}
+	offsets := indexedTreeOffsets(indexedTreeLevels(nbLeafs))
+	return nbLeafs, func(level int, index int) [32]byte {
+		return m.Hashes[offsets[level]+index]
+	}, nil
+}
+
+func (m MerkleTree) generateIndexedProof(index int) (*MerkleProof, error) {
+	nbLeafs, node, err := m.indexedNodes()
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= nbLeafs {
+		return nil, fmt.Errorf("leaf index %v out of range, tree has %v leafs", index, nbLeafs)
+	}
+	levels := indexedTreeLevels(nbLeafs)
+	proof := [][32]byte{}
+	currentIndex := index
+	for level := 0; level < len(levels)-1; level++ {
+		siblingIndex := currentIndex ^ 1
+		if siblingIndex < levels[level] {
+			proof = append(proof, node(level, siblingIndex))
+		}
+		currentIndex /= 2
+	}
+	return &MerkleProof{
+		Leaf:      node(0, index),
+		Hashes:    proof,
+		Mode:      m.Mode,
+		Version:   m.Version,
+		Hash:      m.Hash,
+		ChunkSize: m.ChunkSize,
+		Index:     index,
+		TreeSize:  nbLeafs,
+	}, nil
+}
+
+func (p MerkleProof) verifyIndexedLeafProof(h treeHashing, leaf [32]byte, merkleRoot [32]byte) bool {
+	if p.Index < 0 || p.Index >= p.TreeSize {
+		return false
+	}
+	buff := leaf
+	currentIndex := p.Index
+	proofPos := 0
+	for size := p.TreeSize; size > 1; size = (size + 1) / 2 {
+		if currentIndex%2 == 1 {
+			if proofPos == len(p.Hashes) {
+				return false
+			}
+			buff = h.nodeHash(p.Hashes[proofPos], buff)
+			proofPos++
+		} else if currentIndex+1 < size {
+			if proofPos == len(p.Hashes) {
+				return false
+			}
+			buff = h.nodeHash(buff, p.Hashes[proofPos])
+			proofPos++
+		}
+		currentIndex /= 2
+	}
+	return proofPos == len(p.Hashes) && buff == merkleRoot
+}
+
+func (m MerkleTree) generateIndexedMultiProof(indexes []int) (*MerkleMultiProof, error) {
+	nbLeafs, node, err := m.indexedNodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		return nil, errors.New("cannot generate proof for empty set of leafs")
+	}
+	levels := indexedTreeLevels(nbLeafs)
+
+	var leafs [][32]byte
+	for _, index := range indexes {
+		if index < 0 || index >= nbLeafs {
+			return nil, fmt.Errorf("leaf index %v out of range, tree has %v leafs", index, nbLeafs)
+		}
+		leafs = append(leafs, node(0, index))
+	}
+	known, err := sortUniqueIndexes(indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := [][32]byte{}
+	for level := 0; level < len(levels)-1; level++ {
+		var parents []int
+		for j := 0; j < len(known); j++ {
+			currentIndex := known[j]
+			if currentIndex%2 == 1 {
+				// left sibling is not known, otherwise it would have consumed this node
+				proof = append(proof, node(level, currentIndex-1))
+			} else if currentIndex+1 < levels[level] {
+				if j+1 < len(known) && known[j+1] == currentIndex+1 {
+					j++
+				} else {
+					proof = append(proof, node(level, currentIndex+1))
+				}
+			}
+			parents = append(parents, currentIndex/2)
+		}
+		known = parents
+	}
+
+	return &MerkleMultiProof{
+		Leafs:     leafs,
+		Hashes:    proof,
+		Mode:      m.Mode,
+		Version:   m.Version,
+		Hash:      m.Hash,
+		ChunkSize: m.ChunkSize,
+		Indexes:   append([]int{}, indexes...),
+		TreeSize:  nbLeafs,
+	}, nil
+}
+
+func (p MerkleMultiProof) verifyIndexedLeafsMultiProof(h treeHashing, leafs [][32]byte, merkleRoot [32]byte) bool {
+	type node struct {
+		index int
+		hash  [32]byte
+	}
+	if len(leafs) == 0 || len(leafs) != len(p.Indexes) {
+		return false
+	}
+	var known []node
+	for i, index := range p.Indexes {
+		if index < 0 || index >= p.TreeSize {
+			return false
+		}
+		known = append(known, node{index: index, hash: leafs[i]})
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].index < known[j].index
+	})
+	for i := 1; i < len(known); i++ {
+		if known[i].index == known[i-1].index {
+			return false
+		}
+	}
+
+	proofPos := 0
+	nextProofHash := func() ([32]byte, bool) {
+		if proofPos == len(p.Hashes) {
+			return [32]byte{}, false
+		}
+		proofPos++
+		return p.Hashes[proofPos-1], true
+	}
+	for size := p.TreeSize; size > 1; size = (size + 1) / 2 {
+		var parents []node
+		for j := 0; j < len(known); j++ {
+			current := known[j]
+			parent := current.hash
+			if current.index%2 == 1 {
+				sibling, ok := nextProofHash()
+				if !ok {
+					return false
+				}
+				parent = h.nodeHash(sibling, current.hash)
+			} else if current.index+1 < size {
+				if j+1 < len(known) && known[j+1].index == current.index+1 {
+					parent = h.nodeHash(current.hash, known[j+1].hash)
+					j++
+				} else {
+					sibling, ok := nextProofHash()
+					if !ok {
+						return false
+					}
+					parent = h.nodeHash(current.hash, sibling)
+				}
+			}
+			parents = append(parents, node{index: current.index / 2, hash: parent})
+		}
+		known = parents
+	}
+	return proofPos == len(p.Hashes) && len(known) == 1 && known[0].hash == merkleRoot
+}
+
+func sortUniqueIndexes(indexes []int) ([]int, error) {
+	sorted := append([]int{}, indexes...)
+	sort.Ints(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return nil, errors.New("cannot prove duplicated leaf")
+		}
+	}
+	return sorted, nil
+}
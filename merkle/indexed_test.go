@@ -0,0 +1,181 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+func TestIndexedTreeShape(t *testing.T) {
+	// testData
+	files := [][]byte{[]byte("TEST1"), []byte("TEST2"), []byte("TEST3")}
+	h, err := newTreeHashing(TreeV1, cr.SHA256, 0)
+	if err != nil {
+		t.Errorf("error occured when creating hasher: %v", err)
+		t.FailNow()
+	}
+	var leafs [][32]byte
+	for i, file := range files {
+		leafs = append(leafs, h.indexedLeafHash(i+1, cr.HashOnce(file)))
+	}
+	// third leaf has no sibling and is promoted
+	expectedRoot := h.nodeHash(h.nodeHash(leafs[0], leafs[1]), leafs[2])
+
+	tree := MerkleTree{Mode: IndexedTree}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error occured when building tree: %v", err)
+		t.FailNow()
+	}
+	if tree.GetMerkleRoot() != expectedRoot {
+		t.Errorf("merkle roots do not match")
+	}
+}
+
+func TestNominalIndexedProof(t *testing.T) {
+	for nbFiles := 1; nbFiles <= 33; nbFiles++ {
+		var files [][]byte
+		for i := 0; i < nbFiles; i++ {
+			files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+		}
+		tree := MerkleTree{Mode: IndexedTree}
+		if err := tree.BuildMerkleTree(files); err != nil {
+			t.Errorf("error occured when generating tree: %v", err)
+			t.FailNow()
+		}
+		for i, file := range files {
+			proof, err := tree.GenerateProofForFileNum(i + 1)
+			if err != nil {
+				t.Errorf("error occured when generating proof: %v", err)
+				t.FailNow()
+			}
+			if proof.Index != i || proof.TreeSize != nbFiles {
+				t.Errorf("proof for file %v has index %v and tree size %v", i+1, proof.Index, proof.TreeSize)
+			}
+			if !proof.VerifyFileProof(file, tree.GetMerkleRoot()) {
+				t.Errorf("failed to verify proof for file %v of %v", i+1, nbFiles)
+			}
+		}
+	}
+}
+
+func TestIndexedProofBindsPosition(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 10; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	tree := MerkleTree{Mode: IndexedTree}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	proof, err := tree.GenerateProofForFileNum(8)
+	if err != nil {
+		t.Errorf("error when generating proof: %v", err)
+		t.FailNow()
+	}
+	// file 3 served in place of file 8
+	if proof.VerifyFileProof(files[2], tree.GetMerkleRoot()) {
+		t.Errorf("expected proof verification to fail for file at another position, got success")
+	}
+	// proof of file 3 presented as a proof for file 8
+	proof, err = tree.GenerateProofForFileNum(3)
+	if err != nil {
+		t.Errorf("error when generating proof: %v", err)
+		t.FailNow()
+	}
+	proof.Index = 7
+	if proof.VerifyFileProof(files[2], tree.GetMerkleRoot()) {
+		t.Errorf("expected proof verification to fail with altered index, got success")
+	}
+}
+
+func TestIndexedTreeIdenticalFiles(t *testing.T) {
+	files := [][]byte{[]byte("SAME"), []byte("OTHER"), []byte("SAME"), []byte("SAME")}
+	tree := MerkleTree{Mode: IndexedTree}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	leafs, err := tree.GetLeafs()
+	if err != nil {
+		t.Errorf("error when reading leafs: %v", err)
+		t.FailNow()
+	}
+	if leafs[0] == leafs[2] || leafs[2] == leafs[3] {
+		t.Errorf("identical files should have distinct leafs")
+	}
+	for i, file := range files {
+		proof, err := tree.GenerateProofForFileNum(i + 1)
+		if err != nil {
+			t.Errorf("error when generating proof: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyFileProof(file, tree.GetMerkleRoot()) {
+			t.Errorf("failed to verify proof for file %v", i+1)
+		}
+	}
+}
+
+func TestNominalIndexedMultiProof(t *testing.T) {
+	for nbFiles := 1; nbFiles <= 12; nbFiles++ {
+		var files [][]byte
+		for i := 0; i < nbFiles; i++ {
+			files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+		}
+		tree := MerkleTree{Mode: IndexedTree}
+		if err := tree.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		// prove every subset of files
+		for subset := 1; subset < 1<<nbFiles; subset++ {
+			var fileNums []int
+			var subsetFiles [][]byte
+			for i := nbFiles - 1; i >= 0; i-- {
+				if subset&(1<<i) != 0 {
+					fileNums = append(fileNums, i+1)
+					subsetFiles = append(subsetFiles, files[i])
+				}
+			}
+			proof, err := tree.GenerateMultiProofForFileNums(fileNums)
+			if err != nil {
+				t.Errorf("error when generating multiproof: %v", err)
+				t.FailNow()
+			}
+			if !proof.VerifyFilesMultiProof(subsetFiles, tree.GetMerkleRoot()) {
+				t.Errorf("failed to verify multiproof for files %v of %v", fileNums, nbFiles)
+			}
+		}
+	}
+}
+
+func TestFailIndexedMultiProofVerification(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 30; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	tree := MerkleTree{Mode: IndexedTree}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	proof, err := tree.GenerateMultiProofForFileNums([]int{2, 3, 21})
+	if err != nil {
+		t.Errorf("error when generating multiproof: %v", err)
+		t.FailNow()
+	}
+	// files swapped between requested positions
+	if proof.VerifyFilesMultiProof([][]byte{files[2], files[1], files[20]}, tree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with swapped files, got success")
+	}
+	if proof.VerifyFilesMultiProof([][]byte{files[1], files[2]}, tree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with missing file, got success")
+	}
+	if _, err := tree.GenerateMultiProofForFileNums([]int{2, 2}); err == nil {
+		t.Errorf("generateIndexedMultiProof should return error for duplicated leafs")
+	}
+	if _, err := tree.GenerateMultiProofForFileNums([]int{31}); err == nil {
+		t.Errorf("generateIndexedMultiProof should return error for out of range leaf")
+	}
+}
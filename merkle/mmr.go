@@ -0,0 +1,90 @@
+package merkle
+
+import (
+	"math/bits"
+)
+
+// Mountain ranges store the nodes of an indexed tree in the order they are created when leafs are appended one at
+// a time: appending a leaf adds the leaf and the parents it completes, and never changes the nodes already stored.
+// The range is a list of perfect trees, its peaks, one for each bit set in the number of leafs, from the largest
+// to the smallest. Peaks are bagged from right to left, which makes the root of a mountain range the root of the
+// indexed tree of the same leafs: proofs of both are generated and verified the same way.
+
+// number of nodes of a mountain range
+func mmrSize(nbLeafs int) int {
+	return 2*nbLeafs - bits.OnesCount(uint(nbLeafs))
+}
+
+// returns -1 if no mountain range has the given number of nodes
+func mmrNbLeafs(size int) int {
+	nbLeafs := 0
+	// a perfect tree is larger than all the smaller perfect trees together, so peaks are found greedily
+	for height := bits.Len(uint(size)); height >= 0; height-- {
+		if peakSize := 1<<(height+1) - 1; size >= peakSize {
+			size -= peakSize
+			nbLeafs += 1 << height
+		}
+	}
+	if size != 0 {
+		return -1
+	}
+	return nbLeafs
+}
+
+// position of the root of the perfect subtree of the given height whose first leaf is index << height
+func mmrNodePosition(height int, index int) int {
+	firstLeaf := index << height
+	return 2*firstLeaf - bits.OnesCount(uint(firstLeaf)) + 1<<(height+1) - 2
+}
+
+// appends the leaf of index nbLeafs, and the parents it completes
+func mmrAppend(h treeHashing, nodes [][32]byte, nbLeafs int, leaf [32]byte) [][32]byte {
+	nodes = append(nodes, leaf)
+	// each trailing one of the leaf index is a peak of the same height on the left of the new node
+	for height := 0; nbLeafs>>height&1 == 1; height++ {
+		left := nodes[len(nodes)-1<<(height+1)]
+		nodes = append(nodes, h.nodeHash(left, nodes[len(nodes)-1]))
+	}
+	return nodes
+}
+
+func mmrFromLeafs(h treeHashing, leafs [][32]byte) [][32]byte {
+	nodes := make([][32]byte, 0, mmrSize(len(leafs)))
+	for i, leaf := range leafs {
+		nodes = mmrAppend(h, nodes, i, leaf)
+	}
+	return nodes
+}
+
+// root of the indexed subtree holding the leafs [start, end), bagging the perfect trees that cover it.
+// Subtrees of indexed trees start on a multiple of their width, so the perfect trees are nodes of the range
+func (m MerkleTree) mmrSubtreeHash(h treeHashing, start int, end int) [32]byte {
+	var peaks [][32]byte
+	for height := bits.Len(uint(end-start)) - 1; height >= 0; height-- {
+		if (end-start)>>height&1 == 1 {
+			peaks = append(peaks, m.Hashes[mmrNodePosition(height, start>>height)])
+			start += 1 << height
+		}
+	}
+	root := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = h.nodeHash(peaks[i], root)
+	}
+	return root
+}
+
+// nodes of the indexed tree of the range, by level and position in level
+func (m MerkleTree) mmrIndexedNodes(h treeHashing, nbLeafs int) func(level int, index int) [32]byte {
+	return func(level int, index int) [32]byte {
+		start := index << level
+		return m.mmrSubtreeHash(h, start, min(start+1<<level, nbLeafs))
+	}
+}
+
+func (m MerkleTree) mmrLeafs(nbLeafs int) [][32]byte {
+	leafs := make([][32]byte, nbLeafs)
+	for i := range leafs {
+		leafs[i] = m.Hashes[mmrNodePosition(0, i)]
+	}
+	return leafs
+}
@@ -0,0 +1,131 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+func TestMountainRangeSizes(t *testing.T) {
+	for nbLeafs := 1; nbLeafs <= 300; nbLeafs++ {
+		if got := mmrNbLeafs(mmrSize(nbLeafs)); got != nbLeafs {
+			t.Errorf("mountain range of %v leafs read as %v leafs", nbLeafs, got)
+		}
+	}
+	// sizes between two consecutive ranges are invalid
+	for _, size := range []int{2, 5, 6, 9, 12, 13, 14} {
+		if mmrNbLeafs(size) != -1 {
+			t.Errorf("size %v should not be a mountain range", size)
+		}
+	}
+}
+
+func TestMountainRangeMatchesIndexedTree(t *testing.T) {
+	for _, version := range treeVersions {
+		var files [][]byte
+		builder, _ := NewMerkleTreeBuilder(MountainRangeTree, version, cr.SHA256, 0)
+		for n := 1; n <= 70; n++ {
+			file := []byte(fmt.Sprintf("TEST%d", n))
+			files = append(files, file)
+			builder.AddFile(file)
+			appended, _ := builder.Build()
+			indexed := MerkleTree{Mode: IndexedTree, Version: version}
+			if err := indexed.BuildMerkleTree(files); err != nil {
+				t.Errorf("error when generating tree: %v", err)
+				t.FailNow()
+			}
+			if len(appended.Hashes) != mmrSize(n) {
+				t.Errorf("mountain range of %v leafs has %v nodes", n, len(appended.Hashes))
+			}
+			// bagged peaks give the root of the indexed tree
+			if appended.GetMerkleRoot() != indexed.GetMerkleRoot() {
+				t.Errorf("mountain range of %v leafs has a different root than the indexed tree", n)
+			}
+		}
+	}
+}
+
+func TestMountainRangeAppendKeepsNodes(t *testing.T) {
+	tree := MerkleTree{Mode: MountainRangeTree, Version: TreeV2}
+	if err := tree.BuildMerkleTree([][]byte{[]byte("TEST0"), []byte("TEST1"), []byte("TEST2")}); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	builder, err := NewMerkleTreeBuilderFromTree(tree)
+	if err != nil {
+		t.Errorf("error when loading tree: %v", err)
+		t.FailNow()
+	}
+	builder.AddFile([]byte("TEST3"))
+	builder.AddFile([]byte("TEST4"))
+	extended, _ := builder.Build()
+	for i, node := range tree.Hashes {
+		if extended.Hashes[i] != node {
+			t.Errorf("node %v changed when appending", i)
+		}
+	}
+	proof, err := extended.GenerateConsistencyProof(3)
+	if err != nil {
+		t.Errorf("error when generating consistency proof: %v", err)
+		t.FailNow()
+	}
+	if !proof.VerifyConsistency(tree.GetMerkleRoot(), extended.GetMerkleRoot()) {
+		t.Errorf("failed to verify consistency of appended range")
+	}
+}
+
+func TestMountainRangeProofs(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 23; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	tree := MerkleTree{Mode: MountainRangeTree, Version: TreeV2}
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	root := tree.GetMerkleRoot()
+	for i, file := range files {
+		proof, err := tree.GenerateProofForFileNum(i + 1)
+		if err != nil {
+			t.Errorf("error when generating proof: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyFileProof(file, root) {
+			t.Errorf("failed to verify proof of file %v", i+1)
+		}
+		if proof.VerifyFileProof(files[(i+1)%len(files)], root) {
+			t.Errorf("proof of file %v verified another file", i+1)
+		}
+	}
+	multiProof, err := tree.GenerateMultiProofForFileNums([]int{2, 9, 16, 17, 23})
+	if err != nil {
+		t.Errorf("error when generating multiproof: %v", err)
+		t.FailNow()
+	}
+	if !multiProof.VerifyFilesMultiProof([][]byte{files[1], files[8], files[15], files[16], files[22]}, root) {
+		t.Errorf("failed to verify multiproof")
+	}
+	for oldSize := 1; oldSize <= len(files); oldSize++ {
+		oldTree := MerkleTree{Mode: MountainRangeTree, Version: TreeV2}
+		oldTree.BuildMerkleTree(files[:oldSize])
+		proof, err := tree.GenerateConsistencyProof(oldSize)
+		if err != nil {
+			t.Errorf("error when generating consistency proof: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyConsistency(oldTree.GetMerkleRoot(), root) {
+			t.Errorf("failed to verify consistency from %v to %v leafs", oldSize, len(files))
+		}
+	}
+}
+
+func TestFailLoadMountainRange(t *testing.T) {
+	if _, err := LoadMerkleTreeFromNodes(make([][32]byte, 5), MountainRangeTree, TreeV2, cr.SHA256, 0); err == nil {
+		t.Errorf("mountain range of 5 nodes should not load")
+	}
+	if _, err := LoadMerkleTreeFromNodes(make([][32]byte, 4), MountainRangeTree, TreeV2, cr.SHA256, 0); err != nil {
+		t.Errorf("error when loading mountain range of 3 leafs: %v", err)
+	}
+}
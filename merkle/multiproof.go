@@ -0,0 +1,220 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// MerkleMultiProof proves several leafs at once.
+// For sorted trees, it follows OpenZeppelin's multiproof format: leafs are ordered by decreasing tree index,
+// which means increasing hash order. For indexed trees, leafs are given with their positions in the tree,
+// and the verifier deduces which nodes are read from Hashes, so no flags are needed.
+type MerkleMultiProof struct {
+	Leafs      [][32]byte
+	Hashes     [][32]byte
+	ProofFlags []bool
+	Mode       TreeMode
+	Version    TreeVersion
+	Hash       cr.HashAlgorithm
+	ChunkSize  int
+	// positions of the leafs and number of leafs, only used by indexed trees
+	Indexes  []int
+	TreeSize int
+}
+
+func (m MerkleTree) GenerateMultiProofForFiles(files [][]byte) (*MerkleMultiProof, error) {
+	h, err := m.hashing()
+	if err != nil {
+		return nil, err
+	}
+	var contentHashes [][32]byte
+	for _, file := range files {
+		contentHashes = append(contentHashes, h.contentHash(file))
+	}
+	return m.GenerateMultiProofForContentHashes(contentHashes)
+}
+
+// for files hashed as they are read, see ContentHasher
+func (m MerkleTree) GenerateMultiProofForContentHashes(contentHashes [][32]byte) (*MerkleMultiProof, error) {
+	if m.Mode != SortedTree {
+		return nil, errors.New("leafs of indexed trees can only be found by file number")
+	}
+	h, err := m.hashing()
+	if err != nil {
+		return nil, err
+	}
+	var leafs [][32]byte
+	for _, contentHash := range contentHashes {
+		leafs = append(leafs, h.sortedLeafHash(contentHash))
+	}
+	return m.generateMultiProof(leafs)
+}
+
+func (m MerkleTree) GenerateMultiProofForFileNums(fileNums []int) (*MerkleMultiProof, error) {
+	if !m.Mode.IsIndexed() {
+		return nil, errors.New("leafs of sorted trees are not bound to a file number")
+	}
+	if len(m.Hashes) == 0 {
+		return nil, errors.New("cannot generate proof from empty tree")
+	}
+	var indexes []int
+	for _, fileNum := range fileNums {
+		indexes = append(indexes, fileNum-1)
+	}
+	return m.generateIndexedMultiProof(indexes)
+}
+
+// for indexed trees, files must be given in the same order as p.Indexes
+func (p MerkleMultiProof) VerifyFilesMultiProof(files [][]byte, merkleRoot [32]byte) bool {
+	h, err := newTreeHashing(p.Version, p.Hash, p.ChunkSize)
+	if err != nil {
+		return false
+	}
+	var contentHashes [][32]byte
+	for _, file := range files {
+		contentHashes = append(contentHashes, h.contentHash(file))
+	}
+	return p.verifyContentHashesMultiProof(h, contentHashes, merkleRoot)
+}
+
+// for chunked files, content hashes are the roots of the chunk trees of the files
+func (p MerkleMultiProof) VerifyContentHashesMultiProof(contentHashes [][32]byte, merkleRoot [32]byte) bool {
+	h, err := newTreeHashing(p.Version, p.Hash, p.ChunkSize)
+	if err != nil {
+		return false
+	}
+	return p.verifyContentHashesMultiProof(h, contentHashes, merkleRoot)
+}
+
+func (p MerkleMultiProof) verifyContentHashesMultiProof(h treeHashing, contentHashes [][32]byte, merkleRoot [32]byte) bool {
+	if p.Mode.IsIndexed() {
+		if len(contentHashes) != len(p.Indexes) {
+			return false
+		}
+		var leafs [][32]byte
+		for i, contentHash := range contentHashes {
+			leafs = append(leafs, h.indexedLeafHash(p.Indexes[i]+1, contentHash))
+		}
+		return p.verifyIndexedLeafsMultiProof(h, leafs, merkleRoot)
+	}
+	var leafs [][32]byte
+	for _, contentHash := range contentHashes {
+		leafs = append(leafs, h.sortedLeafHash(contentHash))
+	}
+	// files can be given in any order, proof leafs are ordered by hash
+	sort.Slice(leafs, func(i, j int) bool {
+		return cr.CompareHashes(leafs[i], leafs[j])
+	})
+	return p.verifyLeafsMultiProof(h, leafs, merkleRoot)
+}
+
+func (p MerkleMultiProof) GetProofInHex() []string {
+	var hexProof []string
+	for _, hash := range p.Hashes {
+		hexProof = append(hexProof, hex.EncodeToString(hash[:]))
+	}
+	return hexProof
+}
+
+func (m MerkleTree) generateMultiProof(leafs [][32]byte) (*MerkleMultiProof, error) {
+	if len(m.Hashes) == 0 {
+		return nil, errors.New("cannot generate proof from empty tree")
+	}
+	if len(leafs) == 0 {
+		return nil, errors.New("cannot generate proof for empty set of leafs")
+	}
+	// get leafs positions in tree. Files with the same content have equal leafs, which are next to each other, and the
+	// n-th occurrence of a leaf is proven at its n-th position
+	var indexes []int
+	occurrences := make(map[[32]byte]int)
+	firstLeaf := len(m.Hashes) - (len(m.Hashes)+1)/2
+	for _, leaf := range leafs {
+		leafIndex, err := m.getNodeIndex(leaf)
+		if err != nil {
+			return nil, err
+		}
+		if leafIndex == -1 {
+			return nil, errors.New("leaf is not part of the tree")
+		}
+		for leafIndex > firstLeaf && m.Hashes[leafIndex-1] == leaf {
+			leafIndex--
+		}
+		leafIndex += occurrences[leaf]
+		occurrences[leaf]++
+		if leafIndex >= len(m.Hashes) || m.Hashes[leafIndex] != leaf {
+			return nil, errors.New("cannot prove duplicated leaf")
+		}
+		indexes = append(indexes, leafIndex)
+	}
+	// process deepest nodes first
+	sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+	proofLeafs := make([][32]byte, len(indexes))
+	for i, index := range indexes {
+		proofLeafs[i] = m.Hashes[index]
+	}
+
+	var proof [][32]byte
+	var proofFlags []bool
+	// nodes that are known to the verifier, used as a queue
+	queue := append([]int{}, indexes...)
+	for len(queue) > 0 && queue[0] > 0 {
+		currentIndex := queue[0]
+		queue = queue[1:]
+		siblingIndex := getNodeSiblingIndex(currentIndex)
+		if len(queue) > 0 && queue[0] == siblingIndex {
+			// sibling will be computed by verifier
+			proofFlags = append(proofFlags, true)
+			queue = queue[1:]
+		} else {
+			proofFlags = append(proofFlags, false)
+			proof = append(proof, m.Hashes[siblingIndex])
+		}
+		queue = append(queue, getNodeParentIndex(currentIndex))
+	}
+
+	return &MerkleMultiProof{
+		Leafs:      proofLeafs,
+		Hashes:     proof,
+		ProofFlags: proofFlags,
+		Version:    m.Version,
+		Hash:       m.Hash,
+		ChunkSize:  m.ChunkSize,
+	}, nil
+}
+
+func (p MerkleMultiProof) verifyLeafsMultiProof(h treeHashing, leafs [][32]byte, merkleRoot [32]byte) bool {
+	if len(leafs) == 0 {
+		return false
+	}
+	if len(leafs)+len(p.Hashes) != len(p.ProofFlags)+1 {
+		return false
+	}
+	queue := append([][32]byte{}, leafs...)
+	proofPos := 0
+	for _, flag := range p.ProofFlags {
+		a := queue[0]
+		queue = queue[1:]
+		var b [32]byte
+		if flag {
+			if len(queue) == 0 {
+				return false
+			}
+			b = queue[0]
+			queue = queue[1:]
+		} else {
+			if proofPos == len(p.Hashes) {
+				return false
+			}
+			b = p.Hashes[proofPos]
+			proofPos++
+		}
+		queue = append(queue, h.sortedNodeHash(a, b))
+	}
+	if len(queue) != 1 || proofPos != len(p.Hashes) {
+		return false
+	}
+	return queue[0] == merkleRoot
+}
@@ -0,0 +1,140 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNominalMultiProof(t *testing.T) {
+	for nbFiles := 1; nbFiles <= 20; nbFiles++ {
+		var files [][]byte
+		for i := 0; i < nbFiles; i++ {
+			files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+		}
+		var tree MerkleTree
+		if err := tree.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		// prove every subset of consecutive files, including the full set
+		for start := 0; start < nbFiles; start++ {
+			for end := start + 1; end <= nbFiles; end++ {
+				subset := files[start:end]
+				proof, err := tree.GenerateMultiProofForFiles(subset)
+				if err != nil {
+					t.Errorf("error when generating multiproof: %v", err)
+					t.FailNow()
+				}
+				if !proof.VerifyFilesMultiProof(subset, tree.GetMerkleRoot()) {
+					t.Errorf("failed to verify multiproof for files %v-%v of %v", start, end, nbFiles)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiProofFilesOrder(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 30; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	var tree MerkleTree
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	subset := [][]byte{files[25], files[3], files[17], files[4]}
+	proof, err := tree.GenerateMultiProofForFiles(subset)
+	if err != nil {
+		t.Errorf("error when generating multiproof: %v", err)
+		t.FailNow()
+	}
+	reordered := [][]byte{files[4], files[17], files[25], files[3]}
+	if !proof.VerifyFilesMultiProof(reordered, tree.GetMerkleRoot()) {
+		t.Errorf("multiproof verification should not depend on files order")
+	}
+}
+
+func TestFailMultiProofVerification(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 30; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	var tree MerkleTree
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	proof, err := tree.GenerateMultiProofForFiles([][]byte{files[1], files[2], files[20]})
+	if err != nil {
+		t.Errorf("error when generating multiproof: %v", err)
+		t.FailNow()
+	}
+	if proof.VerifyFilesMultiProof([][]byte{files[1], files[2], files[21]}, tree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with wrong file, got success")
+	}
+	if proof.VerifyFilesMultiProof([][]byte{files[1], files[2]}, tree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with missing file, got success")
+	}
+	proof.ProofFlags[0] = !proof.ProofFlags[0]
+	if proof.VerifyFilesMultiProof([][]byte{files[1], files[2], files[20]}, tree.GetMerkleRoot()) {
+		t.Errorf("expected multiproof verification to fail with altered flags, got success")
+	}
+}
+
+func TestNoMultiProofForDuplicates(t *testing.T) {
+	var files [][]byte
+	for i := 0; i < 10; i++ {
+		files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+	}
+	var tree MerkleTree
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	if _, err := tree.GenerateMultiProofForFiles([][]byte{files[1], files[1]}); err == nil {
+		t.Errorf("generateMultiProof should return error for duplicated leafs")
+	}
+	if _, err := tree.GenerateMultiProofForFiles([][]byte{[]byte("NOT IN TREE")}); err == nil {
+		t.Errorf("generateMultiProof should return error for unknown leaf")
+	}
+}
+
+func TestMultiProofForRepeatedContent(t *testing.T) {
+	// files 0, 3 and 6 have the same content, as do files 1 and 5
+	var files [][]byte
+	for i := 0; i < 12; i++ {
+		switch i {
+		case 3, 6:
+			files = append(files, files[0])
+		case 5:
+			files = append(files, files[1])
+		default:
+			files = append(files, []byte(fmt.Sprintf("TEST%d", i)))
+		}
+	}
+	var tree MerkleTree
+	if err := tree.BuildMerkleTree(files); err != nil {
+		t.Errorf("error when generating tree: %v", err)
+		t.FailNow()
+	}
+	for _, subset := range [][][]byte{
+		{files[0], files[3]},
+		{files[0], files[3], files[6]},
+		{files[6], files[1], files[0], files[5], files[2]},
+		files,
+	} {
+		proof, err := tree.GenerateMultiProofForFiles(subset)
+		if err != nil {
+			t.Errorf("error when generating multiproof for repeated content: %v", err)
+			t.FailNow()
+		}
+		if !proof.VerifyFilesMultiProof(subset, tree.GetMerkleRoot()) {
+			t.Errorf("failed to verify multiproof of %v files with repeated content", len(subset))
+		}
+	}
+	// content cannot be proven more times than it is in the tree
+	if _, err := tree.GenerateMultiProofForFiles([][]byte{files[1], files[5], files[1]}); err == nil {
+		t.Errorf("generateMultiProof should return error for leafs requested more times than they are in the tree")
+	}
+}
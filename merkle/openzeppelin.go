@@ -0,0 +1,290 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// Dumps of OpenZeppelin's StandardMerkleTree (@openzeppelin/merkle-tree), as produced by tree.dump() and read by
+// StandardMerkleTree.load(). Values are kept in their original order, and point to their leaf in the tree.
+// Sorted trees of version TreeOpenZeppelin have the same layout: a file is the value [contentHash] of encoding ["bytes32"].
+
+const standardTreeFormat = "standard-v1"
+
+var fileLeafEncoding = []string{"bytes32"}
+
+type StandardTreeDump struct {
+	Format       string              `json:"format"`
+	Tree         []string            `json:"tree"`
+	Values       []StandardTreeValue `json:"values"`
+	LeafEncoding []string            `json:"leafEncoding"`
+}
+
+type StandardTreeValue struct {
+	// strings for addresses, bytes and integers, booleans for bool
+	Value     []any `json:"value"`
+	TreeIndex int   `json:"treeIndex"`
+}
+
+// DumpStandardTree dumps a sorted openzeppelin tree, given the content hashes of its files in file order
+func (m MerkleTree) DumpStandardTree(contentHashes [][32]byte) (*StandardTreeDump, error) {
+	if m.Mode != SortedTree || m.Version != TreeOpenZeppelin {
+		return nil, errors.New("only sorted trees of version openzeppelin can be dumped")
+	}
+	h, err := m.hashing()
+	if err != nil {
+		return nil, err
+	}
+	if len(contentHashes) != m.NbLeafs() {
+		return nil, fmt.Errorf("tree has %v leafs, got %v content hashes", m.NbLeafs(), len(contentHashes))
+	}
+	// leafs are sorted by hash, values with the same hash stay in file order
+	order := make([]int, len(contentHashes))
+	leafs := make([][32]byte, len(contentHashes))
+	for i, contentHash := range contentHashes {
+		order[i] = i
+		leafs[i] = h.sortedLeafHash(contentHash)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return cr.CompareHashes(leafs[order[i]], leafs[order[j]])
+	})
+
+	dump := &StandardTreeDump{
+		Format:       standardTreeFormat,
+		Tree:         make([]string, len(m.Hashes)),
+		Values:       make([]StandardTreeValue, len(contentHashes)),
+		LeafEncoding: fileLeafEncoding,
+	}
+	for i, hash := range m.Hashes {
+		dump.Tree[i] = "0x" + hex.EncodeToString(hash[:])
+	}
+	for leafIndex, valueIndex := range order {
+		treeIndex := len(m.Hashes) - 1 - leafIndex
+		if m.Hashes[treeIndex] != leafs[valueIndex] {
+			return nil, fmt.Errorf("content hash %v is not in the tree", valueIndex)
+		}
+		contentHash := contentHashes[valueIndex]
+		dump.Values[valueIndex] = StandardTreeValue{
+			Value:     []any{"0x" + hex.EncodeToString(contentHash[:])},
+			TreeIndex: treeIndex,
+		}
+	}
+	return dump, nil
+}
+
+// LoadStandardTree validates a dump, as StandardMerkleTree.load() does, and returns its tree.
+// Dumps of any static leaf encoding can be loaded, files can only be proven in trees of encoding ["bytes32"].
+func LoadStandardTree(dump *StandardTreeDump) (*MerkleTree, error) {
+	if dump.Format != standardTreeFormat {
+		return nil, fmt.Errorf("unknown dump format %q", dump.Format)
+	}
+	if len(dump.LeafEncoding) == 0 {
+		return nil, errors.New("dump has no leaf encoding")
+	}
+	if len(dump.Tree)%2 == 0 {
+		return nil, fmt.Errorf("invalid tree size %v", len(dump.Tree))
+	}
+	h, err := newTreeHashing(TreeOpenZeppelin, cr.Keccak256, 0)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([][32]byte, len(dump.Tree))
+	for i, node := range dump.Tree {
+		if nodes[i], err = parseBytes32(node); err != nil {
+			return nil, fmt.Errorf("invalid node %v: %v", i, err)
+		}
+	}
+	// every node with children is the hash of its children
+	for i := 0; 2*i+2 < len(nodes); i++ {
+		if nodes[i] != h.sortedNodeHash(nodes[2*i+1], nodes[2*i+2]) {
+			return nil, fmt.Errorf("invalid node %v: not the hash of its children", i)
+		}
+	}
+
+	nbLeafs := (len(nodes) + 1) / 2
+	if len(dump.Values) != nbLeafs {
+		return nil, fmt.Errorf("tree has %v leafs, dump has %v values", nbLeafs, len(dump.Values))
+	}
+	seen := make(map[int]bool)
+	for i, value := range dump.Values {
+		if value.TreeIndex < nbLeafs-1 || value.TreeIndex >= len(nodes) || seen[value.TreeIndex] {
+			return nil, fmt.Errorf("invalid tree index %v for value %v", value.TreeIndex, i)
+		}
+		seen[value.TreeIndex] = true
+		leaf, err := standardLeafHash(h, dump.LeafEncoding, value.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %v: %v", i, err)
+		}
+		if leaf != nodes[value.TreeIndex] {
+			return nil, fmt.Errorf("leaf of value %v does not match node %v", i, value.TreeIndex)
+		}
+	}
+	// leafs are sorted, as in trees built from files
+	for i := len(nodes) - 1; i > nbLeafs-1; i-- {
+		if bytes.Compare(nodes[i][:], nodes[i-1][:]) > 0 {
+			return nil, errors.New("leafs of the tree are not sorted")
+		}
+	}
+	return &MerkleTree{
+		Hashes:  nodes,
+		Mode:    SortedTree,
+		Version: TreeOpenZeppelin,
+		Hash:    cr.Keccak256,
+	}, nil
+}
+
+// ContentHashes returns the values of a dump of files, in file order
+func (d StandardTreeDump) ContentHashes() ([][32]byte, error) {
+	if len(d.LeafEncoding) != 1 || d.LeafEncoding[0] != fileLeafEncoding[0] {
+		return nil, fmt.Errorf("leaf encoding %v is not the encoding of files", d.LeafEncoding)
+	}
+	var contentHashes [][32]byte
+	for i, value := range d.Values {
+		if len(value.Value) != 1 {
+			return nil, fmt.Errorf("invalid value %v", i)
+		}
+		str, ok := value.Value[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value %v", i)
+		}
+		contentHash, err := parseBytes32(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %v: %v", i, err)
+		}
+		contentHashes = append(contentHashes, contentHash)
+	}
+	return contentHashes, nil
+}
+
+// leaf of StandardMerkleTree: double hash of the ABI encoding of the value. Only static types are supported,
+// they are encoded as one 32 bytes word each
+func standardLeafHash(h treeHashing, encoding []string, value []any) ([32]byte, error) {
+	if len(value) != len(encoding) {
+		return [32]byte{}, fmt.Errorf("value has %v fields, leaf encoding has %v", len(value), len(encoding))
+	}
+	encoded := make([]byte, 0, 32*len(encoding))
+	for i, typ := range encoding {
+		word, err := abiEncodeWord(typ, value[i])
+		if err != nil {
+			return [32]byte{}, err
+		}
+		encoded = append(encoded, word[:]...)
+	}
+	return h.hasher.HashTwice(encoded), nil
+}
+
+func abiEncodeWord(typ string, value any) ([32]byte, error) {
+	var word [32]byte
+	switch {
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return word, fmt.Errorf("%v is not a bool", value)
+		}
+		if b {
+			word[31] = 1
+		}
+	case typ == "address":
+		data, err := parseHexValue(value, 20)
+		if err != nil {
+			return word, err
+		}
+		copy(word[12:], data)
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return word, fmt.Errorf("unsupported type %v", typ)
+		}
+		data, err := parseHexValue(value, size)
+		if err != nil {
+			return word, err
+		}
+		// fixed size bytes are left aligned
+		copy(word[:], data)
+	case strings.HasPrefix(typ, "uint") || strings.HasPrefix(typ, "int"):
+		signed := strings.HasPrefix(typ, "int")
+		bitSize := 256
+		if suffix := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"); suffix != "" {
+			var err error
+			if bitSize, err = strconv.Atoi(suffix); err != nil || bitSize < 8 || bitSize > 256 || bitSize%8 != 0 {
+				return word, fmt.Errorf("unsupported type %v", typ)
+			}
+		}
+		n, err := parseInteger(value)
+		if err != nil {
+			return word, err
+		}
+		// value must fit in the type
+		lower, upper := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(bitSize))
+		if signed {
+			upper.Rsh(upper, 1)
+			lower.Neg(upper)
+		}
+		if n.Cmp(lower) < 0 || n.Cmp(upper) >= 0 {
+			return word, fmt.Errorf("%v out of range for %v", n, typ)
+		}
+		// negative integers are encoded in two's complement on 256 bits
+		if n.Sign() < 0 {
+			n.Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		n.FillBytes(word[:])
+	default:
+		return word, fmt.Errorf("unsupported type %v", typ)
+	}
+	return word, nil
+}
+
+func parseHexValue(value any, size int) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok || !strings.HasPrefix(str, "0x") {
+		return nil, fmt.Errorf("%v is not a hex string", value)
+	}
+	data, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("%v is not %v bytes long", str, size)
+	}
+	return data, nil
+}
+
+func parseBytes32(str string) ([32]byte, error) {
+	data, err := parseHexValue(str, 32)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(data), nil
+}
+
+// integers are dumped as decimal or hex strings, or as JSON numbers
+func parseInteger(value any) (*big.Int, error) {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case json.Number:
+		str = v.String()
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		str = strconv.FormatInt(int64(v), 10)
+	default:
+		return nil, fmt.Errorf("%v is not an integer", value)
+	}
+	n, ok := new(big.Int).SetString(str, 0)
+	if !ok {
+		return nil, fmt.Errorf("%v is not an integer", str)
+	}
+	return n, nil
+}
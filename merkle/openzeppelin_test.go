@@ -0,0 +1,188 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// dump of the example of @openzeppelin/merkle-tree's README, whose root is given by the README
+const readmeDump = `{
+  "format": "standard-v1",
+  "tree": [
+    "0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77",
+    "0xeb02c421cfa48976e66dfb29120745909ea3a0f843456c263cf8f1253483e283",
+    "0xb92c48e9d7abe27fd8dfd6b5dfdbfb1c9a463f80c712b66f3a5180a090cccafc"
+  ],
+  "values": [
+    {"value": ["0x1111111111111111111111111111111111111111", "5000000000000000000"], "treeIndex": 1},
+    {"value": ["0x2222222222222222222222222222222222222222", "2500000000000000000"], "treeIndex": 2}
+  ],
+  "leafEncoding": ["address", "uint256"]
+}`
+
+const readmeRoot = "d4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77"
+
+func TestLoadOpenZeppelinVector(t *testing.T) {
+	var dump StandardTreeDump
+	if err := json.Unmarshal([]byte(readmeDump), &dump); err != nil {
+		t.Errorf("error when parsing dump: %v", err)
+		t.FailNow()
+	}
+	tree, err := LoadStandardTree(&dump)
+	if err != nil {
+		t.Errorf("error when loading dump: %v", err)
+		t.FailNow()
+	}
+	root := tree.GetMerkleRoot()
+	if hex.EncodeToString(root[:]) != readmeRoot {
+		t.Errorf("merkle roots do not match. Expected %v, got %x", readmeRoot, root)
+	}
+	if _, err := dump.ContentHashes(); err == nil {
+		t.Errorf("values of other leaf encodings should not be read as content hashes")
+	}
+}
+
+func TestStandardLeafEncodings(t *testing.T) {
+	h, _ := newTreeHashing(TreeOpenZeppelin, cr.Keccak256, 0)
+	valid := []struct {
+		typ   string
+		value any
+		word  string
+	}{
+		{"bool", true, strings.Repeat("00", 31) + "01"},
+		{"uint8", "255", strings.Repeat("00", 31) + "ff"},
+		{"uint256", json.Number("16"), strings.Repeat("00", 31) + "10"},
+		{"int16", "-1", strings.Repeat("ff", 32)},
+		{"bytes4", "0x01020304", "01020304" + strings.Repeat("00", 28)},
+		{"address", "0x" + strings.Repeat("ab", 20), strings.Repeat("00", 12) + strings.Repeat("ab", 20)},
+	}
+	for _, test := range valid {
+		word, err := abiEncodeWord(test.typ, test.value)
+		if err != nil {
+			t.Errorf("error when encoding %v as %v: %v", test.value, test.typ, err)
+			continue
+		}
+		if hex.EncodeToString(word[:]) != test.word {
+			t.Errorf("wrong encoding of %v as %v: %x", test.value, test.typ, word)
+		}
+	}
+	invalid := []struct {
+		typ   string
+		value any
+	}{
+		{"uint8", "256"},
+		{"int8", "128"},
+		{"uint256", "-1"},
+		{"bytes4", "0x010203"},
+		{"address", "1111111111111111111111111111111111111111"},
+		{"string", "test"},
+		{"bool", "true"},
+	}
+	for _, test := range invalid {
+		if _, err := standardLeafHash(h, []string{test.typ}, []any{test.value}); err == nil {
+			t.Errorf("encoding %v as %v should fail", test.value, test.typ)
+		}
+	}
+}
+
+func TestDumpAndLoadFileTree(t *testing.T) {
+	for nbFiles := 1; nbFiles <= 9; nbFiles++ {
+		var files [][]byte
+		for i := 0; i < nbFiles; i++ {
+			// identical files give identical leafs
+			files = append(files, []byte(fmt.Sprintf("TEST%d", i%4)))
+		}
+		tree := MerkleTree{Mode: SortedTree, Version: TreeOpenZeppelin, Hash: cr.Keccak256}
+		if err := tree.BuildMerkleTree(files); err != nil {
+			t.Errorf("error when generating tree: %v", err)
+			t.FailNow()
+		}
+		var contentHashes [][32]byte
+		for _, file := range files {
+			contentHash, _ := tree.ContentHash(file)
+			contentHashes = append(contentHashes, contentHash)
+		}
+		dump, err := tree.DumpStandardTree(contentHashes)
+		if err != nil {
+			t.Errorf("error when dumping tree: %v", err)
+			t.FailNow()
+		}
+
+		// load from JSON, as a dump written by another implementation would be
+		data, _ := json.Marshal(dump)
+		var parsed StandardTreeDump
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Errorf("error when parsing dump: %v", err)
+			t.FailNow()
+		}
+		loaded, err := LoadStandardTree(&parsed)
+		if err != nil {
+			t.Errorf("error when loading dump of %v files: %v", nbFiles, err)
+			t.FailNow()
+		}
+		if loaded.GetMerkleRoot() != tree.GetMerkleRoot() {
+			t.Errorf("loaded tree of %v files has a different root", nbFiles)
+		}
+		loadedHashes, err := parsed.ContentHashes()
+		if err != nil {
+			t.Errorf("error when reading content hashes: %v", err)
+			t.FailNow()
+		}
+		for i := range contentHashes {
+			if loadedHashes[i] != contentHashes[i] {
+				t.Errorf("content hash %v changed in dump", i)
+			}
+		}
+		// loaded tree proves files as the original tree
+		for _, file := range files {
+			proof, err := loaded.GenerateProofForFile(file)
+			if err != nil {
+				t.Errorf("error when generating proof: %v", err)
+				t.FailNow()
+			}
+			if !proof.VerifyFileProof(file, tree.GetMerkleRoot()) {
+				t.Errorf("failed to verify proof from loaded tree")
+			}
+		}
+	}
+}
+
+func TestFailLoadStandardTree(t *testing.T) {
+	tamper := []func(d *StandardTreeDump){
+		func(d *StandardTreeDump) { d.Format = "simple-v1" },
+		func(d *StandardTreeDump) { d.LeafEncoding = []string{"address", "address"} },
+		func(d *StandardTreeDump) { d.Values[0].Value[1] = "5000000000000000001" },
+		func(d *StandardTreeDump) { d.Values[0].TreeIndex, d.Values[1].TreeIndex = 2, 1 },
+		func(d *StandardTreeDump) { d.Values[1].TreeIndex = 1 },
+		func(d *StandardTreeDump) { d.Values[0].TreeIndex = 0 },
+		func(d *StandardTreeDump) { d.Values = d.Values[:1] },
+		func(d *StandardTreeDump) { d.Tree[0] = "0x" + strings.Repeat("00", 32) },
+		func(d *StandardTreeDump) { d.Tree = d.Tree[:2] },
+		func(d *StandardTreeDump) { d.Tree[1], d.Tree[2] = d.Tree[2], d.Tree[1] },
+	}
+	for i, f := range tamper {
+		var dump StandardTreeDump
+		json.Unmarshal([]byte(readmeDump), &dump)
+		f(&dump)
+		if _, err := LoadStandardTree(&dump); err == nil {
+			t.Errorf("tampered dump %v should not load", i)
+		}
+	}
+}
+
+func TestOpenZeppelinTreesUseKeccak(t *testing.T) {
+	tree := MerkleTree{Mode: SortedTree, Version: TreeOpenZeppelin, Hash: cr.SHA256}
+	if err := tree.BuildMerkleTree([][]byte{[]byte("TEST")}); err == nil {
+		t.Errorf("openzeppelin trees should not be built with sha256")
+	}
+	v2 := MerkleTree{Mode: SortedTree, Version: TreeV2, Hash: cr.Keccak256}
+	v2.BuildMerkleTree([][]byte{[]byte("TEST")})
+	if _, err := v2.DumpStandardTree([][32]byte{{}}); err == nil {
+		t.Errorf("only openzeppelin trees should be dumped")
+	}
+}
@@ -0,0 +1,37 @@
+package merkle
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Nodes of a level only depend on the level below, so trees are built one level at a time. The nodes of a
+// level are split in contiguous ranges, one per worker, so that each worker reads and writes adjacent hashes.
+
+// ranges smaller than this are hashed by the calling goroutine, starting workers would cost more than hashing
+const minHashesPerWorker = 1024
+
+// calls hash for every index of [start, end), using at most GOMAXPROCS goroutines
+func hashInParallel(start int, end int, hash func(i int)) {
+	nbHashes := end - start
+	nbWorkers := min(runtime.GOMAXPROCS(0), nbHashes/minHashesPerWorker)
+	if nbWorkers < 2 {
+		for i := start; i < end; i++ {
+			hash(i)
+		}
+		return
+	}
+	batchSize := (nbHashes + nbWorkers - 1) / nbWorkers
+	var wg sync.WaitGroup
+	for low := start; low < end; low += batchSize {
+		high := min(low+batchSize, end)
+		wg.Add(1)
+		go func(low int, high int) {
+			defer wg.Done()
+			for i := low; i < high; i++ {
+				hash(i)
+			}
+		}(low, high)
+	}
+	wg.Wait()
+}
@@ -8,13 +8,43 @@ import (
 )
 
 type MerkleProof struct {
-	Leaf   [32]byte
-	Hashes [][32]byte
+	Leaf    [32]byte
+	Hashes  [][32]byte
+	Mode    TreeMode
+	Version TreeVersion
+	Hash    cr.HashAlgorithm
+	// size of the chunks of each file, files are not chunked when 0
+	ChunkSize int
+	// position of the leaf and number of leafs, only used by indexed trees and mountain ranges
+	Index    int
+	TreeSize int
 }
 
 func (p MerkleProof) VerifyFileProof(file []byte, merkleRoot [32]byte) bool {
-	leaf := cr.HashTwice(file)
-	return p.verifyLeafProof(leaf, merkleRoot)
+	h, err := newTreeHashing(p.Version, p.Hash, p.ChunkSize)
+	if err != nil {
+		return false
+	}
+	return p.verifyContentHashProof(h, h.contentHash(file), merkleRoot)
+}
+
+// for chunked files, contentHash is the root of the chunk tree of the file
+func (p MerkleProof) VerifyContentHashProof(contentHash [32]byte, merkleRoot [32]byte) bool {
+	h, err := newTreeHashing(p.Version, p.Hash, p.ChunkSize)
+	if err != nil {
+		return false
+	}
+	return p.verifyContentHashProof(h, contentHash, merkleRoot)
+}
+
+func (p MerkleProof) verifyContentHashProof(h treeHashing, contentHash [32]byte, merkleRoot [32]byte) bool {
+	if p.Mode.IsIndexed() {
+		// proof is only valid for the file at position p.Index
+		leaf := h.indexedLeafHash(p.Index+1, contentHash)
+		return p.verifyIndexedLeafProof(h, leaf, merkleRoot)
+	}
+	leaf := h.sortedLeafHash(contentHash)
+	return p.verifyLeafProof(h, leaf, merkleRoot)
 }
 
 func (p MerkleProof) GetProofInHex() []string {
@@ -36,8 +66,11 @@ func (m MerkleTree) generateProof(leaf [32]byte) (*MerkleProof, error) {
 	}
 	if leafIndex == 0 {
 		return &MerkleProof{
-			Leaf:   leaf,
-			Hashes: [][32]byte{},
+			Leaf:      leaf,
+			Hashes:    [][32]byte{},
+			Version:   m.Version,
+			Hash:      m.Hash,
+			ChunkSize: m.ChunkSize,
 		}, nil
 	}
 	if leafIndex == -1 {
@@ -51,15 +84,18 @@ func (m MerkleTree) generateProof(leaf [32]byte) (*MerkleProof, error) {
 		currentIndex = getNodeParentIndex(currentIndex)
 	}
 	return &MerkleProof{
-		Leaf:   leaf,
-		Hashes: proof,
+		Leaf:      leaf,
+		Hashes:    proof,
+		Version:   m.Version,
+		Hash:      m.Hash,
+		ChunkSize: m.ChunkSize,
 	}, nil
 }
 
-func (p MerkleProof) verifyLeafProof(leaf [32]byte, merkleRoot [32]byte) bool {
+func (p MerkleProof) verifyLeafProof(h treeHashing, leaf [32]byte, merkleRoot [32]byte) bool {
 	buff := leaf
 	for _, hash := range p.Hashes {
-		buff = concatAndHash(buff, hash)
+		buff = h.sortedNodeHash(buff, hash)
 	}
 	return buff == merkleRoot
 }
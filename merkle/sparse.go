@@ -0,0 +1,245 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+// Sparse merkle trees map 64 bits keys, such as file IDs, to 32 bytes values. The tree has a leaf for every possible
+// key, and leafs of keys that are not set are zero, so the hashes of empty subtrees are known in advance and only the
+// nodes above set keys are stored. The path of a key proves either its value, or that the key is not set.
+// Leafs commit to their key, and leafs and nodes are hashed with the prefixes of tree format v2.
+
+const sparseTreeDepth = 64
+
+type sparseNode struct {
+	height int
+	// path from the root to the node, the key of the leaf shifted right by height
+	prefix uint64
+}
+
+type SparseMerkleTree struct {
+	Hash    cr.HashAlgorithm
+	hashing treeHashing
+	values  map[uint64][32]byte
+	// only nodes that differ from the hash of an empty subtree of their height are stored
+	nodes map[sparseNode][32]byte
+	// hashes of empty subtrees, by height
+	empty [sparseTreeDepth + 1][32]byte
+}
+
+// SparseMerkleProof gives the siblings of the path of a key, from the leaf to the root. Siblings that are
+// empty subtrees are not part of Hashes, the bit of their height is cleared in NonEmpty.
+type SparseMerkleProof struct {
+	Key      uint64
+	Hashes   [][32]byte
+	NonEmpty uint64
+	Hash     cr.HashAlgorithm
+}
+
+func NewSparseMerkleTree(algorithm cr.HashAlgorithm) (*SparseMerkleTree, error) {
+	h, err := newTreeHashing(TreeV2, algorithm, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &SparseMerkleTree{
+		Hash:    algorithm,
+		hashing: h,
+		values:  make(map[uint64][32]byte),
+		nodes:   make(map[sparseNode][32]byte),
+		empty:   h.emptySparseSubtrees(),
+	}, nil
+}
+
+// LoadSparseMerkleTree rebuilds a tree from its keys and values, and verifies it against its root.
+// Nodes are built level by level from the leafs, so that each node is hashed once.
+func LoadSparseMerkleTree(algorithm cr.HashAlgorithm, keys []uint64, values [][32]byte, merkleRoot [32]byte) (*SparseMerkleTree, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("got %v keys and %v values", len(keys), len(values))
+	}
+	tree, err := NewSparseMerkleTree(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		if _, exists := tree.values[key]; exists {
+			return nil, fmt.Errorf("duplicated key %v", key)
+		}
+		tree.values[key] = values[i]
+		tree.nodes[sparseNode{0, key}] = tree.hashing.sparseLeafHash(key, values[i])
+	}
+	tree.buildNodes()
+	if tree.GetMerkleRoot() != merkleRoot {
+		return nil, errors.New("sparse tree does not match its merkle root")
+	}
+	return tree, nil
+}
+
+func (t *SparseMerkleTree) Set(key uint64, value [32]byte) {
+	t.values[key] = value
+	t.nodes[sparseNode{0, key}] = t.hashing.sparseLeafHash(key, value)
+	t.updatePath(key)
+}
+
+func (t *SparseMerkleTree) Delete(key uint64) {
+	if _, exists := t.values[key]; !exists {
+		return
+	}
+	delete(t.values, key)
+	delete(t.nodes, sparseNode{0, key})
+	t.updatePath(key)
+}
+
+func (t *SparseMerkleTree) Get(key uint64) ([32]byte, bool) {
+	value, exists := t.values[key]
+	return value, exists
+}
+
+func (t *SparseMerkleTree) Len() int {
+	return len(t.values)
+}
+
+// Keys returns the keys that are set, in increasing order
+func (t *SparseMerkleTree) Keys() []uint64 {
+	keys := make([]uint64, 0, len(t.values))
+	for key := range t.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (t *SparseMerkleTree) GetMerkleRoot() [32]byte {
+	return t.node(sparseTreeDepth, 0)
+}
+
+// GenerateProof proves the value of a key if it is set, or that it is not set otherwise
+func (t *SparseMerkleTree) GenerateProof(key uint64) *SparseMerkleProof {
+	proof := &SparseMerkleProof{
+		Key:    key,
+		Hashes: [][32]byte{},
+		Hash:   t.Hash,
+	}
+	for height := 0; height < sparseTreeDepth; height++ {
+		sibling := t.node(height, key>>height^1)
+		if sibling != t.empty[height] {
+			proof.Hashes = append(proof.Hashes, sibling)
+			proof.NonEmpty |= 1 << height
+		}
+	}
+	return proof
+}
+
+// VerifyInclusion verifies that the key of the proof is set to value in the tree of root merkleRoot
+func (p SparseMerkleProof) VerifyInclusion(value [32]byte, merkleRoot [32]byte) bool {
+	h, err := newTreeHashing(TreeV2, p.Hash, 0)
+	if err != nil {
+		return false
+	}
+	return p.verifyLeaf(h, h.sparseLeafHash(p.Key, value), merkleRoot)
+}
+
+// VerifyNonInclusion verifies that the key of the proof is not set in the tree of root merkleRoot
+func (p SparseMerkleProof) VerifyNonInclusion(merkleRoot [32]byte) bool {
+	h, err := newTreeHashing(TreeV2, p.Hash, 0)
+	if err != nil {
+		return false
+	}
+	return p.verifyLeaf(h, [32]byte{}, merkleRoot)
+}
+
+func (p SparseMerkleProof) GetProofInHex() []string {
+	var hexProof []string
+	for _, hash := range p.Hashes {
+		hexProof = append(hexProof, hex.EncodeToString(hash[:]))
+	}
+	return hexProof
+}
+
+func (p SparseMerkleProof) verifyLeaf(h treeHashing, leaf [32]byte, merkleRoot [32]byte) bool {
+	empty := h.emptySparseSubtrees()
+	buff := leaf
+	proofPos := 0
+	for height := 0; height < sparseTreeDepth; height++ {
+		sibling := empty[height]
+		if p.NonEmpty>>height&1 == 1 {
+			if proofPos == len(p.Hashes) {
+				return false
+			}
+			sibling = p.Hashes[proofPos]
+			proofPos++
+		}
+		if p.Key>>height&1 == 0 {
+			buff = h.nodeHash(buff, sibling)
+		} else {
+			buff = h.nodeHash(sibling, buff)
+		}
+	}
+	return proofPos == len(p.Hashes) && buff == merkleRoot
+}
+
+func (t *SparseMerkleTree) node(height int, prefix uint64) [32]byte {
+	if hash, exists := t.nodes[sparseNode{height, prefix}]; exists {
+		return hash
+	}
+	return t.empty[height]
+}
+
+// computes the nodes above the leafs, from the leafs to the root
+func (t *SparseMerkleTree) buildNodes() {
+	prefixes := t.Keys()
+	for height := 1; height <= sparseTreeDepth; height++ {
+		// prefixes of the parents of the nodes of the level below, keys are sorted so that duplicates are adjacent
+		parents := prefixes[:0]
+		for _, prefix := range prefixes {
+			if len(parents) == 0 || parents[len(parents)-1] != prefix>>1 {
+				parents = append(parents, prefix>>1)
+			}
+		}
+		hashes := make([][32]byte, len(parents))
+		hashInParallel(0, len(parents), func(i int) {
+			hashes[i] = t.hashing.nodeHash(t.node(height-1, parents[i]<<1), t.node(height-1, parents[i]<<1|1))
+		})
+		for i, prefix := range parents {
+			if hashes[i] != t.empty[height] {
+				t.nodes[sparseNode{height, prefix}] = hashes[i]
+			}
+		}
+		prefixes = parents
+	}
+}
+
+// recomputes the nodes from the leaf of key to the root
+func (t *SparseMerkleTree) updatePath(key uint64) {
+	for height := 1; height <= sparseTreeDepth; height++ {
+		// shifting by the depth of the tree gives the prefix of the root
+		prefix := key >> height
+		parent := t.hashing.nodeHash(t.node(height-1, prefix<<1), t.node(height-1, prefix<<1|1))
+		if parent == t.empty[height] {
+			delete(t.nodes, sparseNode{height, prefix})
+		} else {
+			t.nodes[sparseNode{height, prefix}] = parent
+		}
+	}
+}
+
+func (h treeHashing) sparseLeafHash(key uint64, value [32]byte) [32]byte {
+	var buffer [41]byte
+	buffer[0] = leafPrefix
+	binary.BigEndian.PutUint64(buffer[1:9], key)
+	copy(buffer[9:], value[:])
+	return h.hasher.HashOnce(buffer[:])
+}
+
+func (h treeHashing) emptySparseSubtrees() [sparseTreeDepth + 1][32]byte {
+	var empty [sparseTreeDepth + 1][32]byte
+	for height := 1; height <= sparseTreeDepth; height++ {
+		empty[height] = h.nodeHash(empty[height-1], empty[height-1])
+	}
+	return empty
+}
@@ -0,0 +1,159 @@
+package merkle
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+)
+
+func sparseTestValue(key uint64) [32]byte {
+	return cr.HashOnce([]byte(fmt.Sprintf("TEST%d", key)))
+}
+
+func TestEmptySparseTree(t *testing.T) {
+	tree, err := NewSparseMerkleTree(cr.SHA256)
+	if err != nil {
+		t.Errorf("error when creating tree: %v", err)
+		t.FailNow()
+	}
+	root := tree.GetMerkleRoot()
+	if root != tree.empty[sparseTreeDepth] {
+		t.Errorf("empty tree should have the root of an empty subtree")
+	}
+	proof := tree.GenerateProof(42)
+	if len(proof.Hashes) != 0 || proof.NonEmpty != 0 {
+		t.Errorf("proof in empty tree should only have empty siblings")
+	}
+	if !proof.VerifyNonInclusion(root) {
+		t.Errorf("failed to verify non-inclusion in empty tree")
+	}
+}
+
+func TestSparseTreeProofs(t *testing.T) {
+	for _, algorithm := range []cr.HashAlgorithm{cr.SHA256, cr.Keccak256} {
+		tree, _ := NewSparseMerkleTree(algorithm)
+		keys := []uint64{0, 1, 2, 3, 17, 1 << 40, math.MaxUint64}
+		for _, key := range keys {
+			tree.Set(key, sparseTestValue(key))
+		}
+		root := tree.GetMerkleRoot()
+		for _, key := range keys {
+			proof := tree.GenerateProof(key)
+			if !proof.VerifyInclusion(sparseTestValue(key), root) {
+				t.Errorf("failed to verify inclusion of key %v", key)
+			}
+			if proof.VerifyInclusion(sparseTestValue(key+1), root) {
+				t.Errorf("inclusion of key %v verified with another value", key)
+			}
+			if proof.VerifyNonInclusion(root) {
+				t.Errorf("non-inclusion of set key %v should not verify", key)
+			}
+		}
+		for _, key := range []uint64{4, 16, 18, 1<<40 + 1, math.MaxUint64 - 1} {
+			proof := tree.GenerateProof(key)
+			if !proof.VerifyNonInclusion(root) {
+				t.Errorf("failed to verify non-inclusion of key %v", key)
+			}
+			if proof.VerifyInclusion(sparseTestValue(key), root) {
+				t.Errorf("inclusion of unset key %v should not verify", key)
+			}
+			// proof is bound to its key
+			proof.Key = 17
+			if proof.VerifyNonInclusion(root) {
+				t.Errorf("non-inclusion proof of key %v verified for key 17", key)
+			}
+		}
+	}
+}
+
+func TestSparseTreeDelete(t *testing.T) {
+	tree, _ := NewSparseMerkleTree(cr.SHA256)
+	emptyRoot := tree.GetMerkleRoot()
+	for key := uint64(1); key <= 10; key++ {
+		tree.Set(key, sparseTestValue(key))
+	}
+	before := tree.GetMerkleRoot()
+	tree.Set(11, sparseTestValue(11))
+	tree.Delete(11)
+	if tree.GetMerkleRoot() != before {
+		t.Errorf("deleting a key should restore the previous root")
+	}
+	tree.Delete(5)
+	if _, exists := tree.Get(5); exists || tree.Len() != 9 {
+		t.Errorf("key 5 should be deleted")
+	}
+	root := tree.GetMerkleRoot()
+	if !tree.GenerateProof(5).VerifyNonInclusion(root) {
+		t.Errorf("failed to verify non-inclusion of deleted key")
+	}
+	if tree.GenerateProof(5).VerifyNonInclusion(before) {
+		t.Errorf("deleted key should be included in the previous root")
+	}
+	for key := uint64(1); key <= 10; key++ {
+		tree.Delete(key)
+	}
+	if tree.GetMerkleRoot() != emptyRoot || len(tree.nodes) != 0 {
+		t.Errorf("tree should be empty once all keys are deleted")
+	}
+}
+
+func TestLoadSparseTree(t *testing.T) {
+	tree, _ := NewSparseMerkleTree(cr.BLAKE2b256)
+	for _, key := range []uint64{3, 1, 100, 7} {
+		tree.Set(key, sparseTestValue(key))
+	}
+	keys := tree.Keys()
+	var values [][32]byte
+	for _, key := range keys {
+		value, _ := tree.Get(key)
+		values = append(values, value)
+	}
+	loaded, err := LoadSparseMerkleTree(cr.BLAKE2b256, keys, values, tree.GetMerkleRoot())
+	if err != nil {
+		t.Errorf("error when loading tree: %v", err)
+		t.FailNow()
+	}
+	if loaded.GetMerkleRoot() != tree.GetMerkleRoot() {
+		t.Errorf("loaded tree has a different root")
+	}
+	values[0][0] ^= 1
+	if _, err := LoadSparseMerkleTree(cr.BLAKE2b256, keys, values, tree.GetMerkleRoot()); err == nil {
+		t.Errorf("tampered tree should not load")
+	}
+	if _, err := LoadSparseMerkleTree(cr.BLAKE2b256, []uint64{1, 1}, values[:2], tree.GetMerkleRoot()); err == nil {
+		t.Errorf("tree with duplicated keys should not load")
+	}
+}
+
+func TestLoadSparseTreeMatchesSet(t *testing.T) {
+	keys := []uint64{math.MaxUint64, 0, 42, 1, 2, 3, 1 << 40, 1<<40 + 1}
+	tree, _ := NewSparseMerkleTree(cr.SHA256)
+	var values [][32]byte
+	for _, key := range keys {
+		tree.Set(key, sparseTestValue(key))
+		values = append(values, sparseTestValue(key))
+	}
+	loaded, err := LoadSparseMerkleTree(cr.SHA256, keys, values, tree.GetMerkleRoot())
+	if err != nil {
+		t.Errorf("error when loading tree: %v", err)
+		t.FailNow()
+	}
+	if len(loaded.nodes) != len(tree.nodes) {
+		t.Errorf("loaded tree has %v nodes, expected %v", len(loaded.nodes), len(tree.nodes))
+	}
+	for node, hash := range tree.nodes {
+		if loaded.nodes[node] != hash {
+			t.Errorf("node at height %v with prefix %v differs in loaded tree", node.height, node.prefix)
+		}
+	}
+	// loaded tree keeps growing as a tree built with Set
+	loaded.Set(7, sparseTestValue(7))
+	loaded.Delete(42)
+	tree.Set(7, sparseTestValue(7))
+	tree.Delete(42)
+	if loaded.GetMerkleRoot() != tree.GetMerkleRoot() {
+		t.Errorf("loaded tree has a different root after update")
+	}
+}
@@ -1,32 +1,76 @@
 package merkle
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
-	"sort"
-	"sync/atomic"
+	"math/bits"
+	"slices"
 
 	cr "github.com/oteffahi/merkle-filebank/cryptography"
 )
 
+type TreeMode int32
+
+const (
+	// leafs are sorted by hash and pairs of nodes are hashed in sorted order, as in OpenZeppelin's implementation
+	SortedTree TreeMode = 0
+	// leafs are kept in file order and commit to their sequence number
+	IndexedTree TreeMode = 1
+	// indexed tree stored as a merkle mountain range, where appending files does not rebuild the tree
+	MountainRangeTree TreeMode = 2
+)
+
+// IsIndexed reports whether leafs commit to the sequence number of their file, and are found by file number
+func (mode TreeMode) IsIndexed() bool {
+	return mode == IndexedTree || mode == MountainRangeTree
+}
+
 type MerkleTree struct {
-	Hashes [][32]byte
+	Hashes  [][32]byte
+	Mode    TreeMode
+	Version TreeVersion
+	Hash    cr.HashAlgorithm
+	// size of the chunks of each file, files are not chunked when 0
+	ChunkSize int
 }
 
 func (m *MerkleTree) BuildMerkleTree(files [][]byte) error {
 	if len(files) == 0 {
 		return errors.New("cannot create tree from empty slice")
 	}
-	var leafs [][32]byte
-	for _, file := range files {
-		leafs = append(leafs, cr.HashTwice(file))
+	h, err := m.hashing()
+	if err != nil {
+		return err
 	}
-	tree := merkleTreeFromLeafs(leafs)
+	leafs := make([][32]byte, len(files))
+	hashInParallel(0, len(files), func(i int) {
+		leafs[i] = m.leafFromContentHash(h, i+1, h.contentHash(files[i]))
+	})
+	tree := m.treeFromLeafs(h, leafs)
 	m.Hashes = tree
 	return nil
 }
 
+// ContentHash returns the hash of a file committed by its leaf, the root of its chunk tree when files are chunked
+func (m MerkleTree) ContentHash(file []byte) ([32]byte, error) {
+	h, err := m.hashing()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return h.contentHash(file), nil
+}
+
 func (m MerkleTree) GetMerkleRoot() [32]byte {
+	if m.Mode == MountainRangeTree {
+		// root is not stored, peaks are bagged on demand
+		h, err := m.hashing()
+		nbLeafs := mmrNbLeafs(len(m.Hashes))
+		if err != nil || nbLeafs < 1 {
+			return [32]byte{}
+		}
+		return m.mmrSubtreeHash(h, 0, nbLeafs)
+	}
 	if len(m.Hashes) > 0 {
 		return m.Hashes[0]
 	}
@@ -34,7 +78,23 @@ func (m MerkleTree) GetMerkleRoot() [32]byte {
 }
 
 func (m MerkleTree) GenerateProofForFile(file []byte) (*MerkleProof, error) {
-	leaf := cr.HashTwice(file)
+	contentHash, err := m.ContentHash(file)
+	if err != nil {
+		return nil, err
+	}
+	return m.GenerateProofForContentHash(contentHash)
+}
+
+// for files hashed as they are read, see ContentHasher
+func (m MerkleTree) GenerateProofForContentHash(contentHash [32]byte) (*MerkleProof, error) {
+	if m.Mode != SortedTree {
+		return nil, errors.New("leafs of indexed trees can only be found by file number")
+	}
+	h, err := m.hashing()
+	if err != nil {
+		return nil, err
+	}
+	leaf := h.sortedLeafHash(contentHash)
 	proof, err := m.generateProof(leaf)
 	if err != nil {
 		return nil, err
@@ -42,6 +102,46 @@ func (m MerkleTree) GenerateProofForFile(file []byte) (*MerkleProof, error) {
 	return proof, nil
 }
 
+func (m MerkleTree) GenerateProofForFileNum(fileNum int) (*MerkleProof, error) {
+	if !m.Mode.IsIndexed() {
+		return nil, errors.New("leafs of sorted trees are not bound to a file number")
+	}
+	if len(m.Hashes) == 0 {
+		return nil, errors.New("cannot generate proof from empty tree")
+	}
+	return m.generateIndexedProof(fileNum - 1)
+}
+
+func (m MerkleTree) NbLeafs() int {
+	if len(m.Hashes) == 0 {
+		return 0
+	}
+	if m.Mode == IndexedTree {
+		return indexedTreeNbLeafs(len(m.Hashes))
+	}
+	if m.Mode == MountainRangeTree {
+		return mmrNbLeafs(len(m.Hashes))
+	}
+	return (len(m.Hashes) + 1) / 2
+}
+
+func (m MerkleTree) GetLeafs() ([][32]byte, error) {
+	size := len(m.Hashes)
+	if size == 0 {
+		return nil, errors.New("cannot get leafs of empty tree")
+	}
+	nbLeafs := m.NbLeafs()
+	if nbLeafs == -1 {
+		return nil, errors.New("malformed indexed tree")
+	}
+	if m.Mode == MountainRangeTree {
+		return m.mmrLeafs(nbLeafs), nil
+	}
+	leafs := make([][32]byte, nbLeafs)
+	copy(leafs, m.Hashes[size-nbLeafs:])
+	return leafs, nil
+}
+
 func (m MerkleTree) GetTreeInHex() []string {
 	var hexTree []string
 	for _, hash := range m.Hashes {
@@ -75,13 +175,36 @@ func (m MerkleTree) getNodeIndex(leaf [32]byte) (int, error) {
 	return low, nil
 }
 
-func merkleTreeFromLeafs(leafs [][32]byte) [][32]byte {
+func (m MerkleTree) hashing() (treeHashing, error) {
+	return newTreeHashing(m.Version, m.Hash, m.ChunkSize)
+}
+
+func (m MerkleTree) treeFromLeafs(h treeHashing, leafs [][32]byte) [][32]byte {
+	if m.Mode == IndexedTree {
+		return indexedTreeFromLeafs(h, leafs)
+	}
+	if m.Mode == MountainRangeTree {
+		return mmrFromLeafs(h, leafs)
+	}
+	return merkleTreeFromLeafs(h, leafs)
+}
+
+func (m MerkleTree) leafFromContentHash(h treeHashing, seq int, contentHash [32]byte) [32]byte {
+	if m.Mode.IsIndexed() {
+		return h.indexedLeafHash(seq, contentHash)
+	}
+	return h.sortedLeafHash(contentHash)
+}
+
+// Sorted trees are stored as binary heaps: the children of node i are nodes 2i+1 and 2i+2, and leafs are at the end
+// of the buffer in reverse order. Nodes at depth d are at indexes [2^d-1, 2^(d+1)-1) and only depend on nodes at depth d+1.
+func merkleTreeFromLeafs(h treeHashing, leafs [][32]byte) [][32]byte {
 	if len(leafs) == 1 {
 		return leafs[:1]
 	}
 	// sort leafs
-	sort.Slice(leafs, func(i, j int) bool {
-		return cr.CompareHashes(leafs[i], leafs[j])
+	slices.SortFunc(leafs, func(a, b [32]byte) int {
+		return bytes.Compare(a[:], b[:])
 	})
 	treeLen := len(leafs)*2 - 1
 	tree := make([][32]byte, treeLen)
@@ -89,66 +212,12 @@ func merkleTreeFromLeafs(leafs [][32]byte) [][32]byte {
 	for i, leaf := range leafs {
 		tree[treeLen-1-i] = leaf
 	}
-	// compute nodes
-	atomicBuffers := make([]atomic.Pointer[[32]byte], treeLen-len(leafs))
-	notifyEnd := make(chan struct{})
-	defer close(notifyEnd)
-	for i := treeLen - 1; i > treeLen-len(leafs); i -= 2 {
-		go merkleBuildWorker(tree, atomicBuffers, i, notifyEnd)
+	// compute nodes, from the deepest level to the root
+	nbNodes := len(leafs) - 1
+	for depth := bits.Len(uint(nbNodes)) - 1; depth >= 0; depth-- {
+		hashInParallel(1<<depth-1, min(1<<(depth+1)-1, nbNodes), func(i int) {
+			tree[i] = h.sortedNodeHash(tree[2*i+1], tree[2*i+2])
+		})
 	}
-	<-notifyEnd
 	return tree
 }
-
-func merkleBuildWorker(tree [][32]byte, atomicBuffers []atomic.Pointer[[32]byte], index1 int, ch chan<- struct{}) {
-	var (
-		index2      int
-		resultIndex int
-		elm1        *[32]byte
-		elm2        *[32]byte
-	)
-	// compute other index
-	if index1%2 == 0 {
-		index2 = index1 - 1
-	} else {
-		index2 = index1 + 1
-	}
-	// fetch nodes from leafs/atomicBuffer
-	mapIndexes := func(a, b int, f func(int) *[32]byte) (elm1 *[32]byte, elm2 *[32]byte) {
-		return f(a), f(b)
-	}
-	elm1, elm2 = mapIndexes(index1, index2, func(i int) *[32]byte {
-		if i >= len(atomicBuffers) {
-			return &tree[i]
-		} else {
-			return atomicBuffers[i].Load()
-		}
-	})
-	if elm1 == nil || elm2 == nil {
-		// This goroutine has computed one of the two buffers in the previous call
-		// if the other buffer is empty, the goroutine computing that buffer will continue
-		return
-	}
-	// compute next node
-	resultIndex = min(index1, index2) / 2
-	tree[resultIndex] = concatAndHash(*elm1, *elm2)
-	atomicBuffers[resultIndex].Store(&tree[resultIndex])
-	// start computation of next level, or terminate if root reached
-	if resultIndex == 0 {
-		ch <- struct{}{}
-	} else {
-		merkleBuildWorker(tree, atomicBuffers, resultIndex, ch)
-	}
-}
-
-func concatAndHash(hash1 [32]byte, hash2 [32]byte) [32]byte {
-	var buffer []byte
-	if cr.CompareHashes(hash1, hash2) {
-		buffer = append(buffer, hash1[:]...)
-		buffer = append(buffer, hash2[:]...)
-	} else {
-		buffer = append(buffer, hash2[:]...)
-		buffer = append(buffer, hash1[:]...)
-	}
-	return cr.HashOnce(buffer)
-}
@@ -311,10 +311,14 @@ type ChallengeResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Nonce     []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Pubkey    []byte `protobuf:"bytes,2,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
-	Nbfiles   int32  `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
-	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Nonce         []byte        `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Pubkey        []byte        `protobuf:"bytes,2,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Nbfiles       int32         `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	Signature     []byte        `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	TreeMode      TreeMode      `protobuf:"varint,5,opt,name=tree_mode,json=treeMode,proto3,enum=filebank.TreeMode" json:"tree_mode,omitempty"`
+	TreeVersion   TreeVersion   `protobuf:"varint,6,opt,name=tree_version,json=treeVersion,proto3,enum=filebank.TreeVersion" json:"tree_version,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,7,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=filebank.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	ChunkSize     int32         `protobuf:"varint,8,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
 }
 
 func (x *ChallengeResponse) Reset() {
@@ -377,6 +381,35 @@ func (x *ChallengeResponse) GetSignature() []byte {
 	return nil
 }
 
+func (x *ChallengeResponse) GetTreeMode() TreeMode {
+	if x != nil {
+		return x.TreeMode
+	}
+	return TreeMode_SORTED_TREE
+}
+
+func (x *ChallengeResponse) GetTreeVersion() TreeVersion {
+	if x != nil {
+		return x.TreeVersion
+	}
+	return TreeVersion_TREE_V1
+}
+
+func (x *ChallengeResponse) GetHashAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.HashAlgorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+func (x *ChallengeResponse) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+// part of a file. A file is sent as consecutive messages of the same seq, so that it is never held in memory
 type FileMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -500,10 +533,14 @@ type DownloadFilesRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	PubKeyAddr string `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
-	FileNum    int32  `protobuf:"varint,3,opt,name=file_num,json=fileNum,proto3" json:"file_num,omitempty"`
-	Signature  []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Nonce      []byte     `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr string     `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	FileNum    int32      `protobuf:"varint,3,opt,name=file_num,json=fileNum,proto3" json:"file_num,omitempty"`
+	Signature  []byte     `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	FileNums   []int32    `protobuf:"varint,5,rep,packed,name=file_nums,json=fileNums,proto3" json:"file_nums,omitempty"`
+	Range      *ByteRange `protobuf:"bytes,6,opt,name=range,proto3" json:"range,omitempty"`
+	// identity key of a recipient of the bank that signed the request, the bank key when empty
+	Signer []byte `protobuf:"bytes,7,opt,name=signer,proto3" json:"signer,omitempty"`
 }
 
 func (x *DownloadFilesRequest) Reset() {
@@ -566,6 +603,27 @@ func (x *DownloadFilesRequest) GetSignature() []byte {
 	return nil
 }
 
+func (x *DownloadFilesRequest) GetFileNums() []int32 {
+	if x != nil {
+		return x.FileNums
+	}
+	return nil
+}
+
+func (x *DownloadFilesRequest) GetRange() *ByteRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *DownloadFilesRequest) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
 type DownloadFilesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -575,6 +633,9 @@ type DownloadFilesResponse struct {
 	//
 	//	*DownloadFilesResponse_Nonce
 	//	*DownloadFilesResponse_Fp
+	//	*DownloadFilesResponse_Fmp
+	//	*DownloadFilesResponse_Rp
+	//	*DownloadFilesResponse_File
 	Phase isDownloadFilesResponse_Phase `protobuf_oneof:"phase"`
 }
 
@@ -631,6 +692,27 @@ func (x *DownloadFilesResponse) GetFp() *FileAndProof {
 	return nil
 }
 
+func (x *DownloadFilesResponse) GetFmp() *FilesAndMultiProof {
+	if x, ok := x.GetPhase().(*DownloadFilesResponse_Fmp); ok {
+		return x.Fmp
+	}
+	return nil
+}
+
+func (x *DownloadFilesResponse) GetRp() *RangeAndProof {
+	if x, ok := x.GetPhase().(*DownloadFilesResponse_Rp); ok {
+		return x.Rp
+	}
+	return nil
+}
+
+func (x *DownloadFilesResponse) GetFile() *FileMessage {
+	if x, ok := x.GetPhase().(*DownloadFilesResponse_File); ok {
+		return x.File
+	}
+	return nil
+}
+
 type isDownloadFilesResponse_Phase interface {
 	isDownloadFilesResponse_Phase()
 }
@@ -643,23 +725,99 @@ type DownloadFilesResponse_Fp struct {
 	Fp *FileAndProof `protobuf:"bytes,4,opt,name=fp,proto3,oneof"`
 }
 
+type DownloadFilesResponse_Fmp struct {
+	Fmp *FilesAndMultiProof `protobuf:"bytes,5,opt,name=fmp,proto3,oneof"`
+}
+
+type DownloadFilesResponse_Rp struct {
+	Rp *RangeAndProof `protobuf:"bytes,6,opt,name=rp,proto3,oneof"`
+}
+
+type DownloadFilesResponse_File struct {
+	// files and ranges follow their proof as parts, fp, fmp and rp no longer carry them
+	File *FileMessage `protobuf:"bytes,7,opt,name=file,proto3,oneof"`
+}
+
 func (*DownloadFilesResponse_Nonce) isDownloadFilesResponse_Phase() {}
 
 func (*DownloadFilesResponse_Fp) isDownloadFilesResponse_Phase() {}
 
+func (*DownloadFilesResponse_Fmp) isDownloadFilesResponse_Phase() {}
+
+func (*DownloadFilesResponse_Rp) isDownloadFilesResponse_Phase() {}
+
+func (*DownloadFilesResponse_File) isDownloadFilesResponse_Phase() {}
+
+type ByteRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+}
+
+func (x *ByteRange) Reset() {
+	*x = ByteRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ByteRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ByteRange) ProtoMessage() {}
+
+func (x *ByteRange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ByteRange.ProtoReflect.Descriptor instead.
+func (*ByteRange) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ByteRange) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ByteRange) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
 type FileAndProof struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Proof []byte `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
-	File  []byte `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Proof     []byte `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	File      []byte `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	LeafIndex int32  `protobuf:"varint,3,opt,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	TreeSize  int32  `protobuf:"varint,4,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
 }
 
 func (x *FileAndProof) Reset() {
 	*x = FileAndProof{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_filebank_proto_msgTypes[9]
+		mi := &file_proto_filebank_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -672,7 +830,7 @@ func (x *FileAndProof) String() string {
 func (*FileAndProof) ProtoMessage() {}
 
 func (x *FileAndProof) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_filebank_proto_msgTypes[9]
+	mi := &file_proto_filebank_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,7 +843,7 @@ func (x *FileAndProof) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileAndProof.ProtoReflect.Descriptor instead.
 func (*FileAndProof) Descriptor() ([]byte, []int) {
-	return file_proto_filebank_proto_rawDescGZIP(), []int{9}
+	return file_proto_filebank_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *FileAndProof) GetProof() []byte {
@@ -702,172 +860,1464 @@ func (x *FileAndProof) GetFile() []byte {
 	return nil
 }
 
-var File_proto_filebank_proto protoreflect.FileDescriptor
+func (x *FileAndProof) GetLeafIndex() int32 {
+	if x != nil {
+		return x.LeafIndex
+	}
+	return 0
+}
 
-var file_proto_filebank_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
-	0x22, 0x26, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x5d, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x4e,
-	0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e,
-	0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67,
-	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xa2, 0x01, 0x0a, 0x12, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3e,
-	0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x43,
-	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x48, 0x00, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2b,
-	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x66,
-	0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e,
-	0x6f, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f,
-	0x6e, 0x63, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x77, 0x0a, 0x13,
-	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x0f, 0x6d,
-	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
-	0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x6d, 0x65,
-	0x72, 0x6b, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x07, 0x0a, 0x05,
-	0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x79, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e,
-	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f,
-	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69,
-	0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c,
-	0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x22, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x73, 0x65,
-	0x71, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x61, 0x0a, 0x0a, 0x4d,
-	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e,
-	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12,
-	0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74,
-	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x87,
-	0x01, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a,
-	0x0c, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12,
-	0x19, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x75, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x62, 0x0a, 0x15, 0x44, 0x6f, 0x77, 0x6e,
-	0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x02, 0x66, 0x70, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
-	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48, 0x00, 0x52,
-	0x02, 0x66, 0x70, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x38, 0x0a, 0x0c,
-	0x46, 0x69, 0x6c, 0x65, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05,
-	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f,
-	0x6f, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x32, 0xf7, 0x01, 0x0a, 0x0f, 0x46, 0x69, 0x6c, 0x65, 0x42,
-	0x61, 0x6e, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x07, 0x41, 0x64,
-	0x64, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
-	0x2e, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x19, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x64, 0x64, 0x4e, 0x6f,
-	0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x55, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65,
-	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
-	0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x54, 0x0a, 0x0d, 0x44, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x66, 0x69,
-	0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46,
-	0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x66, 0x69,
-	0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46,
-	0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01,
-	0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+func (x *FileAndProof) GetTreeSize() int32 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
 }
 
-var (
-	file_proto_filebank_proto_rawDescOnce sync.Once
-	file_proto_filebank_proto_rawDescData = file_proto_filebank_proto_rawDesc
-)
+type FilesAndMultiProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_proto_filebank_proto_rawDescGZIP() []byte {
-	file_proto_filebank_proto_rawDescOnce.Do(func() {
-		file_proto_filebank_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_filebank_proto_rawDescData)
-	})
-	return file_proto_filebank_proto_rawDescData
+	Proof       []byte   `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	ProofFlags  []bool   `protobuf:"varint,2,rep,packed,name=proof_flags,json=proofFlags,proto3" json:"proof_flags,omitempty"`
+	Files       [][]byte `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	LeafIndexes []int32  `protobuf:"varint,4,rep,packed,name=leaf_indexes,json=leafIndexes,proto3" json:"leaf_indexes,omitempty"`
+	TreeSize    int32    `protobuf:"varint,5,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
 }
 
-var file_proto_filebank_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
-var file_proto_filebank_proto_goTypes = []interface{}{
-	(*AddNodeRequest)(nil),        // 0: filebank.AddNodeRequest
-	(*AddNodeResponse)(nil),       // 1: filebank.AddNodeResponse
-	(*UploadFilesRequest)(nil),    // 2: filebank.UploadFilesRequest
-	(*UploadFilesResponse)(nil),   // 3: filebank.UploadFilesResponse
-	(*ChallengeResponse)(nil),     // 4: filebank.ChallengeResponse
-	(*FileMessage)(nil),           // 5: filebank.FileMessage
-	(*MerkleRoot)(nil),            // 6: filebank.MerkleRoot
-	(*DownloadFilesRequest)(nil),  // 7: filebank.DownloadFilesRequest
-	(*DownloadFilesResponse)(nil), // 8: filebank.DownloadFilesResponse
-	(*FileAndProof)(nil),          // 9: filebank.FileAndProof
+func (x *FilesAndMultiProof) Reset() {
+	*x = FilesAndMultiProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_proto_filebank_proto_depIdxs = []int32{
-	4, // 0: filebank.UploadFilesRequest.signed_resp:type_name -> filebank.ChallengeResponse
-	5, // 1: filebank.UploadFilesRequest.file:type_name -> filebank.FileMessage
-	6, // 2: filebank.UploadFilesResponse.merkle_response:type_name -> filebank.MerkleRoot
-	9, // 3: filebank.DownloadFilesResponse.fp:type_name -> filebank.FileAndProof
-	0, // 4: filebank.FileBankService.AddNode:input_type -> filebank.AddNodeRequest
-	2, // 5: filebank.FileBankService.UploadFiles:input_type -> filebank.UploadFilesRequest
-	7, // 6: filebank.FileBankService.DownloadFiles:input_type -> filebank.DownloadFilesRequest
-	1, // 7: filebank.FileBankService.AddNode:output_type -> filebank.AddNodeResponse
-	3, // 8: filebank.FileBankService.UploadFiles:output_type -> filebank.UploadFilesResponse
-	8, // 9: filebank.FileBankService.DownloadFiles:output_type -> filebank.DownloadFilesResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+func (x *FilesAndMultiProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_proto_filebank_proto_init() }
-func file_proto_filebank_proto_init() {
-	if File_proto_filebank_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_filebank_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddNodeRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*FilesAndMultiProof) ProtoMessage() {}
+
+func (x *FilesAndMultiProof) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_filebank_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddNodeResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesAndMultiProof.ProtoReflect.Descriptor instead.
+func (*FilesAndMultiProof) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FilesAndMultiProof) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *FilesAndMultiProof) GetProofFlags() []bool {
+	if x != nil {
+		return x.ProofFlags
+	}
+	return nil
+}
+
+func (x *FilesAndMultiProof) GetFiles() [][]byte {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *FilesAndMultiProof) GetLeafIndexes() []int32 {
+	if x != nil {
+		return x.LeafIndexes
+	}
+	return nil
+}
+
+func (x *FilesAndMultiProof) GetTreeSize() int32 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+type RangeAndProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chunks      []byte `protobuf:"bytes,1,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	FirstChunk  int32  `protobuf:"varint,2,opt,name=first_chunk,json=firstChunk,proto3" json:"first_chunk,omitempty"`
+	ChunksProof []byte `protobuf:"bytes,3,opt,name=chunks_proof,json=chunksProof,proto3" json:"chunks_proof,omitempty"`
+	ChunkRoot   []byte `protobuf:"bytes,4,opt,name=chunk_root,json=chunkRoot,proto3" json:"chunk_root,omitempty"`
+	Proof       []byte `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+	LeafIndex   int32  `protobuf:"varint,6,opt,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	TreeSize    int32  `protobuf:"varint,7,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+}
+
+func (x *RangeAndProof) Reset() {
+	*x = RangeAndProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RangeAndProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeAndProof) ProtoMessage() {}
+
+func (x *RangeAndProof) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_filebank_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadFilesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeAndProof.ProtoReflect.Descriptor instead.
+func (*RangeAndProof) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RangeAndProof) GetChunks() []byte {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *RangeAndProof) GetFirstChunk() int32 {
+	if x != nil {
+		return x.FirstChunk
+	}
+	return 0
+}
+
+func (x *RangeAndProof) GetChunksProof() []byte {
+	if x != nil {
+		return x.ChunksProof
+	}
+	return nil
+}
+
+func (x *RangeAndProof) GetChunkRoot() []byte {
+	if x != nil {
+		return x.ChunkRoot
+	}
+	return nil
+}
+
+func (x *RangeAndProof) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *RangeAndProof) GetLeafIndex() int32 {
+	if x != nil {
+		return x.LeafIndex
+	}
+	return 0
+}
+
+func (x *RangeAndProof) GetTreeSize() int32 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+type AppendFilesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Phase:
+	//
+	//	*AppendFilesRequest_AppendReq
+	//	*AppendFilesRequest_File
+	//	*AppendFilesRequest_Nonce
+	Phase isAppendFilesRequest_Phase `protobuf_oneof:"phase"`
+}
+
+func (x *AppendFilesRequest) Reset() {
+	*x = AppendFilesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppendFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendFilesRequest) ProtoMessage() {}
+
+func (x *AppendFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendFilesRequest.ProtoReflect.Descriptor instead.
+func (*AppendFilesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{13}
+}
+
+func (m *AppendFilesRequest) GetPhase() isAppendFilesRequest_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return nil
+}
+
+func (x *AppendFilesRequest) GetAppendReq() *AppendRequest {
+	if x, ok := x.GetPhase().(*AppendFilesRequest_AppendReq); ok {
+		return x.AppendReq
+	}
+	return nil
+}
+
+func (x *AppendFilesRequest) GetFile() *FileMessage {
+	if x, ok := x.GetPhase().(*AppendFilesRequest_File); ok {
+		return x.File
+	}
+	return nil
+}
+
+func (x *AppendFilesRequest) GetNonce() []byte {
+	if x, ok := x.GetPhase().(*AppendFilesRequest_Nonce); ok {
+		return x.Nonce
+	}
+	return nil
+}
+
+type isAppendFilesRequest_Phase interface {
+	isAppendFilesRequest_Phase()
+}
+
+type AppendFilesRequest_AppendReq struct {
+	AppendReq *AppendRequest `protobuf:"bytes,1,opt,name=append_req,json=appendReq,proto3,oneof"`
+}
+
+type AppendFilesRequest_File struct {
+	File *FileMessage `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+type AppendFilesRequest_Nonce struct {
+	Nonce []byte `protobuf:"bytes,3,opt,name=nonce,proto3,oneof"`
+}
+
+func (*AppendFilesRequest_AppendReq) isAppendFilesRequest_Phase() {}
+
+func (*AppendFilesRequest_File) isAppendFilesRequest_Phase() {}
+
+func (*AppendFilesRequest_Nonce) isAppendFilesRequest_Phase() {}
+
+type AppendFilesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Phase:
+	//
+	//	*AppendFilesResponse_Nonce
+	//	*AppendFilesResponse_AppendedRoot
+	Phase isAppendFilesResponse_Phase `protobuf_oneof:"phase"`
+}
+
+func (x *AppendFilesResponse) Reset() {
+	*x = AppendFilesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppendFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendFilesResponse) ProtoMessage() {}
+
+func (x *AppendFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendFilesResponse.ProtoReflect.Descriptor instead.
+func (*AppendFilesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{14}
+}
+
+func (m *AppendFilesResponse) GetPhase() isAppendFilesResponse_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return nil
+}
+
+func (x *AppendFilesResponse) GetNonce() []byte {
+	if x, ok := x.GetPhase().(*AppendFilesResponse_Nonce); ok {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *AppendFilesResponse) GetAppendedRoot() *AppendedRoot {
+	if x, ok := x.GetPhase().(*AppendFilesResponse_AppendedRoot); ok {
+		return x.AppendedRoot
+	}
+	return nil
+}
+
+type isAppendFilesResponse_Phase interface {
+	isAppendFilesResponse_Phase()
+}
+
+type AppendFilesResponse_Nonce struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3,oneof"`
+}
+
+type AppendFilesResponse_AppendedRoot struct {
+	AppendedRoot *AppendedRoot `protobuf:"bytes,2,opt,name=appended_root,json=appendedRoot,proto3,oneof"`
+}
+
+func (*AppendFilesResponse_Nonce) isAppendFilesResponse_Phase() {}
+
+func (*AppendFilesResponse_AppendedRoot) isAppendFilesResponse_Phase() {}
+
+type AppendRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr string `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	OldNbfiles int32  `protobuf:"varint,3,opt,name=old_nbfiles,json=oldNbfiles,proto3" json:"old_nbfiles,omitempty"`
+	Nbfiles    int32  `protobuf:"varint,4,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	Signature  []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *AppendRequest) Reset() {
+	*x = AppendRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendRequest) ProtoMessage() {}
+
+func (x *AppendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendRequest.ProtoReflect.Descriptor instead.
+func (*AppendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AppendRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *AppendRequest) GetPubKeyAddr() string {
+	if x != nil {
+		return x.PubKeyAddr
+	}
+	return ""
+}
+
+func (x *AppendRequest) GetOldNbfiles() int32 {
+	if x != nil {
+		return x.OldNbfiles
+	}
+	return 0
+}
+
+func (x *AppendRequest) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *AppendRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type AppendedRoot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce            []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	MerkleRoot       []byte `protobuf:"bytes,2,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Nbfiles          int32  `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	Signature        []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	ConsistencyProof []byte `protobuf:"bytes,5,opt,name=consistency_proof,json=consistencyProof,proto3" json:"consistency_proof,omitempty"`
+	FilesProof       []byte `protobuf:"bytes,6,opt,name=files_proof,json=filesProof,proto3" json:"files_proof,omitempty"`
+}
+
+func (x *AppendedRoot) Reset() {
+	*x = AppendedRoot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppendedRoot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendedRoot) ProtoMessage() {}
+
+func (x *AppendedRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendedRoot.ProtoReflect.Descriptor instead.
+func (*AppendedRoot) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AppendedRoot) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *AppendedRoot) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *AppendedRoot) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *AppendedRoot) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *AppendedRoot) GetConsistencyProof() []byte {
+	if x != nil {
+		return x.ConsistencyProof
+	}
+	return nil
+}
+
+func (x *AppendedRoot) GetFilesProof() []byte {
+	if x != nil {
+		return x.FilesProof
+	}
+	return nil
+}
+
+type RotateBankRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Phase:
+	//
+	//	*RotateBankRequest_RotateReq
+	//	*RotateBankRequest_File
+	Phase isRotateBankRequest_Phase `protobuf_oneof:"phase"`
+}
+
+func (x *RotateBankRequest) Reset() {
+	*x = RotateBankRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateBankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateBankRequest) ProtoMessage() {}
+
+func (x *RotateBankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateBankRequest.ProtoReflect.Descriptor instead.
+func (*RotateBankRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{17}
+}
+
+func (m *RotateBankRequest) GetPhase() isRotateBankRequest_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return nil
+}
+
+func (x *RotateBankRequest) GetRotateReq() *RotateRequest {
+	if x, ok := x.GetPhase().(*RotateBankRequest_RotateReq); ok {
+		return x.RotateReq
+	}
+	return nil
+}
+
+func (x *RotateBankRequest) GetFile() *FileMessage {
+	if x, ok := x.GetPhase().(*RotateBankRequest_File); ok {
+		return x.File
+	}
+	return nil
+}
+
+type isRotateBankRequest_Phase interface {
+	isRotateBankRequest_Phase()
+}
+
+type RotateBankRequest_RotateReq struct {
+	RotateReq *RotateRequest `protobuf:"bytes,1,opt,name=rotate_req,json=rotateReq,proto3,oneof"`
+}
+
+type RotateBankRequest_File struct {
+	File *FileMessage `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+func (*RotateBankRequest_RotateReq) isRotateBankRequest_Phase() {}
+
+func (*RotateBankRequest_File) isRotateBankRequest_Phase() {}
+
+type RotateBankResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Phase:
+	//
+	//	*RotateBankResponse_Nonce
+	//	*RotateBankResponse_RotatedRoot
+	Phase isRotateBankResponse_Phase `protobuf_oneof:"phase"`
+}
+
+func (x *RotateBankResponse) Reset() {
+	*x = RotateBankResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateBankResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateBankResponse) ProtoMessage() {}
+
+func (x *RotateBankResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateBankResponse.ProtoReflect.Descriptor instead.
+func (*RotateBankResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{18}
+}
+
+func (m *RotateBankResponse) GetPhase() isRotateBankResponse_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return nil
+}
+
+func (x *RotateBankResponse) GetNonce() []byte {
+	if x, ok := x.GetPhase().(*RotateBankResponse_Nonce); ok {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *RotateBankResponse) GetRotatedRoot() *RotatedRoot {
+	if x, ok := x.GetPhase().(*RotateBankResponse_RotatedRoot); ok {
+		return x.RotatedRoot
+	}
+	return nil
+}
+
+type isRotateBankResponse_Phase interface {
+	isRotateBankResponse_Phase()
+}
+
+type RotateBankResponse_Nonce struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3,oneof"`
+}
+
+type RotateBankResponse_RotatedRoot struct {
+	RotatedRoot *RotatedRoot `protobuf:"bytes,2,opt,name=rotated_root,json=rotatedRoot,proto3,oneof"`
+}
+
+func (*RotateBankResponse_Nonce) isRotateBankResponse_Phase() {}
+
+func (*RotateBankResponse_RotatedRoot) isRotateBankResponse_Phase() {}
+
+type RotateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Handover    *BankHandover `protobuf:"bytes,1,opt,name=handover,proto3" json:"handover,omitempty"`
+	ClientNonce []byte        `protobuf:"bytes,2,opt,name=client_nonce,json=clientNonce,proto3" json:"client_nonce,omitempty"`
+}
+
+func (x *RotateRequest) Reset() {
+	*x = RotateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateRequest) ProtoMessage() {}
+
+func (x *RotateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateRequest.ProtoReflect.Descriptor instead.
+func (*RotateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RotateRequest) GetHandover() *BankHandover {
+	if x != nil {
+		return x.Handover
+	}
+	return nil
+}
+
+func (x *RotateRequest) GetClientNonce() []byte {
+	if x != nil {
+		return x.ClientNonce
+	}
+	return nil
+}
+
+type RotatedRoot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	MerkleRoot []byte `protobuf:"bytes,2,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Signature  []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *RotatedRoot) Reset() {
+	*x = RotatedRoot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotatedRoot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatedRoot) ProtoMessage() {}
+
+func (x *RotatedRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatedRoot.ProtoReflect.Descriptor instead.
+func (*RotatedRoot) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RotatedRoot) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *RotatedRoot) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *RotatedRoot) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// stores the encrypted manifest of a bank, or reads it when manifest is empty
+type BankManifestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr string `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	Manifest   []byte `protobuf:"bytes,3,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	Signature  []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// recipients the bank is shared with, replaced along with the manifest
+	Recipients []*BankRecipient `protobuf:"bytes,5,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	// identity key of a recipient of the bank that signed the request, the bank key when empty. Recipients can only
+	// read the manifest
+	Signer []byte `protobuf:"bytes,6,opt,name=signer,proto3" json:"signer,omitempty"`
+}
+
+func (x *BankManifestRequest) Reset() {
+	*x = BankManifestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankManifestRequest) ProtoMessage() {}
+
+func (x *BankManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankManifestRequest.ProtoReflect.Descriptor instead.
+func (*BankManifestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BankManifestRequest) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *BankManifestRequest) GetPubKeyAddr() string {
+	if x != nil {
+		return x.PubKeyAddr
+	}
+	return ""
+}
+
+func (x *BankManifestRequest) GetManifest() []byte {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *BankManifestRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *BankManifestRequest) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *BankManifestRequest) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type BankManifestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Phase:
+	//
+	//	*BankManifestResponse_Nonce
+	//	*BankManifestResponse_Result
+	Phase isBankManifestResponse_Phase `protobuf_oneof:"phase"`
+}
+
+func (x *BankManifestResponse) Reset() {
+	*x = BankManifestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankManifestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankManifestResponse) ProtoMessage() {}
+
+func (x *BankManifestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankManifestResponse.ProtoReflect.Descriptor instead.
+func (*BankManifestResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{22}
+}
+
+func (m *BankManifestResponse) GetPhase() isBankManifestResponse_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return nil
+}
+
+func (x *BankManifestResponse) GetNonce() []byte {
+	if x, ok := x.GetPhase().(*BankManifestResponse_Nonce); ok {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *BankManifestResponse) GetResult() *ManifestResult {
+	if x, ok := x.GetPhase().(*BankManifestResponse_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isBankManifestResponse_Phase interface {
+	isBankManifestResponse_Phase()
+}
+
+type BankManifestResponse_Nonce struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3,oneof"`
+}
+
+type BankManifestResponse_Result struct {
+	Result *ManifestResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*BankManifestResponse_Nonce) isBankManifestResponse_Phase() {}
+
+func (*BankManifestResponse_Result) isBankManifestResponse_Phase() {}
+
+// manifest of the bank, empty after it was stored, and the number of files of the bank on the server
+type ManifestResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Manifest   []byte           `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	Nbfiles    int32            `protobuf:"varint,2,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	Recipients []*BankRecipient `protobuf:"bytes,3,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	// exported public key of the bank
+	PubKey []byte `protobuf:"bytes,4,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// nonce and bank key signature of the request that stored the manifest and the recipients
+	Nonce     []byte `protobuf:"bytes,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *ManifestResult) Reset() {
+	*x = ManifestResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_filebank_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ManifestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestResult) ProtoMessage() {}
+
+func (x *ManifestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filebank_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestResult.ProtoReflect.Descriptor instead.
+func (*ManifestResult) Descriptor() ([]byte, []int) {
+	return file_proto_filebank_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ManifestResult) GetManifest() []byte {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *ManifestResult) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *ManifestResult) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *ManifestResult) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *ManifestResult) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *ManifestResult) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+var File_proto_filebank_proto protoreflect.FileDescriptor
+
+var file_proto_filebank_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x1a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x26, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x5d, 0x0a,
+	0x0f, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xa2, 0x01, 0x0a,
+	0x12, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x72, 0x65,
+	0x73, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x12, 0x2b, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65,
+	0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48,
+	0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73,
+	0x65, 0x22, 0x77, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x12, 0x3f, 0x0a, 0x0f, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x48,
+	0x00, 0x52, 0x0e, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0xc3, 0x02, 0x0a, 0x11, 0x43,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x74,
+	0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x74, 0x72, 0x65, 0x65, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x3e, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69,
+	0x74, 0x68, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74,
+	0x68, 0x6d, 0x52, 0x0d, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65,
+	0x22, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x73, 0x65,
+	0x71, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x61, 0x0a, 0x0a, 0x4d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xe7,
+	0x01, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a,
+	0x0c, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12,
+	0x19, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x75, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x6e, 0x75, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x66, 0x69, 0x6c,
+	0x65, 0x4e, 0x75, 0x6d, 0x73, 0x12, 0x29, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x42, 0x79, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0xec, 0x01, 0x0a, 0x15, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x02, 0x66, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48, 0x00,
+	0x52, 0x02, 0x66, 0x70, 0x12, 0x30, 0x0a, 0x03, 0x66, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48,
+	0x00, 0x52, 0x03, 0x66, 0x6d, 0x70, 0x12, 0x29, 0x0a, 0x02, 0x72, 0x70, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48, 0x00, 0x52, 0x02, 0x72,
+	0x70, 0x12, 0x2b, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x3b, 0x0a, 0x09, 0x42, 0x79, 0x74, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x22, 0x74, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x6e, 0x64, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xa1, 0x01, 0x0a, 0x12, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xdc,
+	0x01, 0x0a, 0x0d, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0b, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x9c, 0x01,
+	0x0a, 0x12, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x0a, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x5f, 0x72,
+	0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x48, 0x00, 0x52, 0x09, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x12, 0x2b,
+	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x66,
+	0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x75, 0x0a, 0x13,
+	0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x0d, 0x61,
+	0x70, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x70,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x61, 0x70,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68,
+	0x61, 0x73, 0x65, 0x22, 0xa0, 0x01, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70,
+	0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x6f, 0x6c, 0x64, 0x4e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xcb, 0x01, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x65, 0x6e,
+	0x64, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x22, 0x83, 0x01, 0x0a, 0x11, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x42,
+	0x61, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x0a, 0x72, 0x6f,
+	0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x09, 0x72, 0x6f, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x12, 0x2b, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c,
+	0x65, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x71, 0x0a, 0x12, 0x52, 0x6f,
+	0x74, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
+	0x00, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x3a, 0x0a, 0x0c, 0x72, 0x6f, 0x74, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65,
+	0x64, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x64,
+	0x52, 0x6f, 0x6f, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x66, 0x0a,
+	0x0d, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32,
+	0x0a, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b,
+	0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76,
+	0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x62, 0x0a, 0x0b, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x64,
+	0x52, 0x6f, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65,
+	0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xd8, 0x01, 0x0a, 0x13, 0x42, 0x61,
+	0x6e, 0x6b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x22, 0x6b, 0x0a, 0x14, 0x42, 0x61, 0x6e, 0x6b, 0x4d, 0x61, 0x6e, 0x69,
+	0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x05,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00,
+	0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73,
+	0x65, 0x22, 0xcc, 0x01, 0x0a, 0x0e, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x32, 0xe7, 0x03, 0x0a, 0x0f, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x12,
+	0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x64, 0x64, 0x4e, 0x6f,
+	0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
+	0x6c, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x54, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0b, 0x41, 0x70,
+	0x70, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0a, 0x52, 0x6f,
+	0x74, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x51, 0x0a, 0x0c, 0x42, 0x61, 0x6e, 0x6b, 0x4d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_filebank_proto_rawDescOnce sync.Once
+	file_proto_filebank_proto_rawDescData = file_proto_filebank_proto_rawDesc
+)
+
+func file_proto_filebank_proto_rawDescGZIP() []byte {
+	file_proto_filebank_proto_rawDescOnce.Do(func() {
+		file_proto_filebank_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_filebank_proto_rawDescData)
+	})
+	return file_proto_filebank_proto_rawDescData
+}
+
+var file_proto_filebank_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_proto_filebank_proto_goTypes = []interface{}{
+	(*AddNodeRequest)(nil),        // 0: filebank.AddNodeRequest
+	(*AddNodeResponse)(nil),       // 1: filebank.AddNodeResponse
+	(*UploadFilesRequest)(nil),    // 2: filebank.UploadFilesRequest
+	(*UploadFilesResponse)(nil),   // 3: filebank.UploadFilesResponse
+	(*ChallengeResponse)(nil),     // 4: filebank.ChallengeResponse
+	(*FileMessage)(nil),           // 5: filebank.FileMessage
+	(*MerkleRoot)(nil),            // 6: filebank.MerkleRoot
+	(*DownloadFilesRequest)(nil),  // 7: filebank.DownloadFilesRequest
+	(*DownloadFilesResponse)(nil), // 8: filebank.DownloadFilesResponse
+	(*ByteRange)(nil),             // 9: filebank.ByteRange
+	(*FileAndProof)(nil),          // 10: filebank.FileAndProof
+	(*FilesAndMultiProof)(nil),    // 11: filebank.FilesAndMultiProof
+	(*RangeAndProof)(nil),         // 12: filebank.RangeAndProof
+	(*AppendFilesRequest)(nil),    // 13: filebank.AppendFilesRequest
+	(*AppendFilesResponse)(nil),   // 14: filebank.AppendFilesResponse
+	(*AppendRequest)(nil),         // 15: filebank.AppendRequest
+	(*AppendedRoot)(nil),          // 16: filebank.AppendedRoot
+	(*RotateBankRequest)(nil),     // 17: filebank.RotateBankRequest
+	(*RotateBankResponse)(nil),    // 18: filebank.RotateBankResponse
+	(*RotateRequest)(nil),         // 19: filebank.RotateRequest
+	(*RotatedRoot)(nil),           // 20: filebank.RotatedRoot
+	(*BankManifestRequest)(nil),   // 21: filebank.BankManifestRequest
+	(*BankManifestResponse)(nil),  // 22: filebank.BankManifestResponse
+	(*ManifestResult)(nil),        // 23: filebank.ManifestResult
+	(TreeMode)(0),                 // 24: filebank.TreeMode
+	(TreeVersion)(0),              // 25: filebank.TreeVersion
+	(HashAlgorithm)(0),            // 26: filebank.HashAlgorithm
+	(*BankHandover)(nil),          // 27: filebank.BankHandover
+	(*BankRecipient)(nil),         // 28: filebank.BankRecipient
+}
+var file_proto_filebank_proto_depIdxs = []int32{
+	4,  // 0: filebank.UploadFilesRequest.signed_resp:type_name -> filebank.ChallengeResponse
+	5,  // 1: filebank.UploadFilesRequest.file:type_name -> filebank.FileMessage
+	6,  // 2: filebank.UploadFilesResponse.merkle_response:type_name -> filebank.MerkleRoot
+	24, // 3: filebank.ChallengeResponse.tree_mode:type_name -> filebank.TreeMode
+	25, // 4: filebank.ChallengeResponse.tree_version:type_name -> filebank.TreeVersion
+	26, // 5: filebank.ChallengeResponse.hash_algorithm:type_name -> filebank.HashAlgorithm
+	9,  // 6: filebank.DownloadFilesRequest.range:type_name -> filebank.ByteRange
+	10, // 7: filebank.DownloadFilesResponse.fp:type_name -> filebank.FileAndProof
+	11, // 8: filebank.DownloadFilesResponse.fmp:type_name -> filebank.FilesAndMultiProof
+	12, // 9: filebank.DownloadFilesResponse.rp:type_name -> filebank.RangeAndProof
+	5,  // 10: filebank.DownloadFilesResponse.file:type_name -> filebank.FileMessage
+	15, // 11: filebank.AppendFilesRequest.append_req:type_name -> filebank.AppendRequest
+	5,  // 12: filebank.AppendFilesRequest.file:type_name -> filebank.FileMessage
+	16, // 13: filebank.AppendFilesResponse.appended_root:type_name -> filebank.AppendedRoot
+	19, // 14: filebank.RotateBankRequest.rotate_req:type_name -> filebank.RotateRequest
+	5,  // 15: filebank.RotateBankRequest.file:type_name -> filebank.FileMessage
+	20, // 16: filebank.RotateBankResponse.rotated_root:type_name -> filebank.RotatedRoot
+	27, // 17: filebank.RotateRequest.handover:type_name -> filebank.BankHandover
+	28, // 18: filebank.BankManifestRequest.recipients:type_name -> filebank.BankRecipient
+	23, // 19: filebank.BankManifestResponse.result:type_name -> filebank.ManifestResult
+	28, // 20: filebank.ManifestResult.recipients:type_name -> filebank.BankRecipient
+	0,  // 21: filebank.FileBankService.AddNode:input_type -> filebank.AddNodeRequest
+	2,  // 22: filebank.FileBankService.UploadFiles:input_type -> filebank.UploadFilesRequest
+	7,  // 23: filebank.FileBankService.DownloadFiles:input_type -> filebank.DownloadFilesRequest
+	13, // 24: filebank.FileBankService.AppendFiles:input_type -> filebank.AppendFilesRequest
+	17, // 25: filebank.FileBankService.RotateBank:input_type -> filebank.RotateBankRequest
+	21, // 26: filebank.FileBankService.BankManifest:input_type -> filebank.BankManifestRequest
+	1,  // 27: filebank.FileBankService.AddNode:output_type -> filebank.AddNodeResponse
+	3,  // 28: filebank.FileBankService.UploadFiles:output_type -> filebank.UploadFilesResponse
+	8,  // 29: filebank.FileBankService.DownloadFiles:output_type -> filebank.DownloadFilesResponse
+	14, // 30: filebank.FileBankService.AppendFiles:output_type -> filebank.AppendFilesResponse
+	18, // 31: filebank.FileBankService.RotateBank:output_type -> filebank.RotateBankResponse
+	22, // 32: filebank.FileBankService.BankManifest:output_type -> filebank.BankManifestResponse
+	27, // [27:33] is the sub-list for method output_type
+	21, // [21:27] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_proto_filebank_proto_init() }
+func file_proto_filebank_proto_init() {
+	if File_proto_filebank_proto != nil {
+		return
+	}
+	file_proto_storage_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_proto_filebank_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddNodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddNodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadFilesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
 			case 2:
 				return &v.unknownFields
 			default:
@@ -947,6 +2397,18 @@ func file_proto_filebank_proto_init() {
 			}
 		}
 		file_proto_filebank_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ByteRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FileAndProof); i {
 			case 0:
 				return &v.state
@@ -958,6 +2420,162 @@ func file_proto_filebank_proto_init() {
 				return nil
 			}
 		}
+		file_proto_filebank_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilesAndMultiProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RangeAndProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppendFilesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppendFilesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppendRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppendedRoot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateBankRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateBankResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotatedRoot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankManifestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankManifestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_filebank_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ManifestResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_proto_filebank_proto_msgTypes[2].OneofWrappers = []interface{}{
 		(*UploadFilesRequest_SignedResp)(nil),
@@ -971,6 +2589,30 @@ func file_proto_filebank_proto_init() {
 	file_proto_filebank_proto_msgTypes[8].OneofWrappers = []interface{}{
 		(*DownloadFilesResponse_Nonce)(nil),
 		(*DownloadFilesResponse_Fp)(nil),
+		(*DownloadFilesResponse_Fmp)(nil),
+		(*DownloadFilesResponse_Rp)(nil),
+		(*DownloadFilesResponse_File)(nil),
+	}
+	file_proto_filebank_proto_msgTypes[13].OneofWrappers = []interface{}{
+		(*AppendFilesRequest_AppendReq)(nil),
+		(*AppendFilesRequest_File)(nil),
+		(*AppendFilesRequest_Nonce)(nil),
+	}
+	file_proto_filebank_proto_msgTypes[14].OneofWrappers = []interface{}{
+		(*AppendFilesResponse_Nonce)(nil),
+		(*AppendFilesResponse_AppendedRoot)(nil),
+	}
+	file_proto_filebank_proto_msgTypes[17].OneofWrappers = []interface{}{
+		(*RotateBankRequest_RotateReq)(nil),
+		(*RotateBankRequest_File)(nil),
+	}
+	file_proto_filebank_proto_msgTypes[18].OneofWrappers = []interface{}{
+		(*RotateBankResponse_Nonce)(nil),
+		(*RotateBankResponse_RotatedRoot)(nil),
+	}
+	file_proto_filebank_proto_msgTypes[22].OneofWrappers = []interface{}{
+		(*BankManifestResponse_Nonce)(nil),
+		(*BankManifestResponse_Result)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -978,7 +2620,7 @@ func file_proto_filebank_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_filebank_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,9 @@ type FileBankServiceClient interface {
 	AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error)
 	UploadFiles(ctx context.Context, opts ...grpc.CallOption) (FileBankService_UploadFilesClient, error)
 	DownloadFiles(ctx context.Context, opts ...grpc.CallOption) (FileBankService_DownloadFilesClient, error)
+	AppendFiles(ctx context.Context, opts ...grpc.CallOption) (FileBankService_AppendFilesClient, error)
+	RotateBank(ctx context.Context, opts ...grpc.CallOption) (FileBankService_RotateBankClient, error)
+	BankManifest(ctx context.Context, opts ...grpc.CallOption) (FileBankService_BankManifestClient, error)
 }
 
 type fileBankServiceClient struct {
@@ -106,6 +109,99 @@ func (x *fileBankServiceDownloadFilesClient) Recv() (*DownloadFilesResponse, err
 	return m, nil
 }
 
+func (c *fileBankServiceClient) AppendFiles(ctx context.Context, opts ...grpc.CallOption) (FileBankService_AppendFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileBankService_ServiceDesc.Streams[2], "/filebank.FileBankService/AppendFiles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileBankServiceAppendFilesClient{stream}
+	return x, nil
+}
+
+type FileBankService_AppendFilesClient interface {
+	Send(*AppendFilesRequest) error
+	Recv() (*AppendFilesResponse, error)
+	grpc.ClientStream
+}
+
+type fileBankServiceAppendFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileBankServiceAppendFilesClient) Send(m *AppendFilesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileBankServiceAppendFilesClient) Recv() (*AppendFilesResponse, error) {
+	m := new(AppendFilesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileBankServiceClient) RotateBank(ctx context.Context, opts ...grpc.CallOption) (FileBankService_RotateBankClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileBankService_ServiceDesc.Streams[3], "/filebank.FileBankService/RotateBank", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileBankServiceRotateBankClient{stream}
+	return x, nil
+}
+
+type FileBankService_RotateBankClient interface {
+	Send(*RotateBankRequest) error
+	Recv() (*RotateBankResponse, error)
+	grpc.ClientStream
+}
+
+type fileBankServiceRotateBankClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileBankServiceRotateBankClient) Send(m *RotateBankRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileBankServiceRotateBankClient) Recv() (*RotateBankResponse, error) {
+	m := new(RotateBankResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileBankServiceClient) BankManifest(ctx context.Context, opts ...grpc.CallOption) (FileBankService_BankManifestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileBankService_ServiceDesc.Streams[4], "/filebank.FileBankService/BankManifest", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileBankServiceBankManifestClient{stream}
+	return x, nil
+}
+
+type FileBankService_BankManifestClient interface {
+	Send(*BankManifestRequest) error
+	Recv() (*BankManifestResponse, error)
+	grpc.ClientStream
+}
+
+type fileBankServiceBankManifestClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileBankServiceBankManifestClient) Send(m *BankManifestRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileBankServiceBankManifestClient) Recv() (*BankManifestResponse, error) {
+	m := new(BankManifestResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FileBankServiceServer is the server API for FileBankService service.
 // All implementations must embed UnimplementedFileBankServiceServer
 // for forward compatibility
@@ -113,6 +209,9 @@ type FileBankServiceServer interface {
 	AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error)
 	UploadFiles(FileBankService_UploadFilesServer) error
 	DownloadFiles(FileBankService_DownloadFilesServer) error
+	AppendFiles(FileBankService_AppendFilesServer) error
+	RotateBank(FileBankService_RotateBankServer) error
+	BankManifest(FileBankService_BankManifestServer) error
 	mustEmbedUnimplementedFileBankServiceServer()
 }
 
@@ -129,6 +228,15 @@ func (UnimplementedFileBankServiceServer) UploadFiles(FileBankService_UploadFile
 func (UnimplementedFileBankServiceServer) DownloadFiles(FileBankService_DownloadFilesServer) error {
 	return status.Errorf(codes.Unimplemented, "method DownloadFiles not implemented")
 }
+func (UnimplementedFileBankServiceServer) AppendFiles(FileBankService_AppendFilesServer) error {
+	return status.Errorf(codes.Unimplemented, "method AppendFiles not implemented")
+}
+func (UnimplementedFileBankServiceServer) RotateBank(FileBankService_RotateBankServer) error {
+	return status.Errorf(codes.Unimplemented, "method RotateBank not implemented")
+}
+func (UnimplementedFileBankServiceServer) BankManifest(FileBankService_BankManifestServer) error {
+	return status.Errorf(codes.Unimplemented, "method BankManifest not implemented")
+}
 func (UnimplementedFileBankServiceServer) mustEmbedUnimplementedFileBankServiceServer() {}
 
 // UnsafeFileBankServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -212,6 +320,84 @@ func (x *fileBankServiceDownloadFilesServer) Recv() (*DownloadFilesRequest, erro
 	return m, nil
 }
 
+func _FileBankService_AppendFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileBankServiceServer).AppendFiles(&fileBankServiceAppendFilesServer{stream})
+}
+
+type FileBankService_AppendFilesServer interface {
+	Send(*AppendFilesResponse) error
+	Recv() (*AppendFilesRequest, error)
+	grpc.ServerStream
+}
+
+type fileBankServiceAppendFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileBankServiceAppendFilesServer) Send(m *AppendFilesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileBankServiceAppendFilesServer) Recv() (*AppendFilesRequest, error) {
+	m := new(AppendFilesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileBankService_RotateBank_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileBankServiceServer).RotateBank(&fileBankServiceRotateBankServer{stream})
+}
+
+type FileBankService_RotateBankServer interface {
+	Send(*RotateBankResponse) error
+	Recv() (*RotateBankRequest, error)
+	grpc.ServerStream
+}
+
+type fileBankServiceRotateBankServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileBankServiceRotateBankServer) Send(m *RotateBankResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileBankServiceRotateBankServer) Recv() (*RotateBankRequest, error) {
+	m := new(RotateBankRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileBankService_BankManifest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileBankServiceServer).BankManifest(&fileBankServiceBankManifestServer{stream})
+}
+
+type FileBankService_BankManifestServer interface {
+	Send(*BankManifestResponse) error
+	Recv() (*BankManifestRequest, error)
+	grpc.ServerStream
+}
+
+type fileBankServiceBankManifestServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileBankServiceBankManifestServer) Send(m *BankManifestResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileBankServiceBankManifestServer) Recv() (*BankManifestRequest, error) {
+	m := new(BankManifestRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FileBankService_ServiceDesc is the grpc.ServiceDesc for FileBankService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -237,6 +423,24 @@ var FileBankService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "AppendFiles",
+			Handler:       _FileBankService_AppendFiles_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RotateBank",
+			Handler:       _FileBankService_RotateBank_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BankManifest",
+			Handler:       _FileBankService_BankManifest_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/filebank.proto",
 }
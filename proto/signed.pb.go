@@ -80,9 +80,13 @@ type SignUploadRequestClient struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Nonce   []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	PubKey  []byte `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
-	Nbfiles int32  `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	Nonce         []byte        `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKey        []byte        `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Nbfiles       int32         `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	TreeMode      TreeMode      `protobuf:"varint,4,opt,name=tree_mode,json=treeMode,proto3,enum=filebank.TreeMode" json:"tree_mode,omitempty"`
+	TreeVersion   TreeVersion   `protobuf:"varint,5,opt,name=tree_version,json=treeVersion,proto3,enum=filebank.TreeVersion" json:"tree_version,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,6,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=filebank.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	ChunkSize     int32         `protobuf:"varint,7,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
 }
 
 func (x *SignUploadRequestClient) Reset() {
@@ -138,6 +142,34 @@ func (x *SignUploadRequestClient) GetNbfiles() int32 {
 	return 0
 }
 
+func (x *SignUploadRequestClient) GetTreeMode() TreeMode {
+	if x != nil {
+		return x.TreeMode
+	}
+	return TreeMode_SORTED_TREE
+}
+
+func (x *SignUploadRequestClient) GetTreeVersion() TreeVersion {
+	if x != nil {
+		return x.TreeVersion
+	}
+	return TreeVersion_TREE_V1
+}
+
+func (x *SignUploadRequestClient) GetHashAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.HashAlgorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+func (x *SignUploadRequestClient) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
 type SignMerkleRootServer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -198,9 +230,13 @@ type SignDownloadRequestClient struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	PubKeyAddr string `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
-	FileNum    int32  `protobuf:"varint,3,opt,name=file_num,json=fileNum,proto3" json:"file_num,omitempty"`
+	Nonce       []byte  `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr  string  `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	FileNum     int32   `protobuf:"varint,3,opt,name=file_num,json=fileNum,proto3" json:"file_num,omitempty"`
+	FileNums    []int32 `protobuf:"varint,4,rep,packed,name=file_nums,json=fileNums,proto3" json:"file_nums,omitempty"`
+	RangeOffset int64   `protobuf:"varint,5,opt,name=range_offset,json=rangeOffset,proto3" json:"range_offset,omitempty"`
+	RangeLength int64   `protobuf:"varint,6,opt,name=range_length,json=rangeLength,proto3" json:"range_length,omitempty"`
+	Signer      []byte  `protobuf:"bytes,7,opt,name=signer,proto3" json:"signer,omitempty"`
 }
 
 func (x *SignDownloadRequestClient) Reset() {
@@ -256,35 +292,507 @@ func (x *SignDownloadRequestClient) GetFileNum() int32 {
 	return 0
 }
 
+func (x *SignDownloadRequestClient) GetFileNums() []int32 {
+	if x != nil {
+		return x.FileNums
+	}
+	return nil
+}
+
+func (x *SignDownloadRequestClient) GetRangeOffset() int64 {
+	if x != nil {
+		return x.RangeOffset
+	}
+	return 0
+}
+
+func (x *SignDownloadRequestClient) GetRangeLength() int64 {
+	if x != nil {
+		return x.RangeLength
+	}
+	return 0
+}
+
+func (x *SignDownloadRequestClient) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type SignAppendRequestClient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr string `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	OldNbfiles int32  `protobuf:"varint,3,opt,name=old_nbfiles,json=oldNbfiles,proto3" json:"old_nbfiles,omitempty"`
+	Nbfiles    int32  `protobuf:"varint,4,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+}
+
+func (x *SignAppendRequestClient) Reset() {
+	*x = SignAppendRequestClient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_signed_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignAppendRequestClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignAppendRequestClient) ProtoMessage() {}
+
+func (x *SignAppendRequestClient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signed_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignAppendRequestClient.ProtoReflect.Descriptor instead.
+func (*SignAppendRequestClient) Descriptor() ([]byte, []int) {
+	return file_proto_signed_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SignAppendRequestClient) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *SignAppendRequestClient) GetPubKeyAddr() string {
+	if x != nil {
+		return x.PubKeyAddr
+	}
+	return ""
+}
+
+func (x *SignAppendRequestClient) GetOldNbfiles() int32 {
+	if x != nil {
+		return x.OldNbfiles
+	}
+	return 0
+}
+
+func (x *SignAppendRequestClient) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+type SignAppendedRootServer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	MerkleRoot []byte `protobuf:"bytes,2,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Nbfiles    int32  `protobuf:"varint,3,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+}
+
+func (x *SignAppendedRootServer) Reset() {
+	*x = SignAppendedRootServer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_signed_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignAppendedRootServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignAppendedRootServer) ProtoMessage() {}
+
+func (x *SignAppendedRootServer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signed_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignAppendedRootServer.ProtoReflect.Descriptor instead.
+func (*SignAppendedRootServer) Descriptor() ([]byte, []int) {
+	return file_proto_signed_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SignAppendedRootServer) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *SignAppendedRootServer) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *SignAppendedRootServer) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+type SignRotateRequestClient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte           `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	OldPubKey  []byte           `protobuf:"bytes,2,opt,name=old_pub_key,json=oldPubKey,proto3" json:"old_pub_key,omitempty"`
+	NewPubKey  []byte           `protobuf:"bytes,3,opt,name=new_pub_key,json=newPubKey,proto3" json:"new_pub_key,omitempty"`
+	Nbfiles    int32            `protobuf:"varint,4,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	MerkleRoot []byte           `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Recipients []*BankRecipient `protobuf:"bytes,6,rep,name=recipients,proto3" json:"recipients,omitempty"`
+}
+
+func (x *SignRotateRequestClient) Reset() {
+	*x = SignRotateRequestClient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_signed_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignRotateRequestClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRotateRequestClient) ProtoMessage() {}
+
+func (x *SignRotateRequestClient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signed_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRotateRequestClient.ProtoReflect.Descriptor instead.
+func (*SignRotateRequestClient) Descriptor() ([]byte, []int) {
+	return file_proto_signed_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SignRotateRequestClient) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *SignRotateRequestClient) GetOldPubKey() []byte {
+	if x != nil {
+		return x.OldPubKey
+	}
+	return nil
+}
+
+func (x *SignRotateRequestClient) GetNewPubKey() []byte {
+	if x != nil {
+		return x.NewPubKey
+	}
+	return nil
+}
+
+func (x *SignRotateRequestClient) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *SignRotateRequestClient) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *SignRotateRequestClient) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+type SignBankHandoverServer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	OldPubKey  []byte `protobuf:"bytes,2,opt,name=old_pub_key,json=oldPubKey,proto3" json:"old_pub_key,omitempty"`
+	NewPubKey  []byte `protobuf:"bytes,3,opt,name=new_pub_key,json=newPubKey,proto3" json:"new_pub_key,omitempty"`
+	MerkleRoot []byte `protobuf:"bytes,4,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+}
+
+func (x *SignBankHandoverServer) Reset() {
+	*x = SignBankHandoverServer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_signed_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignBankHandoverServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignBankHandoverServer) ProtoMessage() {}
+
+func (x *SignBankHandoverServer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signed_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignBankHandoverServer.ProtoReflect.Descriptor instead.
+func (*SignBankHandoverServer) Descriptor() ([]byte, []int) {
+	return file_proto_signed_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SignBankHandoverServer) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *SignBankHandoverServer) GetOldPubKey() []byte {
+	if x != nil {
+		return x.OldPubKey
+	}
+	return nil
+}
+
+func (x *SignBankHandoverServer) GetNewPubKey() []byte {
+	if x != nil {
+		return x.NewPubKey
+	}
+	return nil
+}
+
+func (x *SignBankHandoverServer) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+type SignManifestRequestClient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce      []byte           `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	PubKeyAddr string           `protobuf:"bytes,2,opt,name=pub_key_addr,json=pubKeyAddr,proto3" json:"pub_key_addr,omitempty"`
+	Manifest   []byte           `protobuf:"bytes,3,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	Recipients []*BankRecipient `protobuf:"bytes,4,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	Signer     []byte           `protobuf:"bytes,5,opt,name=signer,proto3" json:"signer,omitempty"`
+}
+
+func (x *SignManifestRequestClient) Reset() {
+	*x = SignManifestRequestClient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_signed_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignManifestRequestClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignManifestRequestClient) ProtoMessage() {}
+
+func (x *SignManifestRequestClient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_signed_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignManifestRequestClient.ProtoReflect.Descriptor instead.
+func (*SignManifestRequestClient) Descriptor() ([]byte, []int) {
+	return file_proto_signed_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SignManifestRequestClient) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *SignManifestRequestClient) GetPubKeyAddr() string {
+	if x != nil {
+		return x.PubKeyAddr
+	}
+	return ""
+}
+
+func (x *SignManifestRequestClient) GetManifest() []byte {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *SignManifestRequestClient) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *SignManifestRequestClient) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
 var File_proto_signed_proto protoreflect.FileDescriptor
 
 var file_proto_signed_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x22, 0x42,
-	0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x1a, 0x13,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x42, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x64, 0x64, 0x4e, 0x6f,
+	0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0xac, 0x02, 0x0a, 0x17, 0x53, 0x69, 0x67, 0x6e,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62,
 	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b,
-	0x65, 0x79, 0x22, 0x62, 0x0a, 0x17, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a,
-	0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f,
-	0x6e, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07,
-	0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6e,
-	0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x4d, 0x0a, 0x14, 0x53, 0x69, 0x67, 0x6e, 0x4d, 0x65,
+	0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x09,
+	0x74, 0x72, 0x65, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x12, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4d,
+	0x6f, 0x64, 0x65, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a,
+	0x0c, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x65, 0x65,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x5f,
+	0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41,
+	0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x0d, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c,
+	0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x4d, 0x0a, 0x14, 0x53, 0x69, 0x67, 0x6e, 0x4d, 0x65,
 	0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x14,
 	0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e,
 	0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72,
 	0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c,
-	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0x6e, 0x0a, 0x19, 0x53, 0x69, 0x67, 0x6e, 0x44, 0x6f, 0x77,
-	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x5f,
-	0x6b, 0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x69,
-	0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x66, 0x69,
-	0x6c, 0x65, 0x4e, 0x75, 0x6d, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0xe9, 0x01, 0x0a, 0x19, 0x53, 0x69, 0x67, 0x6e, 0x44, 0x6f,
+	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x75, 0x62,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x66,
+	0x69, 0x6c, 0x65, 0x4e, 0x75, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e,
+	0x75, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e,
+	0x75, 0x6d, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x22, 0x8c, 0x01, 0x0a, 0x17, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x6e, 0x62, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6f, 0x6c, 0x64, 0x4e,
+	0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x22, 0x69, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64,
+	0x52, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0xe3, 0x01, 0x0a, 0x17,
+	0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x0a,
+	0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a,
+	0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a,
+	0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65,
+	0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66,
+	0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74,
+	0x73, 0x22, 0x8f, 0x01, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x42, 0x61, 0x6e, 0x6b, 0x48, 0x61,
+	0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x22, 0xc0, 0x01, 0x0a, 0x19, 0x53, 0x69, 0x67, 0x6e, 0x4d, 0x61, 0x6e, 0x69,
+	0x66, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x75, 0x62, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -299,19 +807,33 @@ func file_proto_signed_proto_rawDescGZIP() []byte {
 	return file_proto_signed_proto_rawDescData
 }
 
-var file_proto_signed_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_signed_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_proto_signed_proto_goTypes = []interface{}{
 	(*SignAddNodeServer)(nil),         // 0: filebank.SignAddNodeServer
 	(*SignUploadRequestClient)(nil),   // 1: filebank.SignUploadRequestClient
 	(*SignMerkleRootServer)(nil),      // 2: filebank.SignMerkleRootServer
 	(*SignDownloadRequestClient)(nil), // 3: filebank.SignDownloadRequestClient
+	(*SignAppendRequestClient)(nil),   // 4: filebank.SignAppendRequestClient
+	(*SignAppendedRootServer)(nil),    // 5: filebank.SignAppendedRootServer
+	(*SignRotateRequestClient)(nil),   // 6: filebank.SignRotateRequestClient
+	(*SignBankHandoverServer)(nil),    // 7: filebank.SignBankHandoverServer
+	(*SignManifestRequestClient)(nil), // 8: filebank.SignManifestRequestClient
+	(TreeMode)(0),                     // 9: filebank.TreeMode
+	(TreeVersion)(0),                  // 10: filebank.TreeVersion
+	(HashAlgorithm)(0),                // 11: filebank.HashAlgorithm
+	(*BankRecipient)(nil),             // 12: filebank.BankRecipient
 }
 var file_proto_signed_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	9,  // 0: filebank.SignUploadRequestClient.tree_mode:type_name -> filebank.TreeMode
+	10, // 1: filebank.SignUploadRequestClient.tree_version:type_name -> filebank.TreeVersion
+	11, // 2: filebank.SignUploadRequestClient.hash_algorithm:type_name -> filebank.HashAlgorithm
+	12, // 3: filebank.SignRotateRequestClient.recipients:type_name -> filebank.BankRecipient
+	12, // 4: filebank.SignManifestRequestClient.recipients:type_name -> filebank.BankRecipient
+	5,  // [5:5] is the sub-list for method output_type
+	5,  // [5:5] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_signed_proto_init() }
@@ -319,6 +841,7 @@ func file_proto_signed_proto_init() {
 	if File_proto_signed_proto != nil {
 		return
 	}
+	file_proto_storage_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_proto_signed_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignAddNodeServer); i {
@@ -368,6 +891,66 @@ func file_proto_signed_proto_init() {
 				return nil
 			}
 		}
+		file_proto_signed_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignAppendRequestClient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_signed_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignAppendedRootServer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_signed_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignRotateRequestClient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_signed_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignBankHandoverServer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_signed_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignManifestRequestClient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -375,7 +958,7 @@ func file_proto_signed_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_signed_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -20,33 +20,1342 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type TreeMode int32
+
+const (
+	TreeMode_SORTED_TREE    TreeMode = 0
+	TreeMode_INDEXED_TREE   TreeMode = 1
+	TreeMode_MOUNTAIN_RANGE TreeMode = 2
+)
+
+// Enum value maps for TreeMode.
+var (
+	TreeMode_name = map[int32]string{
+		0: "SORTED_TREE",
+		1: "INDEXED_TREE",
+		2: "MOUNTAIN_RANGE",
+	}
+	TreeMode_value = map[string]int32{
+		"SORTED_TREE":    0,
+		"INDEXED_TREE":   1,
+		"MOUNTAIN_RANGE": 2,
+	}
+)
+
+func (x TreeMode) Enum() *TreeMode {
+	p := new(TreeMode)
+	*p = x
+	return p
+}
+
+func (x TreeMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TreeMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[0].Descriptor()
+}
+
+func (TreeMode) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[0]
+}
+
+func (x TreeMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TreeMode.Descriptor instead.
+func (TreeMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{0}
+}
+
+type TreeVersion int32
+
+const (
+	TreeVersion_TREE_V1           TreeVersion = 0
+	TreeVersion_TREE_V2           TreeVersion = 1
+	TreeVersion_TREE_OPENZEPPELIN TreeVersion = 2
+)
+
+// Enum value maps for TreeVersion.
+var (
+	TreeVersion_name = map[int32]string{
+		0: "TREE_V1",
+		1: "TREE_V2",
+		2: "TREE_OPENZEPPELIN",
+	}
+	TreeVersion_value = map[string]int32{
+		"TREE_V1":           0,
+		"TREE_V2":           1,
+		"TREE_OPENZEPPELIN": 2,
+	}
+)
+
+func (x TreeVersion) Enum() *TreeVersion {
+	p := new(TreeVersion)
+	*p = x
+	return p
+}
+
+func (x TreeVersion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TreeVersion) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[1].Descriptor()
+}
+
+func (TreeVersion) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[1]
+}
+
+func (x TreeVersion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TreeVersion.Descriptor instead.
+func (TreeVersion) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{1}
+}
+
+type HashAlgorithm int32
+
+const (
+	HashAlgorithm_SHA256      HashAlgorithm = 0
+	HashAlgorithm_SHA512_256  HashAlgorithm = 1
+	HashAlgorithm_BLAKE2B_256 HashAlgorithm = 2
+	HashAlgorithm_KECCAK256   HashAlgorithm = 3
+)
+
+// Enum value maps for HashAlgorithm.
+var (
+	HashAlgorithm_name = map[int32]string{
+		0: "SHA256",
+		1: "SHA512_256",
+		2: "BLAKE2B_256",
+		3: "KECCAK256",
+	}
+	HashAlgorithm_value = map[string]int32{
+		"SHA256":      0,
+		"SHA512_256":  1,
+		"BLAKE2B_256": 2,
+		"KECCAK256":   3,
+	}
+)
+
+func (x HashAlgorithm) Enum() *HashAlgorithm {
+	p := new(HashAlgorithm)
+	*p = x
+	return p
+}
+
+func (x HashAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HashAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[2].Descriptor()
+}
+
+func (HashAlgorithm) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[2]
+}
+
+func (x HashAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HashAlgorithm.Descriptor instead.
+func (HashAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{2}
+}
+
+type Compression int32
+
+const (
+	Compression_NO_COMPRESSION Compression = 0
+	Compression_GZIP           Compression = 1
+	Compression_ZSTD           Compression = 2
+)
+
+// Enum value maps for Compression.
+var (
+	Compression_name = map[int32]string{
+		0: "NO_COMPRESSION",
+		1: "GZIP",
+		2: "ZSTD",
+	}
+	Compression_value = map[string]int32{
+		"NO_COMPRESSION": 0,
+		"GZIP":           1,
+		"ZSTD":           2,
+	}
+)
+
+func (x Compression) Enum() *Compression {
+	p := new(Compression)
+	*p = x
+	return p
+}
+
+func (x Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[3].Descriptor()
+}
+
+func (Compression) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[3]
+}
+
+func (x Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Compression.Descriptor instead.
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{3}
+}
+
+type CipherSuite int32
+
+const (
+	CipherSuite_AES_128_GCM        CipherSuite = 0
+	CipherSuite_AES_256_GCM        CipherSuite = 1
+	CipherSuite_XCHACHA20_POLY1305 CipherSuite = 2
+)
+
+// Enum value maps for CipherSuite.
+var (
+	CipherSuite_name = map[int32]string{
+		0: "AES_128_GCM",
+		1: "AES_256_GCM",
+		2: "XCHACHA20_POLY1305",
+	}
+	CipherSuite_value = map[string]int32{
+		"AES_128_GCM":        0,
+		"AES_256_GCM":        1,
+		"XCHACHA20_POLY1305": 2,
+	}
+)
+
+func (x CipherSuite) Enum() *CipherSuite {
+	p := new(CipherSuite)
+	*p = x
+	return p
+}
+
+func (x CipherSuite) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CipherSuite) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[4].Descriptor()
+}
+
+func (CipherSuite) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[4]
+}
+
+func (x CipherSuite) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CipherSuite.Descriptor instead.
+func (CipherSuite) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{4}
+}
+
+type DescriptorVersion int32
+
+const (
+	// the key of each file is derived from the passphrase
+	DescriptorVersion_DESCRIPTOR_V1 DescriptorVersion = 0
+	// the key of each file is derived by HKDF from the master key of the bank and the salt of the file
+	DescriptorVersion_DESCRIPTOR_V2 DescriptorVersion = 1
+)
+
+// Enum value maps for DescriptorVersion.
+var (
+	DescriptorVersion_name = map[int32]string{
+		0: "DESCRIPTOR_V1",
+		1: "DESCRIPTOR_V2",
+	}
+	DescriptorVersion_value = map[string]int32{
+		"DESCRIPTOR_V1": 0,
+		"DESCRIPTOR_V2": 1,
+	}
+)
+
+func (x DescriptorVersion) Enum() *DescriptorVersion {
+	p := new(DescriptorVersion)
+	*p = x
+	return p
+}
+
+func (x DescriptorVersion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DescriptorVersion) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[5].Descriptor()
+}
+
+func (DescriptorVersion) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[5]
+}
+
+func (x DescriptorVersion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DescriptorVersion.Descriptor instead.
+func (DescriptorVersion) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{5}
+}
+
+type Kdf int32
+
+const (
+	Kdf_PBKDF2_SHA1 Kdf = 0
+	Kdf_ARGON2ID    Kdf = 1
+)
+
+// Enum value maps for Kdf.
+var (
+	Kdf_name = map[int32]string{
+		0: "PBKDF2_SHA1",
+		1: "ARGON2ID",
+	}
+	Kdf_value = map[string]int32{
+		"PBKDF2_SHA1": 0,
+		"ARGON2ID":    1,
+	}
+)
+
+func (x Kdf) Enum() *Kdf {
+	p := new(Kdf)
+	*p = x
+	return p
+}
+
+func (x Kdf) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Kdf) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_storage_proto_enumTypes[6].Descriptor()
+}
+
+func (Kdf) Type() protoreflect.EnumType {
+	return &file_proto_storage_proto_enumTypes[6]
+}
+
+func (x Kdf) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Kdf.Descriptor instead.
+func (Kdf) EnumDescriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{6}
+}
+
 type ServerBankDescriptor struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PubKey       []byte   `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
-	Nbfiles      int32    `protobuf:"varint,2,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
-	MerkleHashes [][]byte `protobuf:"bytes,3,rep,name=merkle_hashes,json=merkleHashes,proto3" json:"merkle_hashes,omitempty"`
+	PubKey        []byte        `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Nbfiles       int32         `protobuf:"varint,2,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	MerkleHashes  [][]byte      `protobuf:"bytes,3,rep,name=merkle_hashes,json=merkleHashes,proto3" json:"merkle_hashes,omitempty"`
+	TreeMode      TreeMode      `protobuf:"varint,4,opt,name=tree_mode,json=treeMode,proto3,enum=filebank.TreeMode" json:"tree_mode,omitempty"`
+	TreeVersion   TreeVersion   `protobuf:"varint,5,opt,name=tree_version,json=treeVersion,proto3,enum=filebank.TreeVersion" json:"tree_version,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,6,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=filebank.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	ChunkSize     int32         `protobuf:"varint,7,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	// merkle hashes are stored in a tree file instead of merkle_hashes
+	TreeFile bool `protobuf:"varint,8,opt,name=tree_file,json=treeFile,proto3" json:"tree_file,omitempty"`
+	// handover from the previous key of the bank, when it was rotated
+	Handover *BankHandover `protobuf:"bytes,9,opt,name=handover,proto3" json:"handover,omitempty"`
+	// recipients the bank is shared with, whose identity keys may download files and read the manifest
+	Recipients []*BankRecipient `protobuf:"bytes,10,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	// nonce and bank key signature of the request that stored the manifest and the recipients, over
+	// SignManifestRequestClient, for recipients to verify them
+	ManifestNonce     []byte `protobuf:"bytes,11,opt,name=manifest_nonce,json=manifestNonce,proto3" json:"manifest_nonce,omitempty"`
+	ManifestSignature []byte `protobuf:"bytes,12,opt,name=manifest_signature,json=manifestSignature,proto3" json:"manifest_signature,omitempty"`
+}
+
+func (x *ServerBankDescriptor) Reset() {
+	*x = ServerBankDescriptor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerBankDescriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerBankDescriptor) ProtoMessage() {}
+
+func (x *ServerBankDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerBankDescriptor.ProtoReflect.Descriptor instead.
+func (*ServerBankDescriptor) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ServerBankDescriptor) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *ServerBankDescriptor) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *ServerBankDescriptor) GetMerkleHashes() [][]byte {
+	if x != nil {
+		return x.MerkleHashes
+	}
+	return nil
+}
+
+func (x *ServerBankDescriptor) GetTreeMode() TreeMode {
+	if x != nil {
+		return x.TreeMode
+	}
+	return TreeMode_SORTED_TREE
+}
+
+func (x *ServerBankDescriptor) GetTreeVersion() TreeVersion {
+	if x != nil {
+		return x.TreeVersion
+	}
+	return TreeVersion_TREE_V1
+}
+
+func (x *ServerBankDescriptor) GetHashAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.HashAlgorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+func (x *ServerBankDescriptor) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *ServerBankDescriptor) GetTreeFile() bool {
+	if x != nil {
+		return x.TreeFile
+	}
+	return false
+}
+
+func (x *ServerBankDescriptor) GetHandover() *BankHandover {
+	if x != nil {
+		return x.Handover
+	}
+	return nil
+}
+
+func (x *ServerBankDescriptor) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *ServerBankDescriptor) GetManifestNonce() []byte {
+	if x != nil {
+		return x.ManifestNonce
+	}
+	return nil
+}
+
+func (x *ServerBankDescriptor) GetManifestSignature() []byte {
+	if x != nil {
+		return x.ManifestSignature
+	}
+	return nil
+}
+
+// handover of a bank to a new key, signed by the old and the new key of the bank over SignRotateRequestClient
+type BankHandover struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce        []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	OldPubKey    []byte `protobuf:"bytes,2,opt,name=old_pub_key,json=oldPubKey,proto3" json:"old_pub_key,omitempty"`
+	NewPubKey    []byte `protobuf:"bytes,3,opt,name=new_pub_key,json=newPubKey,proto3" json:"new_pub_key,omitempty"`
+	Nbfiles      int32  `protobuf:"varint,4,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	MerkleRoot   []byte `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	OldSignature []byte `protobuf:"bytes,6,opt,name=old_signature,json=oldSignature,proto3" json:"old_signature,omitempty"`
+	NewSignature []byte `protobuf:"bytes,7,opt,name=new_signature,json=newSignature,proto3" json:"new_signature,omitempty"`
+	// recipients of the new bank, with the new master key wrapped to them
+	Recipients []*BankRecipient `protobuf:"bytes,8,rep,name=recipients,proto3" json:"recipients,omitempty"`
+}
+
+func (x *BankHandover) Reset() {
+	*x = BankHandover{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankHandover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankHandover) ProtoMessage() {}
+
+func (x *BankHandover) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankHandover.ProtoReflect.Descriptor instead.
+func (*BankHandover) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BankHandover) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *BankHandover) GetOldPubKey() []byte {
+	if x != nil {
+		return x.OldPubKey
+	}
+	return nil
+}
+
+func (x *BankHandover) GetNewPubKey() []byte {
+	if x != nil {
+		return x.NewPubKey
+	}
+	return nil
+}
+
+func (x *BankHandover) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *BankHandover) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *BankHandover) GetOldSignature() []byte {
+	if x != nil {
+		return x.OldSignature
+	}
+	return nil
+}
+
+func (x *BankHandover) GetNewSignature() []byte {
+	if x != nil {
+		return x.NewSignature
+	}
+	return nil
+}
+
+func (x *BankHandover) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+type ClientBankDescriptor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PrivKey         []byte            `protobuf:"bytes,5,opt,name=priv_key,json=privKey,proto3" json:"priv_key,omitempty"`
+	Nbfiles         int32             `protobuf:"varint,6,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
+	MerkleRoot      []byte            `protobuf:"bytes,7,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	FileDescriptors []*FileDescriptor `protobuf:"bytes,8,rep,name=file_descriptors,json=fileDescriptors,proto3" json:"file_descriptors,omitempty"`
+	TreeMode        TreeMode          `protobuf:"varint,9,opt,name=tree_mode,json=treeMode,proto3,enum=filebank.TreeMode" json:"tree_mode,omitempty"`
+	TreeVersion     TreeVersion       `protobuf:"varint,10,opt,name=tree_version,json=treeVersion,proto3,enum=filebank.TreeVersion" json:"tree_version,omitempty"`
+	HashAlgorithm   HashAlgorithm     `protobuf:"varint,11,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=filebank.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	ChunkSize       int32             `protobuf:"varint,12,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	// derivation of the keys of new files of v1 banks, PBKDF2 when not set
+	Kdf     *KdfParams        `protobuf:"bytes,13,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	Version DescriptorVersion `protobuf:"varint,14,opt,name=version,proto3,enum=filebank.DescriptorVersion" json:"version,omitempty"`
+	// key of the files of v2 banks
+	MasterKey *MasterKey `protobuf:"bytes,15,opt,name=master_key,json=masterKey,proto3" json:"master_key,omitempty"`
+	// encryption of the files and of the master key. Files of v1 banks and files sealed in a single call use AES-128-GCM
+	CipherSuite CipherSuite `protobuf:"varint,16,opt,name=cipher_suite,json=cipherSuite,proto3,enum=filebank.CipherSuite" json:"cipher_suite,omitempty"`
+	// compression of new files, skipped for files that do not compress
+	Compression Compression `protobuf:"varint,17,opt,name=compression,proto3,enum=filebank.Compression" json:"compression,omitempty"`
+	// handover from the previous key of the bank, when it was rotated
+	Handover *BankHandover `protobuf:"bytes,18,opt,name=handover,proto3" json:"handover,omitempty"`
+	// signature of the handover by the server, over SignBankHandoverServer
+	HandoverServerSignature []byte `protobuf:"bytes,19,opt,name=handover_server_signature,json=handoverServerSignature,proto3" json:"handover_server_signature,omitempty"`
+	// recipients the bank is shared with
+	Recipients []*BankRecipient `protobuf:"bytes,20,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	// public key of a bank shared with the user, which has no priv_key
+	BankPubKey []byte `protobuf:"bytes,21,opt,name=bank_pub_key,json=bankPubKey,proto3" json:"bank_pub_key,omitempty"`
+}
+
+func (x *ClientBankDescriptor) Reset() {
+	*x = ClientBankDescriptor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientBankDescriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientBankDescriptor) ProtoMessage() {}
+
+func (x *ClientBankDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientBankDescriptor.ProtoReflect.Descriptor instead.
+func (*ClientBankDescriptor) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ClientBankDescriptor) GetPrivKey() []byte {
+	if x != nil {
+		return x.PrivKey
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetNbfiles() int32 {
+	if x != nil {
+		return x.Nbfiles
+	}
+	return 0
+}
+
+func (x *ClientBankDescriptor) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetFileDescriptors() []*FileDescriptor {
+	if x != nil {
+		return x.FileDescriptors
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetTreeMode() TreeMode {
+	if x != nil {
+		return x.TreeMode
+	}
+	return TreeMode_SORTED_TREE
+}
+
+func (x *ClientBankDescriptor) GetTreeVersion() TreeVersion {
+	if x != nil {
+		return x.TreeVersion
+	}
+	return TreeVersion_TREE_V1
+}
+
+func (x *ClientBankDescriptor) GetHashAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.HashAlgorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+func (x *ClientBankDescriptor) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *ClientBankDescriptor) GetKdf() *KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetVersion() DescriptorVersion {
+	if x != nil {
+		return x.Version
+	}
+	return DescriptorVersion_DESCRIPTOR_V1
+}
+
+func (x *ClientBankDescriptor) GetMasterKey() *MasterKey {
+	if x != nil {
+		return x.MasterKey
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetCipherSuite() CipherSuite {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return CipherSuite_AES_128_GCM
+}
+
+func (x *ClientBankDescriptor) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_NO_COMPRESSION
+}
+
+func (x *ClientBankDescriptor) GetHandover() *BankHandover {
+	if x != nil {
+		return x.Handover
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetHandoverServerSignature() []byte {
+	if x != nil {
+		return x.HandoverServerSignature
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetRecipients() []*BankRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *ClientBankDescriptor) GetBankPubKey() []byte {
+	if x != nil {
+		return x.BankPubKey
+	}
+	return nil
+}
+
+// random master key of a bank, encrypted with the key derived from the passphrase and salt
+// master key of a bank encrypted with HPKE to the identity key of a recipient, bound to the key hash of the bank
+type BankRecipient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identity   []byte `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Enc        []byte `protobuf:"bytes,2,opt,name=enc,proto3" json:"enc,omitempty"`
+	WrappedKey []byte `protobuf:"bytes,3,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+}
+
+func (x *BankRecipient) Reset() {
+	*x = BankRecipient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankRecipient) ProtoMessage() {}
+
+func (x *BankRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankRecipient.ProtoReflect.Descriptor instead.
+func (*BankRecipient) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BankRecipient) GetIdentity() []byte {
+	if x != nil {
+		return x.Identity
+	}
+	return nil
+}
+
+func (x *BankRecipient) GetEnc() []byte {
+	if x != nil {
+		return x.Enc
+	}
+	return nil
+}
+
+func (x *BankRecipient) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+type MasterKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kdf        *KdfParams `protobuf:"bytes,1,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	Salt       []byte     `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	WrappedKey []byte     `protobuf:"bytes,3,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+}
+
+func (x *MasterKey) Reset() {
+	*x = MasterKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MasterKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MasterKey) ProtoMessage() {}
+
+func (x *MasterKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MasterKey.ProtoReflect.Descriptor instead.
+func (*MasterKey) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MasterKey) GetKdf() *KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *MasterKey) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *MasterKey) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+// memory is in KiB, time, memory and threads are only used by argon2id
+type KdfParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kdf     Kdf    `protobuf:"varint,1,opt,name=kdf,proto3,enum=filebank.Kdf" json:"kdf,omitempty"`
+	Time    uint32 `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Memory  uint32 `protobuf:"varint,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	Threads uint32 `protobuf:"varint,4,opt,name=threads,proto3" json:"threads,omitempty"`
+}
+
+func (x *KdfParams) Reset() {
+	*x = KdfParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KdfParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KdfParams) ProtoMessage() {}
+
+func (x *KdfParams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KdfParams.ProtoReflect.Descriptor instead.
+func (*KdfParams) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KdfParams) GetKdf() Kdf {
+	if x != nil {
+		return x.Kdf
+	}
+	return Kdf_PBKDF2_SHA1
+}
+
+func (x *KdfParams) GetTime() uint32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *KdfParams) GetMemory() uint32 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *KdfParams) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+type FileDescriptor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  int32  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Salt []byte `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+	Iv   []byte `protobuf:"bytes,4,opt,name=iv,proto3" json:"iv,omitempty"`
+	Size int64  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	// hash of the encrypted file, or root of its chunk tree, as committed by its merkle leaf
+	ContentHash []byte `protobuf:"bytes,6,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	// derivation of the key of a file of a v1 bank from the passphrase and salt, PBKDF2 when not set
+	Kdf *KdfParams `protobuf:"bytes,7,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	// key of the file encrypted with the derived key, when keys were derived again by 'bank rekdf'
+	WrappedKey []byte `protobuf:"bytes,8,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	// size of the plaintext segments of files encrypted as a STREAM, whose iv is the nonce prefix.
+	// Files are sealed with a single AES-GCM call when not set
+	SegmentSize int32 `protobuf:"varint,9,opt,name=segment_size,json=segmentSize,proto3" json:"segment_size,omitempty"`
+	// STREAM segments are authenticated with the hash of the bank public key, the file number and the descriptor
+	// version as associated data. Not set on files encrypted before
+	Bound bool `protobuf:"varint,10,opt,name=bound,proto3" json:"bound,omitempty"`
+	// compression of the plaintext before encryption
+	Compression Compression `protobuf:"varint,11,opt,name=compression,proto3,enum=filebank.Compression" json:"compression,omitempty"`
+}
+
+func (x *FileDescriptor) Reset() {
+	*x = FileDescriptor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileDescriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDescriptor) ProtoMessage() {}
+
+func (x *FileDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDescriptor.ProtoReflect.Descriptor instead.
+func (*FileDescriptor) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FileDescriptor) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *FileDescriptor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileDescriptor) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *FileDescriptor) GetIv() []byte {
+	if x != nil {
+		return x.Iv
+	}
+	return nil
+}
+
+func (x *FileDescriptor) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileDescriptor) GetContentHash() []byte {
+	if x != nil {
+		return x.ContentHash
+	}
+	return nil
+}
+
+func (x *FileDescriptor) GetKdf() *KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *FileDescriptor) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *FileDescriptor) GetSegmentSize() int32 {
+	if x != nil {
+		return x.SegmentSize
+	}
+	return 0
+}
+
+func (x *FileDescriptor) GetBound() bool {
+	if x != nil {
+		return x.Bound
+	}
+	return false
+}
+
+func (x *FileDescriptor) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_NO_COMPRESSION
+}
+
+// client bank descriptor without its private key and master key, stored on the server to restore the bank from its
+// recovery phrase. It is encrypted with the key derived from the master key and salt, and bound to the hash of the
+// bank public key
+type EncryptedManifest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CipherSuite CipherSuite `protobuf:"varint,1,opt,name=cipher_suite,json=cipherSuite,proto3,enum=filebank.CipherSuite" json:"cipher_suite,omitempty"`
+	Salt        []byte      `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Ciphertext  []byte      `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+}
+
+func (x *EncryptedManifest) Reset() {
+	*x = EncryptedManifest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncryptedManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptedManifest) ProtoMessage() {}
+
+func (x *EncryptedManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptedManifest.ProtoReflect.Descriptor instead.
+func (*EncryptedManifest) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EncryptedManifest) GetCipherSuite() CipherSuite {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return CipherSuite_AES_128_GCM
+}
+
+func (x *EncryptedManifest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *EncryptedManifest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+// share of the private key and master key of a bank, encrypted with HPKE to the identity key of a user
+type BankShare struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// serialized BankShareHeader, authenticated as associated data of the share
+	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// encapsulated key of HPKE
+	Enc        []byte `protobuf:"bytes,2,opt,name=enc,proto3" json:"enc,omitempty"`
+	Ciphertext []byte `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+}
+
+func (x *BankShare) Reset() {
+	*x = BankShare{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankShare) ProtoMessage() {}
+
+func (x *BankShare) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankShare.ProtoReflect.Descriptor instead.
+func (*BankShare) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BankShare) GetHeader() []byte {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *BankShare) GetEnc() []byte {
+	if x != nil {
+		return x.Enc
+	}
+	return nil
+}
+
+func (x *BankShare) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+type BankShareHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hash of the exported public key of the bank
+	BankKeyHash []byte `protobuf:"bytes,1,opt,name=bank_key_hash,json=bankKeyHash,proto3" json:"bank_key_hash,omitempty"`
+	Threshold   int32  `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Nbshares    int32  `protobuf:"varint,3,opt,name=nbshares,proto3" json:"nbshares,omitempty"`
+	// ed25519 identity key of the user the share is encrypted to
+	Recipient []byte `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+}
+
+func (x *BankShareHeader) Reset() {
+	*x = BankShareHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_storage_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankShareHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankShareHeader) ProtoMessage() {}
+
+func (x *BankShareHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankShareHeader.ProtoReflect.Descriptor instead.
+func (*BankShareHeader) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BankShareHeader) GetBankKeyHash() []byte {
+	if x != nil {
+		return x.BankKeyHash
+	}
+	return nil
+}
+
+func (x *BankShareHeader) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *BankShareHeader) GetNbshares() int32 {
+	if x != nil {
+		return x.Nbshares
+	}
+	return 0
+}
+
+func (x *BankShareHeader) GetRecipient() []byte {
+	if x != nil {
+		return x.Recipient
+	}
+	return nil
+}
+
+type ServerDescriptor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Host   string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
 }
 
-func (x *ServerBankDescriptor) Reset() {
-	*x = ServerBankDescriptor{}
+func (x *ServerDescriptor) Reset() {
+	*x = ServerDescriptor{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_storage_proto_msgTypes[0]
+		mi := &file_proto_storage_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServerBankDescriptor) String() string {
+func (x *ServerDescriptor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerBankDescriptor) ProtoMessage() {}
+func (*ServerDescriptor) ProtoMessage() {}
 
-func (x *ServerBankDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_storage_proto_msgTypes[0]
+func (x *ServerDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,60 +1366,72 @@ func (x *ServerBankDescriptor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerBankDescriptor.ProtoReflect.Descriptor instead.
-func (*ServerBankDescriptor) Descriptor() ([]byte, []int) {
-	return file_proto_storage_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use ServerDescriptor.ProtoReflect.Descriptor instead.
+func (*ServerDescriptor) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ServerBankDescriptor) GetPubKey() []byte {
+func (x *ServerDescriptor) GetPubKey() []byte {
 	if x != nil {
 		return x.PubKey
 	}
 	return nil
 }
 
-func (x *ServerBankDescriptor) GetNbfiles() int32 {
-	if x != nil {
-		return x.Nbfiles
-	}
-	return 0
-}
-
-func (x *ServerBankDescriptor) GetMerkleHashes() [][]byte {
+func (x *ServerDescriptor) GetHost() string {
 	if x != nil {
-		return x.MerkleHashes
+		return x.Host
 	}
-	return nil
+	return ""
 }
 
-type ClientBankDescriptor struct {
+// merkle proof of a downloaded file, saved for offline verification
+type SavedProof struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PrivKey         []byte            `protobuf:"bytes,5,opt,name=priv_key,json=privKey,proto3" json:"priv_key,omitempty"`
-	Nbfiles         int32             `protobuf:"varint,6,opt,name=nbfiles,proto3" json:"nbfiles,omitempty"`
-	MerkleRoot      []byte            `protobuf:"bytes,7,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
-	FileDescriptors []*FileDescriptor `protobuf:"bytes,8,rep,name=file_descriptors,json=fileDescriptors,proto3" json:"file_descriptors,omitempty"`
+	TreeMode      TreeMode      `protobuf:"varint,1,opt,name=tree_mode,json=treeMode,proto3,enum=filebank.TreeMode" json:"tree_mode,omitempty"`
+	TreeVersion   TreeVersion   `protobuf:"varint,2,opt,name=tree_version,json=treeVersion,proto3,enum=filebank.TreeVersion" json:"tree_version,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,3,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=filebank.HashAlgorithm" json:"hash_algorithm,omitempty"`
+	ChunkSize     int32         `protobuf:"varint,4,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	MerkleRoot    []byte        `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Hashes        [][]byte      `protobuf:"bytes,6,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	LeafIndex     int32         `protobuf:"varint,7,opt,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	TreeSize      int32         `protobuf:"varint,8,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	FileNumber    int32         `protobuf:"varint,9,opt,name=file_number,json=fileNumber,proto3" json:"file_number,omitempty"`
+	FileName      string        `protobuf:"bytes,10,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// encryption parameters of the file, needed to verify its plaintext
+	Salt              []byte            `protobuf:"bytes,11,opt,name=salt,proto3" json:"salt,omitempty"`
+	Iv                []byte            `protobuf:"bytes,12,opt,name=iv,proto3" json:"iv,omitempty"`
+	Kdf               *KdfParams        `protobuf:"bytes,13,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	WrappedKey        []byte            `protobuf:"bytes,14,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	DescriptorVersion DescriptorVersion `protobuf:"varint,15,opt,name=descriptor_version,json=descriptorVersion,proto3,enum=filebank.DescriptorVersion" json:"descriptor_version,omitempty"`
+	MasterKey         *MasterKey        `protobuf:"bytes,16,opt,name=master_key,json=masterKey,proto3" json:"master_key,omitempty"`
+	SegmentSize       int32             `protobuf:"varint,17,opt,name=segment_size,json=segmentSize,proto3" json:"segment_size,omitempty"`
+	BankKeyHash       []byte            `protobuf:"bytes,18,opt,name=bank_key_hash,json=bankKeyHash,proto3" json:"bank_key_hash,omitempty"`
+	Bound             bool              `protobuf:"varint,19,opt,name=bound,proto3" json:"bound,omitempty"`
+	CipherSuite       CipherSuite       `protobuf:"varint,20,opt,name=cipher_suite,json=cipherSuite,proto3,enum=filebank.CipherSuite" json:"cipher_suite,omitempty"`
+	Compression       Compression       `protobuf:"varint,21,opt,name=compression,proto3,enum=filebank.Compression" json:"compression,omitempty"`
 }
 
-func (x *ClientBankDescriptor) Reset() {
-	*x = ClientBankDescriptor{}
+func (x *SavedProof) Reset() {
+	*x = SavedProof{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_storage_proto_msgTypes[1]
+		mi := &file_proto_storage_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ClientBankDescriptor) String() string {
+func (x *SavedProof) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientBankDescriptor) ProtoMessage() {}
+func (*SavedProof) ProtoMessage() {}
 
-func (x *ClientBankDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_storage_proto_msgTypes[1]
+func (x *SavedProof) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_storage_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -121,163 +1442,156 @@ func (x *ClientBankDescriptor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientBankDescriptor.ProtoReflect.Descriptor instead.
-func (*ClientBankDescriptor) Descriptor() ([]byte, []int) {
-	return file_proto_storage_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use SavedProof.ProtoReflect.Descriptor instead.
+func (*SavedProof) Descriptor() ([]byte, []int) {
+	return file_proto_storage_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ClientBankDescriptor) GetPrivKey() []byte {
+func (x *SavedProof) GetTreeMode() TreeMode {
 	if x != nil {
-		return x.PrivKey
+		return x.TreeMode
 	}
-	return nil
+	return TreeMode_SORTED_TREE
 }
 
-func (x *ClientBankDescriptor) GetNbfiles() int32 {
+func (x *SavedProof) GetTreeVersion() TreeVersion {
 	if x != nil {
-		return x.Nbfiles
+		return x.TreeVersion
 	}
-	return 0
+	return TreeVersion_TREE_V1
 }
 
-func (x *ClientBankDescriptor) GetMerkleRoot() []byte {
+func (x *SavedProof) GetHashAlgorithm() HashAlgorithm {
 	if x != nil {
-		return x.MerkleRoot
+		return x.HashAlgorithm
 	}
-	return nil
+	return HashAlgorithm_SHA256
 }
 
-func (x *ClientBankDescriptor) GetFileDescriptors() []*FileDescriptor {
+func (x *SavedProof) GetChunkSize() int32 {
 	if x != nil {
-		return x.FileDescriptors
+		return x.ChunkSize
 	}
-	return nil
-}
-
-type FileDescriptor struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Seq  int32  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Salt []byte `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
-	Iv   []byte `protobuf:"bytes,4,opt,name=iv,proto3" json:"iv,omitempty"`
+	return 0
 }
 
-func (x *FileDescriptor) Reset() {
-	*x = FileDescriptor{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_storage_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SavedProof) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
 	}
+	return nil
 }
 
-func (x *FileDescriptor) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SavedProof) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
 }
 
-func (*FileDescriptor) ProtoMessage() {}
-
-func (x *FileDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_storage_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *SavedProof) GetLeafIndex() int32 {
+	if x != nil {
+		return x.LeafIndex
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use FileDescriptor.ProtoReflect.Descriptor instead.
-func (*FileDescriptor) Descriptor() ([]byte, []int) {
-	return file_proto_storage_proto_rawDescGZIP(), []int{2}
+func (x *SavedProof) GetTreeSize() int32 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
 }
 
-func (x *FileDescriptor) GetSeq() int32 {
+func (x *SavedProof) GetFileNumber() int32 {
 	if x != nil {
-		return x.Seq
+		return x.FileNumber
 	}
 	return 0
 }
 
-func (x *FileDescriptor) GetName() string {
+func (x *SavedProof) GetFileName() string {
 	if x != nil {
-		return x.Name
+		return x.FileName
 	}
 	return ""
 }
 
-func (x *FileDescriptor) GetSalt() []byte {
+func (x *SavedProof) GetSalt() []byte {
 	if x != nil {
 		return x.Salt
 	}
 	return nil
 }
 
-func (x *FileDescriptor) GetIv() []byte {
+func (x *SavedProof) GetIv() []byte {
 	if x != nil {
 		return x.Iv
 	}
 	return nil
 }
 
-type ServerDescriptor struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *SavedProof) GetKdf() *KdfParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
 
-	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
-	Host   string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+func (x *SavedProof) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
 }
 
-func (x *ServerDescriptor) Reset() {
-	*x = ServerDescriptor{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_storage_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SavedProof) GetDescriptorVersion() DescriptorVersion {
+	if x != nil {
+		return x.DescriptorVersion
 	}
+	return DescriptorVersion_DESCRIPTOR_V1
 }
 
-func (x *ServerDescriptor) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SavedProof) GetMasterKey() *MasterKey {
+	if x != nil {
+		return x.MasterKey
+	}
+	return nil
 }
 
-func (*ServerDescriptor) ProtoMessage() {}
+func (x *SavedProof) GetSegmentSize() int32 {
+	if x != nil {
+		return x.SegmentSize
+	}
+	return 0
+}
 
-func (x *ServerDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_storage_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *SavedProof) GetBankKeyHash() []byte {
+	if x != nil {
+		return x.BankKeyHash
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ServerDescriptor.ProtoReflect.Descriptor instead.
-func (*ServerDescriptor) Descriptor() ([]byte, []int) {
-	return file_proto_storage_proto_rawDescGZIP(), []int{3}
+func (x *SavedProof) GetBound() bool {
+	if x != nil {
+		return x.Bound
+	}
+	return false
 }
 
-func (x *ServerDescriptor) GetPubKey() []byte {
+func (x *SavedProof) GetCipherSuite() CipherSuite {
 	if x != nil {
-		return x.PubKey
+		return x.CipherSuite
 	}
-	return nil
+	return CipherSuite_AES_128_GCM
 }
 
-func (x *ServerDescriptor) GetHost() string {
+func (x *SavedProof) GetCompression() Compression {
 	if x != nil {
-		return x.Host
+		return x.Compression
 	}
-	return ""
+	return Compression_NO_COMPRESSION
 }
 
 var File_proto_storage_proto protoreflect.FileDescriptor
@@ -285,14 +1599,59 @@ var File_proto_storage_proto protoreflect.FileDescriptor
 var file_proto_storage_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x22,
-	0x6e, 0x0a, 0x14, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79,
-	0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65,
-	0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0c, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22,
-	0xb1, 0x01, 0x0a, 0x14, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x65,
+	0x98, 0x04, 0x0a, 0x14, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73,
+	0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x38, 0x0a, 0x0c, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b,
+	0x74, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x0e, 0x68,
+	0x61, 0x73, 0x68, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x48,
+	0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x0d, 0x68, 0x61,
+	0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x72,
+	0x65, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x74,
+	0x72, 0x65, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x6f,
+	0x76, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65,
+	0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65,
+	0x72, 0x52, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x0a, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74,
+	0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6d, 0x61,
+	0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x6d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xa2, 0x02, 0x0a, 0x0c, 0x42,
+	0x61, 0x6e, 0x6b, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x12, 0x1e, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x50, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x50, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x6f, 0x6c, 0x64, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6f, 0x6c, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6e, 0x65, 0x77, 0x53, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c,
+	0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x22,
+	0xcb, 0x06, 0x0a, 0x14, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x44, 0x65,
 	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x76,
 	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76,
 	0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x62, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x06,
@@ -303,18 +1662,196 @@ var file_proto_storage_proto_rawDesc = []byte{
 	0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
 	0x61, 0x6e, 0x6b, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
 	0x6f, 0x72, 0x52, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x6f, 0x72, 0x73, 0x22, 0x5a, 0x0a, 0x0e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73,
-	0x61, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x76, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x76, 0x22,
-	0x3f, 0x0a, 0x10, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04,
-	0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74,
-	0x42, 0x09, 0x5a, 0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x6f, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c,
+	0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x74, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e,
+	0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52,
+	0x0d, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x25, 0x0a,
+	0x03, 0x6b, 0x64, 0x66, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x69, 0x6c,
+	0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4b, 0x64, 0x66, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52,
+	0x03, 0x6b, 0x64, 0x66, 0x12, 0x35, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x0a, 0x6d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x4b, 0x65, 0x79, 0x52, 0x09, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12,
+	0x38, 0x0a, 0x0c, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x5f, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b,
+	0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
+	0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x42, 0x61, 0x6e, 0x6b, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x08, 0x68, 0x61,
+	0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x3a, 0x0a, 0x19, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x76,
+	0x65, 0x72, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x17, 0x68, 0x61, 0x6e, 0x64, 0x6f,
+	0x76, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x52,
+	0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x62, 0x61, 0x6e, 0x6b, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x5e, 0x0a,
+	0x0d, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e,
+	0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x65, 0x6e, 0x63, 0x12, 0x1f, 0x0a, 0x0b,
+	0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x22, 0x67, 0x0a,
+	0x09, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x03, 0x6b, 0x64,
+	0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61,
+	0x6e, 0x6b, 0x2e, 0x4b, 0x64, 0x66, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x03, 0x6b, 0x64,
+	0x66, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x73, 0x61, 0x6c, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x77, 0x72, 0x61, 0x70,
+	0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x22, 0x72, 0x0a, 0x09, 0x4b, 0x64, 0x66, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x03, 0x6b, 0x64, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4b, 0x64, 0x66, 0x52,
+	0x03, 0x6b, 0x64, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x22, 0xcb, 0x02, 0x0a, 0x0e, 0x46,
+	0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x76, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x76, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x25,
+	0x0a, 0x03, 0x6b, 0x64, 0x66, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x69,
+	0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4b, 0x64, 0x66, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x52, 0x03, 0x6b, 0x64, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x77, 0x72, 0x61, 0x70,
+	0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x73, 0x65,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6f, 0x75,
+	0x6e, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12,
+	0x37, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x81, 0x01, 0x0a, 0x11, 0x45, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x38,
+	0x0a, 0x0c, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x5f, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e,
+	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x22, 0x55, 0x0a, 0x09,
+	0x42, 0x61, 0x6e, 0x6b, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x65, 0x6e, 0x63, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74,
+	0x65, 0x78, 0x74, 0x22, 0x8d, 0x01, 0x0a, 0x0f, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0d, 0x62, 0x61, 0x6e, 0x6b, 0x5f,
+	0x6b, 0x65, 0x79, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x62, 0x61, 0x6e, 0x6b, 0x4b, 0x65, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x62, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6e, 0x62, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x22, 0x3f, 0x0a, 0x10, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x44, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x68, 0x6f, 0x73, 0x74, 0x22, 0xc5, 0x06, 0x0a, 0x0a, 0x53, 0x61, 0x76, 0x65, 0x64, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c,
+	0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x74, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e,
+	0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e,
+	0x6b, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52,
+	0x0d, 0x68, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06,
+	0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x65, 0x61, 0x66,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x73, 0x61, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x76, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x69, 0x76, 0x12, 0x25, 0x0a, 0x03, 0x6b, 0x64, 0x66, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4b, 0x64, 0x66,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x03, 0x6b, 0x64, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x77,
+	0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x4a, 0x0a, 0x12,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
+	0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x0a, 0x6d, 0x61, 0x73, 0x74,
+	0x65, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66,
+	0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x4b, 0x65,
+	0x79, 0x52, 0x09, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0b, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x22, 0x0a, 0x0d, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x62, 0x61, 0x6e, 0x6b, 0x4b, 0x65, 0x79, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x38, 0x0a, 0x0c, 0x63, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x5f, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75,
+	0x69, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x62,
+	0x61, 0x6e, 0x6b, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2a, 0x41, 0x0a, 0x08,
+	0x54, 0x72, 0x65, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x4f, 0x52, 0x54,
+	0x45, 0x44, 0x5f, 0x54, 0x52, 0x45, 0x45, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x49, 0x4e, 0x44,
+	0x45, 0x58, 0x45, 0x44, 0x5f, 0x54, 0x52, 0x45, 0x45, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4d,
+	0x4f, 0x55, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x02, 0x2a,
+	0x3e, 0x0a, 0x0b, 0x54, 0x72, 0x65, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0b,
+	0x0a, 0x07, 0x54, 0x52, 0x45, 0x45, 0x5f, 0x56, 0x31, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x54,
+	0x52, 0x45, 0x45, 0x5f, 0x56, 0x32, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x52, 0x45, 0x45,
+	0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x5a, 0x45, 0x50, 0x50, 0x45, 0x4c, 0x49, 0x4e, 0x10, 0x02, 0x2a,
+	0x4b, 0x0a, 0x0d, 0x48, 0x61, 0x73, 0x68, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d,
+	0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a,
+	0x53, 0x48, 0x41, 0x35, 0x31, 0x32, 0x5f, 0x32, 0x35, 0x36, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b,
+	0x42, 0x4c, 0x41, 0x4b, 0x45, 0x32, 0x42, 0x5f, 0x32, 0x35, 0x36, 0x10, 0x02, 0x12, 0x0d, 0x0a,
+	0x09, 0x4b, 0x45, 0x43, 0x43, 0x41, 0x4b, 0x32, 0x35, 0x36, 0x10, 0x03, 0x2a, 0x35, 0x0a, 0x0b,
+	0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x0e, 0x4e,
+	0x4f, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12,
+	0x08, 0x0a, 0x04, 0x47, 0x5a, 0x49, 0x50, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x5a, 0x53, 0x54,
+	0x44, 0x10, 0x02, 0x2a, 0x47, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69,
+	0x74, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x45, 0x53, 0x5f, 0x31, 0x32, 0x38, 0x5f, 0x47, 0x43,
+	0x4d, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x45, 0x53, 0x5f, 0x32, 0x35, 0x36, 0x5f, 0x47,
+	0x43, 0x4d, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x58, 0x43, 0x48, 0x41, 0x43, 0x48, 0x41, 0x32,
+	0x30, 0x5f, 0x50, 0x4f, 0x4c, 0x59, 0x31, 0x33, 0x30, 0x35, 0x10, 0x02, 0x2a, 0x39, 0x0a, 0x11,
+	0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x50, 0x54, 0x4f, 0x52, 0x5f,
+	0x56, 0x31, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x50, 0x54,
+	0x4f, 0x52, 0x5f, 0x56, 0x32, 0x10, 0x01, 0x2a, 0x24, 0x0a, 0x03, 0x4b, 0x64, 0x66, 0x12, 0x0f,
+	0x0a, 0x0b, 0x50, 0x42, 0x4b, 0x44, 0x46, 0x32, 0x5f, 0x53, 0x48, 0x41, 0x31, 0x10, 0x00, 0x12,
+	0x0c, 0x0a, 0x08, 0x41, 0x52, 0x47, 0x4f, 0x4e, 0x32, 0x49, 0x44, 0x10, 0x01, 0x42, 0x09, 0x5a,
+	0x07, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -329,20 +1866,65 @@ func file_proto_storage_proto_rawDescGZIP() []byte {
 	return file_proto_storage_proto_rawDescData
 }
 
-var file_proto_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_storage_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_proto_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_storage_proto_goTypes = []interface{}{
-	(*ServerBankDescriptor)(nil), // 0: filebank.ServerBankDescriptor
-	(*ClientBankDescriptor)(nil), // 1: filebank.ClientBankDescriptor
-	(*FileDescriptor)(nil),       // 2: filebank.FileDescriptor
-	(*ServerDescriptor)(nil),     // 3: filebank.ServerDescriptor
+	(TreeMode)(0),                // 0: filebank.TreeMode
+	(TreeVersion)(0),             // 1: filebank.TreeVersion
+	(HashAlgorithm)(0),           // 2: filebank.HashAlgorithm
+	(Compression)(0),             // 3: filebank.Compression
+	(CipherSuite)(0),             // 4: filebank.CipherSuite
+	(DescriptorVersion)(0),       // 5: filebank.DescriptorVersion
+	(Kdf)(0),                     // 6: filebank.Kdf
+	(*ServerBankDescriptor)(nil), // 7: filebank.ServerBankDescriptor
+	(*BankHandover)(nil),         // 8: filebank.BankHandover
+	(*ClientBankDescriptor)(nil), // 9: filebank.ClientBankDescriptor
+	(*BankRecipient)(nil),        // 10: filebank.BankRecipient
+	(*MasterKey)(nil),            // 11: filebank.MasterKey
+	(*KdfParams)(nil),            // 12: filebank.KdfParams
+	(*FileDescriptor)(nil),       // 13: filebank.FileDescriptor
+	(*EncryptedManifest)(nil),    // 14: filebank.EncryptedManifest
+	(*BankShare)(nil),            // 15: filebank.BankShare
+	(*BankShareHeader)(nil),      // 16: filebank.BankShareHeader
+	(*ServerDescriptor)(nil),     // 17: filebank.ServerDescriptor
+	(*SavedProof)(nil),           // 18: filebank.SavedProof
 }
 var file_proto_storage_proto_depIdxs = []int32{
-	2, // 0: filebank.ClientBankDescriptor.file_descriptors:type_name -> filebank.FileDescriptor
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: filebank.ServerBankDescriptor.tree_mode:type_name -> filebank.TreeMode
+	1,  // 1: filebank.ServerBankDescriptor.tree_version:type_name -> filebank.TreeVersion
+	2,  // 2: filebank.ServerBankDescriptor.hash_algorithm:type_name -> filebank.HashAlgorithm
+	8,  // 3: filebank.ServerBankDescriptor.handover:type_name -> filebank.BankHandover
+	10, // 4: filebank.ServerBankDescriptor.recipients:type_name -> filebank.BankRecipient
+	10, // 5: filebank.BankHandover.recipients:type_name -> filebank.BankRecipient
+	13, // 6: filebank.ClientBankDescriptor.file_descriptors:type_name -> filebank.FileDescriptor
+	0,  // 7: filebank.ClientBankDescriptor.tree_mode:type_name -> filebank.TreeMode
+	1,  // 8: filebank.ClientBankDescriptor.tree_version:type_name -> filebank.TreeVersion
+	2,  // 9: filebank.ClientBankDescriptor.hash_algorithm:type_name -> filebank.HashAlgorithm
+	12, // 10: filebank.ClientBankDescriptor.kdf:type_name -> filebank.KdfParams
+	5,  // 11: filebank.ClientBankDescriptor.version:type_name -> filebank.DescriptorVersion
+	11, // 12: filebank.ClientBankDescriptor.master_key:type_name -> filebank.MasterKey
+	4,  // 13: filebank.ClientBankDescriptor.cipher_suite:type_name -> filebank.CipherSuite
+	3,  // 14: filebank.ClientBankDescriptor.compression:type_name -> filebank.Compression
+	8,  // 15: filebank.ClientBankDescriptor.handover:type_name -> filebank.BankHandover
+	10, // 16: filebank.ClientBankDescriptor.recipients:type_name -> filebank.BankRecipient
+	12, // 17: filebank.MasterKey.kdf:type_name -> filebank.KdfParams
+	6,  // 18: filebank.KdfParams.kdf:type_name -> filebank.Kdf
+	12, // 19: filebank.FileDescriptor.kdf:type_name -> filebank.KdfParams
+	3,  // 20: filebank.FileDescriptor.compression:type_name -> filebank.Compression
+	4,  // 21: filebank.EncryptedManifest.cipher_suite:type_name -> filebank.CipherSuite
+	0,  // 22: filebank.SavedProof.tree_mode:type_name -> filebank.TreeMode
+	1,  // 23: filebank.SavedProof.tree_version:type_name -> filebank.TreeVersion
+	2,  // 24: filebank.SavedProof.hash_algorithm:type_name -> filebank.HashAlgorithm
+	12, // 25: filebank.SavedProof.kdf:type_name -> filebank.KdfParams
+	5,  // 26: filebank.SavedProof.descriptor_version:type_name -> filebank.DescriptorVersion
+	11, // 27: filebank.SavedProof.master_key:type_name -> filebank.MasterKey
+	4,  // 28: filebank.SavedProof.cipher_suite:type_name -> filebank.CipherSuite
+	3,  // 29: filebank.SavedProof.compression:type_name -> filebank.Compression
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_proto_storage_proto_init() }
@@ -364,7 +1946,7 @@ func file_proto_storage_proto_init() {
 			}
 		}
 		file_proto_storage_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ClientBankDescriptor); i {
+			switch v := v.(*BankHandover); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -376,7 +1958,7 @@ func file_proto_storage_proto_init() {
 			}
 		}
 		file_proto_storage_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileDescriptor); i {
+			switch v := v.(*ClientBankDescriptor); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -388,6 +1970,90 @@ func file_proto_storage_proto_init() {
 			}
 		}
 		file_proto_storage_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankRecipient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MasterKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KdfParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileDescriptor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncryptedManifest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankShare); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankShareHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_storage_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ServerDescriptor); i {
 			case 0:
 				return &v.state
@@ -399,19 +2065,32 @@ func file_proto_storage_proto_init() {
 				return nil
 			}
 		}
+		file_proto_storage_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SavedProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_storage_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      7,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_proto_storage_proto_goTypes,
 		DependencyIndexes: file_proto_storage_proto_depIdxs,
+		EnumInfos:         file_proto_storage_proto_enumTypes,
 		MessageInfos:      file_proto_storage_proto_msgTypes,
 	}.Build()
 	File_proto_storage_proto = out.File
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// serializes bank updates, so that two appends cannot both extend the same version of a bank
+var appendLock sync.Mutex
+
+func (c *fileBankServer) AppendFiles(stream pb.FileBankService_AppendFilesServer) error {
+	log.Printf("Received call: AppendFiles")
+	serverNonce, err := cr.Random12BytesNonce()
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.AppendFilesResponse{
+		Phase: &pb.AppendFilesResponse_Nonce{
+			Nonce: serverNonce,
+		},
+	}); err != nil {
+		return err
+	}
+
+	req1, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by client")
+	}
+	if err != nil {
+		return err
+	}
+
+	var appendReq *pb.AppendRequest
+	switch phase := req1.Phase.(type) {
+	case *pb.AppendFilesRequest_AppendReq:
+		appendReq = phase.AppendReq
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	// verify nonce matches
+	if !bytes.Equal(appendReq.Nonce, serverNonce) {
+		return errors.New("Invalid challenge response nonce")
+	}
+
+	// verify bank existence
+	if exists, err := verifyBankExistenceFromAddress(appendReq.PubKeyAddr); err != nil {
+		return err
+	} else if !exists {
+		return errors.New("Bank does not exist")
+	}
+	// read bank descriptor from disk
+	bankDescriptor, err := storage.Server_ReadBankDescriptor(bankhome, appendReq.PubKeyAddr)
+	if err != nil {
+		return err
+	}
+
+	// import bank public key
+	pubKey, err := cr.ImportPublicKey(bankDescriptor.PubKey)
+	if err != nil {
+		return err
+	}
+
+	// verify signature
+	if err := verifyAppendRequestSignature(appendReq, pubKey); err != nil {
+		return err
+	}
+
+	if !merkle.TreeMode(bankDescriptor.TreeMode).IsIndexed() {
+		return errors.New("Only banks using indexed trees or mountain ranges can be extended")
+	}
+	// client must extend the current version of the bank
+	if appendReq.OldNbfiles != bankDescriptor.Nbfiles {
+		return errors.New(fmt.Sprintf("Bank %v has %v files, client expected %v", appendReq.PubKeyAddr, bankDescriptor.Nbfiles, appendReq.OldNbfiles))
+	}
+	if appendReq.Nbfiles < 1 {
+		return errors.New("No file to append")
+	}
+
+	// read files, staged until the bank is updated. The nonce follows the last file
+	var req3 *pb.AppendFilesRequest
+	files := storage.NewFileMessageReader(func() (*pb.FileMessage, error) {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("Connexion closed by client")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if phase, ok := req.Phase.(*pb.AppendFilesRequest_File); ok {
+			return phase.File, nil
+		}
+		req3 = req
+		return nil, io.EOF
+	})
+	// numbering continues after the files already in the bank
+	received, err := receiveBankFiles(files, appendReq.PubKeyAddr, bankTreeParams(bankDescriptor), int(bankDescriptor.Nbfiles)+1, int(appendReq.Nbfiles))
+	if err != nil {
+		return err
+	}
+	defer removeStagedFiles(received)
+
+	// append files to existing merkle tree, all its leafs are read, or all its nodes for mountain ranges
+	oldTree, release, err := loadBankMerkleTree(appendReq.PubKeyAddr, bankDescriptor, true)
+	if err != nil {
+		return err
+	}
+	builder, err := merkle.NewMerkleTreeBuilderFromTree(*oldTree)
+	release()
+	if err != nil {
+		return err
+	}
+	for _, file := range received {
+		builder.AddContentHash(file.contentHash)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	merkleRoot := tree.GetMerkleRoot()
+	newNbfiles := bankDescriptor.Nbfiles + appendReq.Nbfiles
+
+	// prove that the new tree extends the old one, and that it contains the appended files
+	consistencyProof, err := tree.GenerateConsistencyProof(int(bankDescriptor.Nbfiles))
+	if err != nil {
+		return err
+	}
+	var fileNums []int
+	for i := bankDescriptor.Nbfiles + 1; i <= newNbfiles; i++ {
+		fileNums = append(fileNums, int(i))
+	}
+	filesProof, err := tree.GenerateMultiProofForFileNums(fileNums)
+	if err != nil {
+		return err
+	}
+
+	// read nonce
+	if req3 == nil {
+		return errors.New("Invalid message type")
+	}
+	var clientNonce []byte
+	switch phase := req3.Phase.(type) {
+	case *pb.AppendFilesRequest_Nonce:
+		clientNonce = phase.Nonce
+	default:
+		return errors.New("Invalid message type")
+	}
+
+	if err := writeAppendedFiles(appendReq.PubKeyAddr, bankDescriptor, received, tree); err != nil {
+		return err
+	}
+
+	// files stored correctly. Sign response
+	msgToSign := &pb.SignAppendedRootServer{
+		Nonce:      clientNonce,
+		MerkleRoot: merkleRoot[:],
+		Nbfiles:    newNbfiles,
+	}
+	sign, err := cr.SignMessage(msgToSign, ServerKeys.privKey)
+	if err != nil {
+		return err
+	}
+
+	resp := &pb.AppendFilesResponse{
+		Phase: &pb.AppendFilesResponse_AppendedRoot{
+			AppendedRoot: &pb.AppendedRoot{
+				Nonce:            clientNonce,
+				MerkleRoot:       merkleRoot[:],
+				Nbfiles:          newNbfiles,
+				Signature:        sign,
+				ConsistencyProof: linearizeHashes(consistencyProof.Hashes),
+				FilesProof:       linearizeHashes(filesProof.Hashes),
+			},
+		},
+	}
+
+	// only send when successfuly written to disk
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+func verifyAppendRequestSignature(req *pb.AppendRequest, pubKey ed25519.PublicKey) error {
+	clientSignedMsg := &pb.SignAppendRequestClient{
+		Nonce:      req.Nonce,
+		PubKeyAddr: req.PubKeyAddr,
+		OldNbfiles: req.OldNbfiles,
+		Nbfiles:    req.Nbfiles,
+	}
+	return cr.VerifySignature(clientSignedMsg, pubKey, req.Signature)
+}
+
+func writeAppendedFiles(pubKeyAddr string, bankDescriptor *pb.ServerBankDescriptor, received []*receivedFile, tree *merkle.MerkleTree) error {
+	appendLock.Lock()
+	defer appendLock.Unlock()
+
+	// bank must not have changed since the tree was extended
+	current, err := storage.Server_ReadBankDescriptor(bankhome, pubKeyAddr)
+	if err != nil {
+		return err
+	}
+	if current.Nbfiles != bankDescriptor.Nbfiles {
+		return errors.New("Bank was modified during append")
+	}
+
+	// files and tree file of a failed append are removed, so that it can be retried with the same file numbers
+	committed := false
+	treeWritten := false
+	firstSeq := int(bankDescriptor.Nbfiles) + 1
+	defer func() {
+		if committed {
+			return
+		}
+		if err := storage.Server_RemoveFilesFromBank(bankhome, pubKeyAddr, firstSeq, firstSeq+len(received)-1); err != nil {
+			log.Printf("Could not remove files of failed append to bank %v: %v", pubKeyAddr, err)
+		}
+		if treeWritten {
+			if err := storage.Server_RemoveTreeFile(bankhome, pubKeyAddr, bankDescriptor.Nbfiles+int32(len(received))); err != nil {
+				log.Printf("Could not remove tree file of failed append to bank %v: %v", pubKeyAddr, err)
+			}
+		}
+	}()
+
+	// write files before descriptor, so that the descriptor never references missing files
+	if err := commitReceivedFiles(received, pubKeyAddr, bankDescriptor, firstSeq); err != nil {
+		return err
+	}
+
+	// the current descriptor is kept as is, with its recipients, handover and signed manifest, which may have changed
+	// since bankDescriptor was read. Banks storing their hashes in the descriptor are moved to tree files
+	newDescriptor := proto.Clone(current).(*pb.ServerBankDescriptor)
+	newDescriptor.Nbfiles = bankDescriptor.Nbfiles + int32(len(received))
+	newDescriptor.TreeFile = true
+	newDescriptor.MerkleHashes = nil
+	// the new tree file does not replace the old one, which is still referenced until the descriptor is updated
+	if err := storage.Server_WriteTreeFile(bankhome, pubKeyAddr, newDescriptor, tree.Hashes); err != nil {
+		return err
+	}
+	treeWritten = true
+	if err := storage.Server_UpdateBankDescriptor(bankhome, newDescriptor); err != nil {
+		return err
+	}
+	committed = true
+	// the previous tree file is kept until the next append, for downloads that read the previous descriptor and have
+	// not opened its tree file yet. Older ones are removed
+	if err := storage.Server_RemoveTreeFilesBefore(bankhome, pubKeyAddr, bankDescriptor.Nbfiles); err != nil {
+		log.Printf("Could not remove old tree files of bank %v: %v", pubKeyAddr, err)
+	}
+	return nil
+}
+
+// linearize proof to fit in one message
+func linearizeHashes(hashes [][32]byte) []byte {
+	var linearProof []byte
+	for _, hash := range hashes {
+		linearProof = append(linearProof, hash[:]...)
+	}
+	return linearProof
+}
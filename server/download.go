@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
 
 	cr "github.com/oteffahi/merkle-filebank/cryptography"
 	"github.com/oteffahi/merkle-filebank/merkle"
@@ -55,8 +56,8 @@ func (c *fileBankServer) DownloadFiles(stream pb.FileBankService_DownloadFilesSe
 		return err
 	}
 
-	// import bank public key
-	pubKey, err := cr.ImportPublicKey(bankDescriptor.PubKey)
+	// import bank public key, or the identity key of the recipient that signed
+	pubKey, err := bankRequestSigner(bankDescriptor, req1.Signer)
 	if err != nil {
 		return err
 	}
@@ -66,27 +67,38 @@ func (c *fileBankServer) DownloadFiles(stream pb.FileBankService_DownloadFilesSe
 		return err
 	}
 
-	if req1.FileNum < 1 || req1.FileNum > bankDescriptor.Nbfiles {
-		return errors.New(fmt.Sprintf("No file identified by %v. Bank %v has files between 1-%v", req1.FileNum, req1.PubKeyAddr, bankDescriptor.Nbfiles))
+	// part of a file requested, answer with the chunks covering it
+	if req1.Range != nil {
+		return sendRangeAndProof(stream, req1, bankDescriptor)
 	}
 
-	// read file from disk
-	file, err := storage.Server_ReadFileFromBank(bankhome, req1.PubKeyAddr, int(req1.FileNum))
-	if err != nil {
-		return err
+	// several files requested, answer with a single multiproof
+	if len(req1.FileNums) > 0 {
+		return sendFilesAndMultiProof(stream, req1, bankDescriptor)
 	}
 
-	// convert bankDescriptor.MerkleHashes from slice of slices to slice of arrays
-	merkleHashes := [][32]byte{}
-	for _, hash := range bankDescriptor.MerkleHashes {
-		merkleHashes = append(merkleHashes, [32]byte(hash))
+	if req1.FileNum < 1 || req1.FileNum > bankDescriptor.Nbfiles {
+		return errors.New(fmt.Sprintf("No file identified by %v. Bank %v has files between 1-%v", req1.FileNum, req1.PubKeyAddr, bankDescriptor.Nbfiles))
 	}
+
 	// load merkle tree
-	merkleTree := merkle.MerkleTree{
-		Hashes: merkleHashes,
+	merkleTree, release, err := loadBankMerkleTree(req1.PubKeyAddr, bankDescriptor, false)
+	if err != nil {
+		return err
 	}
+	defer release()
 	// generate proof
-	merkleProof, err := merkleTree.GenerateProofForFile(file)
+	var merkleProof *merkle.MerkleProof
+	if merkleTree.Mode.IsIndexed() {
+		merkleProof, err = merkleTree.GenerateProofForFileNum(int(req1.FileNum))
+	} else {
+		var contentHash [32]byte
+		contentHash, err = bankFileContentHash(merkleTree, req1.PubKeyAddr, int(req1.FileNum))
+		if err != nil {
+			return err
+		}
+		merkleProof, err = merkleTree.GenerateProofForContentHash(contentHash)
+	}
 	if err != nil {
 		return err
 	}
@@ -97,20 +109,20 @@ func (c *fileBankServer) DownloadFiles(stream pb.FileBankService_DownloadFilesSe
 		linearProof = append(linearProof, hash[:]...)
 	}
 
-	// send file and proof
+	// send proof, then the file
 	resp := &pb.DownloadFilesResponse{
 		Phase: &pb.DownloadFilesResponse_Fp{
 			Fp: &pb.FileAndProof{
-				Proof: linearProof,
-				File:  file,
+				Proof:     linearProof,
+				LeafIndex: int32(merkleProof.Index),
+				TreeSize:  int32(merkleProof.TreeSize),
 			},
 		},
 	}
 	if err := stream.Send(resp); err != nil {
 		return err
 	}
-
-	return nil
+	return sendBankFile(stream, req1.PubKeyAddr, int(req1.FileNum))
 }
 
 func verifyDownloadRequestSignature(req *pb.DownloadFilesRequest, pubKey ed25519.PublicKey) error {
@@ -118,10 +130,277 @@ func verifyDownloadRequestSignature(req *pb.DownloadFilesRequest, pubKey ed25519
 		Nonce:      req.Nonce,
 		PubKeyAddr: req.PubKeyAddr,
 		FileNum:    req.FileNum,
+		FileNums:   req.FileNums,
+		Signer:     req.Signer,
+	}
+	if req.Range != nil {
+		clientSignedMsg.RangeOffset = req.Range.Offset
+		clientSignedMsg.RangeLength = req.Range.Length
 	}
 	return cr.VerifySignature(clientSignedMsg, pubKey, req.Signature)
 }
 
+func sendFilesAndMultiProof(stream pb.FileBankService_DownloadFilesServer, req *pb.DownloadFilesRequest, bankDescriptor *pb.ServerBankDescriptor) error {
+	requested := make(map[int32]bool)
+	for _, fileNum := range req.FileNums {
+		if fileNum < 1 || fileNum > bankDescriptor.Nbfiles {
+			return errors.New(fmt.Sprintf("No file identified by %v. Bank %v has files between 1-%v", fileNum, req.PubKeyAddr, bankDescriptor.Nbfiles))
+		}
+		if requested[fileNum] {
+			return errors.New(fmt.Sprintf("File %v requested more than once", fileNum))
+		}
+		requested[fileNum] = true
+	}
+
+	// load merkle tree
+	merkleTree, release, err := loadBankMerkleTree(req.PubKeyAddr, bankDescriptor, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	// generate proof
+	var multiProof *merkle.MerkleMultiProof
+	if merkleTree.Mode.IsIndexed() {
+		var fileNums []int
+		for _, fileNum := range req.FileNums {
+			fileNums = append(fileNums, int(fileNum))
+		}
+		multiProof, err = merkleTree.GenerateMultiProofForFileNums(fileNums)
+	} else {
+		var contentHashes [][32]byte
+		for _, fileNum := range req.FileNums {
+			contentHash, err := bankFileContentHash(merkleTree, req.PubKeyAddr, int(fileNum))
+			if err != nil {
+				return err
+			}
+			contentHashes = append(contentHashes, contentHash)
+		}
+		multiProof, err = merkleTree.GenerateMultiProofForContentHashes(contentHashes)
+	}
+	if err != nil {
+		return err
+	}
+	var leafIndexes []int32
+	for _, index := range multiProof.Indexes {
+		leafIndexes = append(leafIndexes, int32(index))
+	}
+
+	// linearize proof to fit in one message
+	var linearProof []byte
+	for _, hash := range multiProof.Hashes {
+		linearProof = append(linearProof, hash[:]...)
+	}
+
+	// send proof, then the files in requested order
+	resp := &pb.DownloadFilesResponse{
+		Phase: &pb.DownloadFilesResponse_Fmp{
+			Fmp: &pb.FilesAndMultiProof{
+				Proof:       linearProof,
+				ProofFlags:  multiProof.ProofFlags,
+				LeafIndexes: leafIndexes,
+				TreeSize:    int32(multiProof.TreeSize),
+			},
+		},
+	}
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+	for _, fileNum := range req.FileNums {
+		if err := sendBankFile(stream, req.PubKeyAddr, int(fileNum)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sends a file of a bank as parts, reading it from disk as it is sent
+func sendBankFile(stream pb.FileBankService_DownloadFilesServer, pubKeyAddr string, fileNum int) error {
+	file, err := storage.Server_OpenFileFromBank(bankhome, pubKeyAddr, fileNum)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := newDownloadWriter(stream, fileNum)
+	if _, err := io.Copy(writer, file); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// writer sending the bytes of file fileNum as parts of a download
+func newDownloadWriter(stream pb.FileBankService_DownloadFilesServer, fileNum int) *storage.FileMessageWriter {
+	return storage.NewFileMessageWriter(func(part *pb.FileMessage) error {
+		return stream.Send(&pb.DownloadFilesResponse{
+			Phase: &pb.DownloadFilesResponse_File{
+				File: part,
+			},
+		})
+	}, int32(fileNum))
+}
+
+// content hash of a file of a bank, read from disk. Leafs of sorted trees are only found by the hash of their file
+func bankFileContentHash(tree *merkle.MerkleTree, pubKeyAddr string, fileNum int) ([32]byte, error) {
+	file, err := storage.Server_OpenFileFromBank(bankhome, pubKeyAddr, fileNum)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	defer file.Close()
+	hasher, err := tree.NewContentHasher()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if _, err := io.Copy(hasher, file); err != nil {
+		return [32]byte{}, err
+	}
+	return hasher.Sum(), nil
+}
+
+func sendRangeAndProof(stream pb.FileBankService_DownloadFilesServer, req *pb.DownloadFilesRequest, bankDescriptor *pb.ServerBankDescriptor) error {
+	if !merkle.TreeMode(bankDescriptor.TreeMode).IsIndexed() || bankDescriptor.ChunkSize < 1 {
+		return errors.New("Ranged downloads are only supported by banks using chunked indexed trees")
+	}
+	if req.FileNum < 1 || req.FileNum > bankDescriptor.Nbfiles {
+		return errors.New(fmt.Sprintf("No file identified by %v. Bank %v has files between 1-%v", req.FileNum, req.PubKeyAddr, bankDescriptor.Nbfiles))
+	}
+
+	file, err := storage.Server_OpenFileFromBank(bankhome, req.PubKeyAddr, int(req.FileNum))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset, length := req.Range.Offset, req.Range.Length
+	chunkSize := int64(bankDescriptor.ChunkSize)
+	firstChunk, lastChunk, err := merkle.ChunksOfRange(offset, length, info.Size(), int(chunkSize))
+	if err != nil {
+		return errors.New(fmt.Sprintf("Invalid range for file %v: %v", req.FileNum, err))
+	}
+
+	// chunk tree of the file, its root is the content hash of the file in the bank tree
+	chunkTree, releaseChunks, err := loadFileChunkTree(req.PubKeyAddr, bankDescriptor, int(req.FileNum), file)
+	if err != nil {
+		return err
+	}
+	defer releaseChunks()
+	chunkRoot := chunkTree.GetMerkleRoot()
+
+	var chunkNums []int
+	for i := firstChunk; i <= lastChunk; i++ {
+		chunkNums = append(chunkNums, int(i)+1)
+	}
+	chunksProof, err := chunkTree.GenerateMultiProofForFileNums(chunkNums)
+	if err != nil {
+		return err
+	}
+
+	// prove chunk tree root in bank tree
+	merkleTree, release, err := loadBankMerkleTree(req.PubKeyAddr, bankDescriptor, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	merkleProof, err := merkleTree.GenerateProofForFileNum(int(req.FileNum))
+	if err != nil {
+		return err
+	}
+
+	resp := &pb.DownloadFilesResponse{
+		Phase: &pb.DownloadFilesResponse_Rp{
+			Rp: &pb.RangeAndProof{
+				FirstChunk:  int32(firstChunk),
+				ChunksProof: linearizeHashes(chunksProof.Hashes),
+				ChunkRoot:   chunkRoot[:],
+				Proof:       linearizeHashes(merkleProof.Hashes),
+				LeafIndex:   int32(merkleProof.Index),
+				TreeSize:    int32(merkleProof.TreeSize),
+			},
+		},
+	}
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+
+	// send chunks covering the range as parts of the file, read from disk as they are sent
+	chunksEnd := min((lastChunk+1)*chunkSize, info.Size())
+	writer := newDownloadWriter(stream, int(req.FileNum))
+	if _, err := io.Copy(writer, io.NewSectionReader(file, firstChunk*chunkSize, chunksEnd-firstChunk*chunkSize)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// returns the chunk tree of a file, and a function releasing it. Files stored before chunk tree files get their tree
+// built from the file, which is then written for the next ranges
+func loadFileChunkTree(pubKeyAddr string, bankDescriptor *pb.ServerBankDescriptor, fileNum int, file io.Reader) (*merkle.MerkleTree, func(), error) {
+	version := merkle.TreeVersion(bankDescriptor.TreeVersion)
+	algorithm := cr.HashAlgorithm(bankDescriptor.HashAlgorithm)
+	treeFile, err := storage.Server_OpenChunkTreeFile(bankhome, pubKeyAddr, bankDescriptor, fileNum)
+	if err == nil {
+		release := func() {
+			if err := treeFile.Close(); err != nil {
+				log.Printf("Could not unmap chunk tree of file %v of bank %v: %v", fileNum, pubKeyAddr, err)
+			}
+		}
+		tree, err := merkle.LoadMerkleTreeFromNodes(treeFile.Nodes, merkle.IndexedTree, version, algorithm, 0)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		return tree, release, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	tree, err := merkle.BuildChunkTree(file, int(bankDescriptor.ChunkSize), version, algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := storage.Server_WriteChunkTreeFile(bankhome, pubKeyAddr, bankDescriptor, fileNum, tree.Hashes); err != nil {
+		log.Printf("Could not write chunk tree of file %v of bank %v: %v", fileNum, pubKeyAddr, err)
+	}
+	return tree, func() {}, nil
+}
+
+// returns the merkle tree of a bank, and a function releasing it once the tree is not used anymore.
+// Nodes of tree files are mapped in memory and only read when used, unless their checksum is verified again.
+func loadBankMerkleTree(pubKeyAddr string, bankDescriptor *pb.ServerBankDescriptor, verifyChecksum bool) (*merkle.MerkleTree, func(), error) {
+	mode := merkle.TreeMode(bankDescriptor.TreeMode)
+	version := merkle.TreeVersion(bankDescriptor.TreeVersion)
+	algorithm := cr.HashAlgorithm(bankDescriptor.HashAlgorithm)
+	chunkSize := int(bankDescriptor.ChunkSize)
+	if !bankDescriptor.TreeFile {
+		// banks created before tree files keep their hashes in the descriptor
+		tree, err := merkle.LoadMerkleTree(bankDescriptor.MerkleHashes, mode, version, algorithm, chunkSize)
+		return tree, func() {}, err
+	}
+
+	treeFile, err := storage.Server_OpenTreeFile(bankhome, pubKeyAddr, bankDescriptor)
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() {
+		if err := treeFile.Close(); err != nil {
+			log.Printf("Could not unmap tree file of bank %v: %v", pubKeyAddr, err)
+		}
+	}
+	if verifyChecksum {
+		if err := treeFile.VerifyChecksum(); err != nil {
+			release()
+			return nil, nil, err
+		}
+	}
+	tree, err := merkle.LoadMerkleTreeFromNodes(treeFile.Nodes, mode, version, algorithm, chunkSize)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return tree, release, nil
+}
+
 func verifyBankExistenceFromAddress(keyHashB58 string) (bool, error) {
 	if exists, err := storage.Server_BankExists(bankhome, keyHashB58); err != nil {
 		return false, err
@@ -130,3 +409,16 @@ func verifyBankExistenceFromAddress(keyHashB58 string) (bool, error) {
 	}
 	return false, nil
 }
+
+// returns the key that signs requests to a bank, the bank key or the identity key of one of its recipients
+func bankRequestSigner(bankDescriptor *pb.ServerBankDescriptor, signer []byte) (ed25519.PublicKey, error) {
+	if len(signer) == 0 {
+		return cr.ImportPublicKey(bankDescriptor.PubKey)
+	}
+	for _, recipient := range bankDescriptor.Recipients {
+		if len(signer) == ed25519.PublicKeySize && bytes.Equal(recipient.Identity, signer) {
+			return ed25519.PublicKey(signer), nil
+		}
+	}
+	return nil, errors.New("Signer is not a recipient of the bank")
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"log"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// BankManifest stores the manifest of a bank along with its recipients, or sends them back when the request has no
+// manifest. The manifest is encrypted by the client, the server only keeps it for the bank to be restored from its
+// recovery phrase, or fetched by its recipients. Requests are signed by the bank key, recipients can only read
+func (c *fileBankServer) BankManifest(stream pb.FileBankService_BankManifestServer) error {
+	log.Printf("Received call: BankManifest")
+	serverNonce, err := cr.Random12BytesNonce()
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.BankManifestResponse{
+		Phase: &pb.BankManifestResponse_Nonce{
+			Nonce: serverNonce,
+		},
+	}); err != nil {
+		return err
+	}
+
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by client")
+	}
+	if err != nil {
+		return err
+	}
+
+	// verify nonce matches
+	if !bytes.Equal(req.Nonce, serverNonce) {
+		return errors.New("Invalid challenge response nonce")
+	}
+
+	// verify bank existence
+	if exists, err := verifyBankExistenceFromAddress(req.PubKeyAddr); err != nil {
+		return err
+	} else if !exists {
+		return errors.New("Bank does not exist")
+	}
+	bankDescriptor, err := storage.Server_ReadBankDescriptor(bankhome, req.PubKeyAddr)
+	if err != nil {
+		return err
+	}
+	pubKey, err := bankRequestSigner(bankDescriptor, req.Signer)
+	if err != nil {
+		return err
+	}
+
+	// verify signature
+	clientSignedMsg := &pb.SignManifestRequestClient{
+		Nonce:      req.Nonce,
+		PubKeyAddr: req.PubKeyAddr,
+		Manifest:   req.Manifest,
+		Recipients: req.Recipients,
+		Signer:     req.Signer,
+	}
+	if err := cr.VerifySignature(clientSignedMsg, pubKey, req.Signature); err != nil {
+		return err
+	}
+
+	result := &pb.ManifestResult{Nbfiles: bankDescriptor.Nbfiles}
+	if len(req.Manifest) > 0 {
+		if len(req.Signer) > 0 {
+			return errors.New("Only the bank key can store the manifest")
+		}
+		if err := verifyBankRecipients(req.Recipients); err != nil {
+			return err
+		}
+		if err := writeBankManifest(req.PubKeyAddr, req.Manifest, req.Recipients, req.Nonce, req.Signature); err != nil {
+			return err
+		}
+	} else {
+		result.Recipients = bankDescriptor.Recipients
+		result.PubKey = bankDescriptor.PubKey
+		result.Nonce = bankDescriptor.ManifestNonce
+		result.Signature = bankDescriptor.ManifestSignature
+		if result.Manifest, err = storage.Server_ReadBankManifest(bankhome, req.PubKeyAddr); err != nil {
+			return err
+		}
+		if result.Manifest == nil {
+			return errors.New("Bank has no manifest")
+		}
+	}
+
+	return stream.Send(&pb.BankManifestResponse{
+		Phase: &pb.BankManifestResponse_Result{
+			Result: result,
+		},
+	})
+}
+
+// recipients are only used by the server to verify signatures with their identity key
+func verifyBankRecipients(recipients []*pb.BankRecipient) error {
+	for _, recipient := range recipients {
+		if len(recipient.Identity) != ed25519.PublicKeySize {
+			return errors.New("Invalid recipient identity key")
+		}
+	}
+	return nil
+}
+
+// the manifest is not written while the bank is appended to or rotated, which could remove it or overwrite the
+// recipients. The signature of the request is kept with them, for recipients to verify they were stored by the bank key
+func writeBankManifest(pubKeyAddr string, manifest []byte, recipients []*pb.BankRecipient, nonce []byte, signature []byte) error {
+	appendLock.Lock()
+	defer appendLock.Unlock()
+
+	if exists, err := verifyBankExistenceFromAddress(pubKeyAddr); err != nil {
+		return err
+	} else if !exists {
+		return errors.New("Bank does not exist")
+	}
+	bankDescriptor, err := storage.Server_ReadBankDescriptor(bankhome, pubKeyAddr)
+	if err != nil {
+		return err
+	}
+	// recipients are updated first, a revoked recipient must not read the new manifest
+	bankDescriptor.Recipients = recipients
+	bankDescriptor.ManifestNonce = nonce
+	bankDescriptor.ManifestSignature = signature
+	if err := storage.Server_UpdateBankDescriptor(bankhome, bankDescriptor); err != nil {
+		return err
+	}
+	return storage.Server_WriteBankManifest(bankhome, pubKeyAddr, manifest)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"log"
+
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// file received from a client, staged in the directory of a bank until it is numbered
+type receivedFile struct {
+	stagedPath  string
+	contentHash [32]byte
+	// chunk tree of the file, nil when files are not chunked
+	chunkTree *merkle.MerkleTree
+}
+
+// receives nbfiles files numbered from firstSeq, and stages them in the directory of bank pubKeyAddr. Files are written
+// to disk and hashed with the parameters of tree as they are received, and are never held in memory. Staged files are
+// left to removeStagedFiles, once committed or not
+func receiveBankFiles(files *storage.FileMessageReader, pubKeyAddr string, tree merkle.MerkleTree, firstSeq, nbfiles int) ([]*receivedFile, error) {
+	var received []*receivedFile
+	for i := 0; i < nbfiles; i++ {
+		file, err := receiveBankFile(files, pubKeyAddr, tree, firstSeq+i)
+		if file != nil {
+			received = append(received, file)
+		}
+		if err != nil {
+			removeStagedFiles(received)
+			return nil, err
+		}
+	}
+	return received, nil
+}
+
+func receiveBankFile(files *storage.FileMessageReader, pubKeyAddr string, tree merkle.MerkleTree, seq int) (*receivedFile, error) {
+	if err := files.Next(int32(seq)); err != nil {
+		return nil, err
+	}
+	hasher, err := tree.NewContentHasher()
+	if err != nil {
+		return nil, err
+	}
+	staged, err := storage.Server_CreateStagedFile(bankhome, pubKeyAddr)
+	if err != nil {
+		return nil, err
+	}
+	received := &receivedFile{stagedPath: staged.Name()}
+	writer := bufio.NewWriter(staged)
+	_, err = io.Copy(io.MultiWriter(writer, hasher), files)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := staged.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return received, err
+	}
+	received.contentHash = hasher.Sum()
+	received.chunkTree = hasher.ChunkTree()
+	return received, nil
+}
+
+// numbers received files from firstSeq in bank pubKeyAddr, with their chunk trees so that their ranges are proven
+// without reading them
+func commitReceivedFiles(received []*receivedFile, pubKeyAddr string, bankDescriptor *pb.ServerBankDescriptor, firstSeq int) error {
+	for i, file := range received {
+		if err := storage.Server_CommitStagedFile(bankhome, file.stagedPath, pubKeyAddr, firstSeq+i); err != nil {
+			return err
+		}
+		if file.chunkTree == nil || !merkle.TreeMode(bankDescriptor.TreeMode).IsIndexed() {
+			continue
+		}
+		if err := storage.Server_WriteChunkTreeFile(bankhome, pubKeyAddr, bankDescriptor, firstSeq+i, file.chunkTree.Hashes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removes the received files that were not committed
+func removeStagedFiles(received []*receivedFile) {
+	for _, file := range received {
+		if err := storage.Server_RemoveStagedFile(file.stagedPath); err != nil {
+			log.Printf("Could not remove staged file %v: %v", file.stagedPath, err)
+		}
+	}
+}
+
+func receivedContentHashes(received []*receivedFile) [][32]byte {
+	var contentHashes [][32]byte
+	for _, file := range received {
+		contentHashes = append(contentHashes, file.contentHash)
+	}
+	return contentHashes
+}
+
+// builds the tree of received files, with the parameters of params
+func buildReceivedTree(params merkle.MerkleTree, received []*receivedFile) (*merkle.MerkleTree, error) {
+	builder, err := merkle.NewMerkleTreeBuilder(params.Mode, params.Version, params.Hash, params.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range received {
+		builder.AddContentHash(file.contentHash)
+	}
+	return builder.Build()
+}
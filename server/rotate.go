@@ -0,0 +1,240 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	cr "github.com/oteffahi/merkle-filebank/cryptography"
+	"github.com/oteffahi/merkle-filebank/merkle"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"github.com/oteffahi/merkle-filebank/storage"
+)
+
+// RotateBank replaces all the files of a bank with files encrypted again by the client, and moves the bank to a new
+// key. The old key hands the bank over to the new key by signing it, along with the new key and root, and the new key
+// signs the same message to prove it is owned by the client. The handover is kept in the new bank, and the old bank
+// is removed.
+func (c *fileBankServer) RotateBank(stream pb.FileBankService_RotateBankServer) error {
+	log.Printf("Received call: RotateBank")
+	serverNonce, err := cr.Random12BytesNonce()
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.RotateBankResponse{
+		Phase: &pb.RotateBankResponse_Nonce{
+			Nonce: serverNonce,
+		},
+	}); err != nil {
+		return err
+	}
+
+	req1, err := stream.Recv()
+	if err == io.EOF {
+		return errors.New("Connexion closed by client")
+	}
+	if err != nil {
+		return err
+	}
+
+	var rotateReq *pb.RotateRequest
+	switch phase := req1.Phase.(type) {
+	case *pb.RotateBankRequest_RotateReq:
+		rotateReq = phase.RotateReq
+	default:
+		return errors.New("Invalid message type")
+	}
+	handover := rotateReq.Handover
+	if handover == nil {
+		return errors.New("Missing bank handover")
+	}
+
+	// verify nonce matches
+	if !bytes.Equal(handover.Nonce, serverNonce) {
+		return errors.New("Invalid challenge response nonce")
+	}
+
+	// verify old bank exists and new bank does not
+	oldKeyHash := cr.HashOnce(handover.OldPubKey)
+	oldPubKeyAddr := cr.Base58Encode(oldKeyHash[:])
+	if exists, err := verifyBankExistenceFromAddress(oldPubKeyAddr); err != nil {
+		return err
+	} else if !exists {
+		return errors.New("Bank does not exist")
+	}
+	if exists, err := verifyBankExistence(handover.NewPubKey); err != nil {
+		return err
+	} else if exists {
+		return errors.New("Bank already exists")
+	}
+	bankDescriptor, err := storage.Server_ReadBankDescriptor(bankhome, oldPubKeyAddr)
+	if err != nil {
+		return err
+	}
+
+	// verify both keys signed the handover
+	if err := verifyBankHandover(handover); err != nil {
+		return err
+	}
+	if err := verifyBankRecipients(handover.Recipients); err != nil {
+		return err
+	}
+
+	// client must replace all the files of the current version of the bank
+	if handover.Nbfiles != bankDescriptor.Nbfiles {
+		return errors.New(fmt.Sprintf("Bank %v has %v files, client expected %v", oldPubKeyAddr, bankDescriptor.Nbfiles, handover.Nbfiles))
+	}
+
+	// read files, staged in the old bank until the new one is written. The client closes the stream after the last file
+	files := storage.NewFileMessageReader(func() (*pb.FileMessage, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if phase, ok := req.Phase.(*pb.RotateBankRequest_File); ok {
+			return phase.File, nil
+		}
+		return nil, errors.New("Invalid message type")
+	})
+	received, err := receiveBankFiles(files, oldPubKeyAddr, bankTreeParams(bankDescriptor), 1, int(handover.Nbfiles))
+	if err != nil {
+		return err
+	}
+	defer removeStagedFiles(received)
+
+	// generate merkle tree for files, with the parameters of the bank
+	tree, err := buildReceivedTree(bankTreeParams(bankDescriptor), received)
+	if err != nil {
+		return err
+	}
+	merkleRoot := tree.GetMerkleRoot()
+	if !bytes.Equal(merkleRoot[:], handover.MerkleRoot) {
+		return errors.New("Merkle root of the files does not match the handover")
+	}
+
+	if err := writeRotatedBank(oldPubKeyAddr, bankDescriptor, handover, received, tree); err != nil {
+		return err
+	}
+
+	// files stored correctly. Sign response
+	msgToSign := &pb.SignBankHandoverServer{
+		Nonce:      rotateReq.ClientNonce,
+		OldPubKey:  handover.OldPubKey,
+		NewPubKey:  handover.NewPubKey,
+		MerkleRoot: merkleRoot[:],
+	}
+	sign, err := cr.SignMessage(msgToSign, ServerKeys.privKey)
+	if err != nil {
+		return err
+	}
+
+	resp := &pb.RotateBankResponse{
+		Phase: &pb.RotateBankResponse_RotatedRoot{
+			RotatedRoot: &pb.RotatedRoot{
+				Nonce:      rotateReq.ClientNonce,
+				MerkleRoot: merkleRoot[:],
+				Signature:  sign,
+			},
+		},
+	}
+
+	// only send when successfuly written to disk
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+func verifyBankHandover(handover *pb.BankHandover) error {
+	oldPubKey, err := cr.ImportPublicKey(handover.OldPubKey)
+	if err != nil {
+		return err
+	}
+	newPubKey, err := cr.ImportPublicKey(handover.NewPubKey)
+	if err != nil {
+		return err
+	}
+	clientSignedMsg := &pb.SignRotateRequestClient{
+		Nonce:      handover.Nonce,
+		OldPubKey:  handover.OldPubKey,
+		NewPubKey:  handover.NewPubKey,
+		Nbfiles:    handover.Nbfiles,
+		MerkleRoot: handover.MerkleRoot,
+		Recipients: handover.Recipients,
+	}
+	if err := cr.VerifySignature(clientSignedMsg, oldPubKey, handover.OldSignature); err != nil {
+		return err
+	}
+	return cr.VerifySignature(clientSignedMsg, newPubKey, handover.NewSignature)
+}
+
+// writes the new bank, then removes the old one. Appends are locked out, so that no file is added to the old bank
+// after it was read. The descriptor of the new bank is written last, so that it never references missing files, and
+// the new bank is removed if it could not be written, so that the rotation can be retried
+func writeRotatedBank(oldPubKeyAddr string, oldDescriptor *pb.ServerBankDescriptor, handover *pb.BankHandover, received []*receivedFile, tree *merkle.MerkleTree) (err error) {
+	appendLock.Lock()
+	defer appendLock.Unlock()
+
+	// bank must not have changed since the request was verified
+	current, err := storage.Server_ReadBankDescriptor(bankhome, oldPubKeyAddr)
+	if err != nil {
+		return err
+	}
+	if current.Nbfiles != oldDescriptor.Nbfiles {
+		return errors.New("Bank was modified during rotation")
+	}
+
+	newDescriptor := &pb.ServerBankDescriptor{
+		PubKey:        handover.NewPubKey,
+		Nbfiles:       handover.Nbfiles,
+		TreeMode:      oldDescriptor.TreeMode,
+		TreeVersion:   oldDescriptor.TreeVersion,
+		HashAlgorithm: oldDescriptor.HashAlgorithm,
+		ChunkSize:     oldDescriptor.ChunkSize,
+		TreeFile:      true,
+		Handover:      handover,
+		// recipients keep their access without waiting for the manifest of the new bank
+		Recipients: handover.Recipients,
+	}
+	newKeyHash := cr.HashOnce(handover.NewPubKey)
+	newPubKeyAddr := cr.Base58Encode(newKeyHash[:])
+	if err := storage.Server_CreateBankDirectory(bankhome, newPubKeyAddr); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if removeErr := storage.Server_RemoveBank(bankhome, newPubKeyAddr); removeErr != nil {
+				log.Printf("Could not remove bank %v of failed rotation: %v", newPubKeyAddr, removeErr)
+			}
+		}
+	}()
+
+	if err := commitReceivedFiles(received, newPubKeyAddr, newDescriptor, 1); err != nil {
+		return err
+	}
+	if err := storage.Server_WriteTreeFile(bankhome, newPubKeyAddr, newDescriptor, tree.Hashes); err != nil {
+		return err
+	}
+	if err := storage.Server_CommitBankDescriptor(bankhome, newDescriptor); err != nil {
+		return err
+	}
+
+	// the new bank is complete, the old one is only left behind when it cannot be removed
+	if err := storage.Server_RemoveBank(bankhome, oldPubKeyAddr); err != nil {
+		log.Printf("Could not remove bank %v after its rotation: %v", oldPubKeyAddr, err)
+	}
+	log.Printf("Bank %v was rotated to %v", oldPubKeyAddr, newPubKeyAddr)
+	return nil
+}
+
+// tree without nodes, holding the parameters used to hash the files of a bank
+func bankTreeParams(bankDescriptor *pb.ServerBankDescriptor) merkle.MerkleTree {
+	return merkle.MerkleTree{
+		Mode:      merkle.TreeMode(bankDescriptor.TreeMode),
+		Version:   merkle.TreeVersion(bankDescriptor.TreeVersion),
+		Hash:      cr.HashAlgorithm(bankDescriptor.HashAlgorithm),
+		ChunkSize: int(bankDescriptor.ChunkSize),
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"crypto/ed25519"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 
 	cr "github.com/oteffahi/merkle-filebank/cryptography"
-	"github.com/oteffahi/merkle-filebank/merkle"
 	pb "github.com/oteffahi/merkle-filebank/proto"
 	"github.com/oteffahi/merkle-filebank/storage"
 )
 
+// largest chunk size accepted for chunked trees
+const maxChunkSize = 64 << 20
+
 func (c *fileBankServer) UploadFiles(stream pb.FileBankService_UploadFilesServer) error {
 	log.Printf("Received call: UploadFiles")
 	serverNonce, err := cr.Random12BytesNonce()
@@ -58,6 +61,23 @@ func (c *fileBankServer) UploadFiles(stream pb.FileBankService_UploadFilesServer
 		return err
 	}
 
+	// verify tree mode is supported
+	if _, known := pb.TreeMode_name[int32(signedResp.TreeMode)]; !known {
+		return errors.New("Unsupported merkle tree mode")
+	}
+	if _, known := pb.TreeVersion_name[int32(signedResp.TreeVersion)]; !known {
+		return errors.New("Unsupported merkle tree version")
+	}
+	if _, err := cr.NewHasher(cr.HashAlgorithm(signedResp.HashAlgorithm)); err != nil {
+		return errors.New(fmt.Sprintf("Unsupported hash algorithm %v", signedResp.HashAlgorithm))
+	}
+	if signedResp.TreeVersion == pb.TreeVersion_TREE_OPENZEPPELIN && (signedResp.HashAlgorithm != pb.HashAlgorithm_KECCAK256 || signedResp.TreeMode != pb.TreeMode_SORTED_TREE) {
+		return errors.New("OpenZeppelin trees are sorted trees using keccak256")
+	}
+	if signedResp.ChunkSize < 0 || signedResp.ChunkSize > maxChunkSize {
+		return errors.New(fmt.Sprintf("Invalid chunk size %v, maximum is %v", signedResp.ChunkSize, maxChunkSize))
+	}
+
 	// check bank existence
 	if exists, err := verifyBankExistence(signedResp.Pubkey); err != nil {
 		return err
@@ -65,49 +85,65 @@ func (c *fileBankServer) UploadFiles(stream pb.FileBankService_UploadFilesServer
 		return errors.New("Bank already exists")
 	}
 
-	// read files
-	var files [][]byte
-	for i := 0; i < int(signedResp.Nbfiles); i++ {
-		req2, err := stream.Recv()
-		if err == io.EOF {
-			return errors.New("Connexion closed by client")
+	// files are staged in the directory of the bank, which is removed unless the upload completes
+	keyHash := cr.HashOnce(signedResp.Pubkey)
+	pubKeyAddr := cr.Base58Encode(keyHash[:])
+	if err := storage.Server_CreateBankDirectory(bankhome, pubKeyAddr); err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
 		}
-		if err != nil {
-			return err
+		if err := storage.Server_RemoveBank(bankhome, pubKeyAddr); err != nil {
+			log.Printf("Could not remove bank %v of failed upload: %v", pubKeyAddr, err)
 		}
+	}()
 
-		var file *pb.FileMessage
-
-		// verify type of message
-		switch phase := req2.Phase.(type) {
-		case *pb.UploadFilesRequest_File:
-			file = phase.File
-		default:
-			return errors.New("Invalid message type")
+	// merkle hashes are stored in the tree file
+	bankDescriptor := &pb.ServerBankDescriptor{
+		PubKey:        signedResp.Pubkey,
+		Nbfiles:       signedResp.Nbfiles,
+		TreeMode:      signedResp.TreeMode,
+		TreeVersion:   signedResp.TreeVersion,
+		HashAlgorithm: signedResp.HashAlgorithm,
+		ChunkSize:     signedResp.ChunkSize,
+		TreeFile:      true,
+	}
+
+	// read files, the nonce follows the last one
+	var req3 *pb.UploadFilesRequest
+	files := storage.NewFileMessageReader(func() (*pb.FileMessage, error) {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("Connexion closed by client")
+		}
+		if err != nil {
+			return nil, err
 		}
-		// verify messages are in order
-		if int(file.Seq) != i+1 {
-			return errors.New("Invalid file order")
+		if phase, ok := req.Phase.(*pb.UploadFilesRequest_File); ok {
+			return phase.File, nil
 		}
-		files = append(files, file.Content)
+		req3 = req
+		return nil, io.EOF
+	})
+	received, err := receiveBankFiles(files, pubKeyAddr, bankTreeParams(bankDescriptor), 1, int(signedResp.Nbfiles))
+	if err != nil {
+		return err
 	}
 
 	// generate merkle tree for files
-	tree, err := generateMerkleTreeForFiles(files)
+	tree, err := buildReceivedTree(bankTreeParams(bankDescriptor), received)
 	if err != nil {
 		return err
 	}
 	merkleRoot := tree.GetMerkleRoot()
 
 	// read nonce
-	req3, err := stream.Recv()
-	if err == io.EOF {
-		return errors.New("Connexion closed by client")
-	}
-	if err != nil {
-		return err
+	if req3 == nil {
+		return errors.New("Invalid message type")
 	}
-
 	var clientNonce []byte
 	switch phase := req3.Phase.(type) {
 	case *pb.UploadFilesRequest_Nonce:
@@ -116,28 +152,17 @@ func (c *fileBankServer) UploadFiles(stream pb.FileBankService_UploadFilesServer
 		return errors.New("Invalid message type")
 	}
 
-	// convert tree.Hashes from slice of arrays to slice of slices
-	merkleHashes := [][]byte{}
-	for i := 0; i < len(tree.Hashes); i++ {
-		hash := tree.Hashes[i]
-		merkleHashes = append(merkleHashes, hash[:])
-	}
-	// write bank descriptor
-	bankDescriptor := &pb.ServerBankDescriptor{
-		PubKey:       signedResp.Pubkey,
-		Nbfiles:      signedResp.Nbfiles,
-		MerkleHashes: merkleHashes,
+	// write files and tree file before descriptor, so that the descriptor never references missing files
+	if err := commitReceivedFiles(received, pubKeyAddr, bankDescriptor, 1); err != nil {
+		return err
 	}
-	if err := storage.Server_WriteBankDescriptor(bankhome, bankDescriptor); err != nil {
+	if err := storage.Server_WriteTreeFile(bankhome, pubKeyAddr, bankDescriptor, tree.Hashes); err != nil {
 		return err
 	}
-
-	// write files
-	for i := 0; i < len(files); i++ {
-		if err := storage.Server_WriteFileToBank(bankhome, signedResp.Pubkey, files[i], i+1); err != nil {
-			return err
-		}
+	if err := storage.Server_CommitBankDescriptor(bankhome, bankDescriptor); err != nil {
+		return err
 	}
+	committed = true
 
 	// files stored correctly. Sign response
 	msgToSign := &pb.SignMerkleRootServer{
@@ -168,9 +193,13 @@ func (c *fileBankServer) UploadFiles(stream pb.FileBankService_UploadFilesServer
 
 func verifyUploadChallengeResponseSignature(resp *pb.ChallengeResponse, pubKey ed25519.PublicKey) error {
 	clientSignedMsg := &pb.SignUploadRequestClient{
-		Nonce:   resp.Nonce,
-		PubKey:  resp.Pubkey,
-		Nbfiles: resp.Nbfiles,
+		Nonce:         resp.Nonce,
+		PubKey:        resp.Pubkey,
+		Nbfiles:       resp.Nbfiles,
+		TreeMode:      resp.TreeMode,
+		TreeVersion:   resp.TreeVersion,
+		HashAlgorithm: resp.HashAlgorithm,
+		ChunkSize:     resp.ChunkSize,
 	}
 	return cr.VerifySignature(clientSignedMsg, pubKey, resp.Signature)
 }
@@ -185,9 +214,3 @@ func verifyBankExistence(clientPubKey []byte) (bool, error) {
 	}
 	return false, nil
 }
-
-func generateMerkleTreeForFiles(files [][]byte) (*merkle.MerkleTree, error) {
-	var tree merkle.MerkleTree
-	err := tree.BuildMerkleTree(files)
-	return &tree, err
-}
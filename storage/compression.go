@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	pb "github.com/oteffahi/merkle-filebank/proto"
+)
+
+// magic numbers of formats that are already compressed
+var compressedMagics = [][]byte{
+	{0x1f, 0x8b},                       // gzip
+	{0x28, 0xb5, 0x2f, 0xfd},           // zstd
+	{'P', 'K', 0x03, 0x04},             // zip, docx, jar...
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},   // xz
+	{'B', 'Z', 'h'},                    // bzip2
+	{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, // 7z
+	{0x89, 'P', 'N', 'G'},              // png
+	{0xff, 0xd8, 0xff},                 // jpeg
+	{'G', 'I', 'F', '8'},               // gif
+	{'R', 'I', 'F', 'F'},               // webp, avi, wav
+	{'O', 'g', 'g', 'S'},               // ogg
+	{'f', 'L', 'a', 'C'},               // flac
+	{'I', 'D', '3'},                    // mp3
+	{0x04, 0x22, 0x4d, 0x18},           // lz4
+}
+
+// length of the longest magic number
+const maxMagicLen = 6
+
+// FileCompression returns the codec to apply to a file before encryption, and seeks the file back to its start. Files
+// that are already compressed, or that do not get smaller, are kept as they are, which is only known after compressing
+// them once without keeping the output
+func FileCompression(file io.ReadSeeker, codec pb.Compression) (pb.Compression, error) {
+	if codec == pb.Compression_NO_COMPRESSION {
+		return codec, nil
+	}
+	header := make([]byte, maxMagicLen)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, err
+	}
+	if isCompressed(header[:n]) {
+		_, err := file.Seek(0, io.SeekStart)
+		return pb.Compression_NO_COMPRESSION, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var compressed byteCounter
+	if err := CompressFile(&compressed, file, codec); err != nil {
+		return 0, err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if int64(compressed) >= size {
+		return pb.Compression_NO_COMPRESSION, nil
+	}
+	return codec, nil
+}
+
+// CompressFile writes the compression of a file with codec to w, as the file is read
+func CompressFile(w io.Writer, file io.Reader, codec pb.Compression) error {
+	switch codec {
+	case pb.Compression_NO_COMPRESSION:
+		_, err := io.Copy(w, file)
+		return err
+	case pb.Compression_GZIP:
+		writer := gzip.NewWriter(w)
+		if _, err := io.Copy(writer, file); err != nil {
+			return err
+		}
+		return writer.Close()
+	case pb.Compression_ZSTD:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(encoder, file); err != nil {
+			encoder.Close()
+			return err
+		}
+		return encoder.Close()
+	default:
+		return errors.New(fmt.Sprintf("Unknown compression %v", codec))
+	}
+}
+
+// DecompressFile writes the decompression of a file compressed by CompressFile to w, as it is read
+func DecompressFile(w io.Writer, compressed io.Reader, codec pb.Compression) error {
+	switch codec {
+	case pb.Compression_NO_COMPRESSION:
+		_, err := io.Copy(w, compressed)
+		return err
+	case pb.Compression_GZIP:
+		reader, err := gzip.NewReader(compressed)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		_, err = io.Copy(w, reader)
+		return err
+	case pb.Compression_ZSTD:
+		decoder, err := zstd.NewReader(compressed)
+		if err != nil {
+			return err
+		}
+		defer decoder.Close()
+		_, err = io.Copy(w, decoder)
+		return err
+	default:
+		return errors.New(fmt.Sprintf("Unknown compression %v", codec))
+	}
+}
+
+func isCompressed(file []byte) bool {
+	for _, magic := range compressedMagics {
+		if bytes.HasPrefix(file, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// writer counting the bytes written to it, and discarding them
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
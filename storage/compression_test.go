@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	pb "github.com/oteffahi/merkle-filebank/proto"
+)
+
+var testCodecs = []pb.Compression{pb.Compression_GZIP, pb.Compression_ZSTD}
+
+func compressibleTestFile(size int) []byte {
+	return bytes.Repeat([]byte("merkle filebank "), size/16+1)[:size]
+}
+
+// checks the codec chosen for file, and that the file is read again from its start
+func checkFileCompression(t *testing.T, file []byte, codec pb.Compression, expected pb.Compression) {
+	reader := bytes.NewReader(file)
+	chosen, err := FileCompression(reader, codec)
+	if err != nil {
+		t.Errorf("error when choosing compression: %v", err)
+		t.FailNow()
+	}
+	if chosen != expected {
+		t.Errorf("compression of %v bytes with %v should be %v, got %v", len(file), codec, expected, chosen)
+	}
+	read, err := io.ReadAll(reader)
+	if err != nil {
+		t.Errorf("error when reading file after choosing compression: %v", err)
+		t.FailNow()
+	}
+	if !bytes.Equal(read, file) {
+		t.Errorf("file was not seeked back to its start after choosing compression with %v", codec)
+	}
+}
+
+func TestCompressDecompressFile(t *testing.T) {
+	for _, codec := range append(testCodecs, pb.Compression_NO_COMPRESSION) {
+		for _, size := range []int{0, 1, 100, 1 << 20} {
+			file := compressibleTestFile(size)
+			var compressed bytes.Buffer
+			if err := CompressFile(&compressed, bytes.NewReader(file), codec); err != nil {
+				t.Errorf("error when compressing with %v: %v", codec, err)
+				t.FailNow()
+			}
+			if size == 1<<20 && codec != pb.Compression_NO_COMPRESSION && compressed.Len() >= size {
+				t.Errorf("compression with %v of a repeated pattern should be smaller than %v bytes, got %v", codec, size, compressed.Len())
+			}
+			var decompressed bytes.Buffer
+			if err := DecompressFile(&decompressed, &compressed, codec); err != nil {
+				t.Errorf("error when decompressing with %v: %v", codec, err)
+				t.FailNow()
+			}
+			if !bytes.Equal(decompressed.Bytes(), file) {
+				t.Errorf("decompression with %v of %v bytes is different from original", codec, size)
+			}
+		}
+	}
+}
+
+func TestUnknownCompression(t *testing.T) {
+	var out bytes.Buffer
+	if err := CompressFile(&out, bytes.NewReader([]byte("file")), pb.Compression(42)); err == nil {
+		t.Errorf("compression with unknown codec should fail")
+	}
+	if err := DecompressFile(&out, bytes.NewReader([]byte("file")), pb.Compression(42)); err == nil {
+		t.Errorf("decompression with unknown codec should fail")
+	}
+}
+
+func TestFileCompression(t *testing.T) {
+	random := make([]byte, 4096)
+	if _, err := rand.Read(random); err != nil {
+		t.Errorf("error when generating random file: %v", err)
+		t.FailNow()
+	}
+	for _, codec := range testCodecs {
+		// files that shrink are compressed
+		checkFileCompression(t, compressibleTestFile(4096), codec, codec)
+		// random files do not shrink and are kept as they are
+		checkFileCompression(t, random, codec, pb.Compression_NO_COMPRESSION)
+		// empty and tiny files grow with the headers of the codec
+		checkFileCompression(t, nil, codec, pb.Compression_NO_COMPRESSION)
+		checkFileCompression(t, []byte("a"), codec, pb.Compression_NO_COMPRESSION)
+		// compression is disabled
+		checkFileCompression(t, compressibleTestFile(4096), pb.Compression_NO_COMPRESSION, pb.Compression_NO_COMPRESSION)
+	}
+}
+
+func TestFileCompressionSkipsCompressedFormats(t *testing.T) {
+	for _, codec := range testCodecs {
+		// compressible contents are not compressed when they start with the magic number of a compressed format
+		for _, magic := range compressedMagics {
+			file := append(append([]byte(nil), magic...), compressibleTestFile(4096)...)
+			checkFileCompression(t, file, codec, pb.Compression_NO_COMPRESSION)
+		}
+		// files compressed by CompressFile are not compressed again
+		var compressed bytes.Buffer
+		if err := CompressFile(&compressed, bytes.NewReader(compressibleTestFile(1<<16)), codec); err != nil {
+			t.Errorf("error when compressing with %v: %v", codec, err)
+			t.FailNow()
+		}
+		checkFileCompression(t, compressed.Bytes(), codec, pb.Compression_NO_COMPRESSION)
+		// magic numbers are only matched at the start of files
+		file := append(compressibleTestFile(4096), compressedMagics[0]...)
+		checkFileCompression(t, file, codec, codec)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func WriteSavedProof(path string, savedProof *pb.SavedProof, asJSON bool) error {
+	var data []byte
+	var err error
+	if asJSON {
+		data, err = protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(savedProof)
+	} else {
+		data, err = proto.Marshal(savedProof)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reads a proof saved in JSON or binary format
+func ReadSavedProof(path string) (*pb.SavedProof, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	savedProof := &pb.SavedProof{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = protojson.Unmarshal(data, savedProof)
+	} else {
+		err = proto.Unmarshal(data, savedProof)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return savedProof, nil
+}
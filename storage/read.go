@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -87,16 +88,18 @@ func Server_ReadBankDescriptor(bankhome string, pubKeyHashB58 string) (*pb.Serve
 	return descriptor, nil
 }
 
-func Server_ReadFileFromBank(bankhome string, pubKeyHashB58 string, fileNum int) ([]byte, error) {
-	// clientPubKey is assumed hashed and b58encoded in exported format
-	dirName := pubKeyHashB58
+// opens a file of a bank for reading, the caller must close it
+func Server_OpenFileFromBank(bankhome string, pubKeyHashB58 string, fileNum int) (*os.File, error) {
+	return os.Open(fmt.Sprintf("%s/server/%s/%d", bankhome, pubKeyHashB58, fileNum))
+}
 
-	file, err := os.ReadFile(fmt.Sprintf("%s/server/%s/%d", bankhome, dirName, fileNum))
-	if err != nil {
-		return nil, err
+// reads the encrypted manifest of a bank, nil when the client never stored one
+func Server_ReadBankManifest(bankhome string, pubKeyHashB58 string) ([]byte, error) {
+	manifest, err := os.ReadFile(bankhome + "/server/" + pubKeyHashB58 + "/manifest")
+	if os.IsNotExist(err) {
+		return nil, nil
 	}
-
-	return file, nil
+	return manifest, err
 }
 
 func Client_BankExists(bankhome string, serverName string, bankName string) (bool, error) {
@@ -130,6 +133,22 @@ func Client_ReadBankDescriptor(bankhome string, serverName string, bankName stri
 	return descriptor, nil
 }
 
+func Client_ReadIdentityKey(bankhome string) ([]byte, error) {
+	key, err := os.ReadFile(bankhome + "/client/identity.key")
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No identity key in %v, create one with 'identity create'", bankhome)
+	}
+	return key, err
+}
+
+func Client_ReadIdentityPublicKey(bankhome string) (string, error) {
+	pubKey, err := os.ReadFile(bankhome + "/client/identity.pub")
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("No identity key in %v, create one with 'identity create'", bankhome)
+	}
+	return strings.TrimSpace(string(pubKey)), err
+}
+
 func Client_ReadServerDescriptor(bankhome string, serverName string) (*pb.ServerDescriptor, error) {
 	desc, err := os.ReadFile(fmt.Sprintf("%s/client/srv_%s/server.desc", bankhome, serverName))
 	if err != nil {
@@ -245,18 +264,18 @@ func GetAllFilesPaths(rootPath string) ([]string, error) {
 	}
 }
 
-func ReadFilesFromPaths(paths []string) (names []string, files [][]byte, err error) {
+// returns the names of the files at paths, which are read one at a time when they are sent
+func FileNamesFromPaths(paths []string) ([]string, error) {
+	var names []string
 	for _, path := range paths {
 		file, err := os.Stat(path)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil, nil, err
+		if !file.Mode().IsRegular() {
+			return nil, errors.New(fmt.Sprintf("%v is not a regular file", path))
 		}
-		files = append(files, content)
 		names = append(names, file.Name())
 	}
-	return names, files, nil
+	return names, nil
 }
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+
+	pb "github.com/oteffahi/merkle-filebank/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// writes a bank share in JSON, so that it can be sent as text
+func WriteBankShare(path string, share *pb.BankShare) error {
+	data, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(share)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// reads a bank share in JSON or binary format
+func ReadBankShare(path string) (*pb.BankShare, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	share := &pb.BankShare{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = protojson.Unmarshal(data, share)
+	} else {
+		err = proto.Unmarshal(data, share)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	pb "github.com/oteffahi/merkle-filebank/proto"
+)
+
+// Files are sent over gRPC streams as consecutive FileMessage parts of the same sequence number, each holding at most
+// FileMessageSize bytes, so that neither side holds a whole file in memory and messages stay under the gRPC limit of
+// 4MB. An empty file is sent as a single empty part. A file ends with the first message that is not one of its parts.
+
+// size of the content of each part of a file
+const FileMessageSize = 1 << 20
+
+// FileMessageWriter sends the bytes written to it as the parts of file seq
+type FileMessageWriter struct {
+	send   func(*pb.FileMessage) error
+	seq    int32
+	buffer []byte
+	sent   bool
+}
+
+func NewFileMessageWriter(send func(*pb.FileMessage) error, seq int32) *FileMessageWriter {
+	return &FileMessageWriter{send: send, seq: seq, buffer: make([]byte, 0, FileMessageSize)}
+}
+
+func (w *FileMessageWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(w.buffer) == FileMessageSize {
+			if err := w.flush(); err != nil {
+				return n - len(p), err
+			}
+		}
+		size := min(len(p), FileMessageSize-len(w.buffer))
+		w.buffer = append(w.buffer, p[:size]...)
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// Close sends the last part of the file
+func (w *FileMessageWriter) Close() error {
+	if len(w.buffer) > 0 || !w.sent {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *FileMessageWriter) flush() error {
+	// content is copied, messages may be marshaled after the buffer is reused
+	if err := w.send(&pb.FileMessage{Seq: w.seq, Content: append([]byte(nil), w.buffer...)}); err != nil {
+		return err
+	}
+	w.buffer = w.buffer[:0]
+	w.sent = true
+	return nil
+}
+
+// FileMessageReader reads files sent as parts by a FileMessageWriter. recv returns io.EOF when the next message is not
+// a part of a file, or when the stream is closed
+type FileMessageReader struct {
+	recv func() (*pb.FileMessage, error)
+	seq  int32
+	part []byte
+	// first part of the next file, received at the end of the current one
+	next    *pb.FileMessage
+	reading bool
+	ended   bool
+}
+
+func NewFileMessageReader(recv func() (*pb.FileMessage, error)) *FileMessageReader {
+	return &FileMessageReader{recv: recv}
+}
+
+// Next starts reading file seq, which must be the next file of the stream. The rest of the current file is skipped
+func (r *FileMessageReader) Next(seq int32) error {
+	if r.reading {
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return err
+		}
+	}
+	part := r.next
+	r.next = nil
+	if part == nil {
+		if r.ended {
+			return errors.New(fmt.Sprintf("Missing file %v", seq))
+		}
+		var err error
+		part, err = r.recv()
+		if err == io.EOF {
+			r.ended = true
+			return errors.New(fmt.Sprintf("Missing file %v", seq))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if part.Seq != seq {
+		return errors.New("Invalid file order")
+	}
+	r.seq = seq
+	r.part = part.Content
+	r.reading = true
+	return nil
+}
+
+// Read reads the current file, io.EOF is returned at its end
+func (r *FileMessageReader) Read(p []byte) (int, error) {
+	for len(r.part) == 0 {
+		if !r.reading {
+			return 0, io.EOF
+		}
+		part, err := r.recv()
+		if err == io.EOF {
+			r.ended = true
+			r.reading = false
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		if part.Seq != r.seq {
+			r.next = part
+			r.reading = false
+			return 0, io.EOF
+		}
+		r.part = part.Content
+	}
+	n := copy(p, r.part)
+	r.part = r.part[n:]
+	return n, nil
+}
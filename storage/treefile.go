@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	pb "github.com/oteffahi/merkle-filebank/proto"
+)
+
+// Tree files hold the nodes of the merkle tree of a server bank, so that the server can map them in memory
+// and only read the nodes needed by a proof. A tree file is a fixed size header followed by the nodes, 32 bytes each.
+//
+// Header, integers in big endian:
+//
+//	0   magic "FBTREE\0\0"
+//	8   format version, uint16
+//	10  tree mode, tree version and hash algorithm, one byte each
+//	13  reserved, zero
+//	16  chunk size, uint32
+//	20  number of nodes, uint64
+//	28  CRC-32 of the header, with this field set to zero
+//	32  SHA-256 of the nodes
+//
+// The header checksum is verified every time the file is opened. The checksum of the nodes requires reading
+// the whole file, it is verified the first time the server opens the file, and then only when the file changes.
+// VerifyChecksum verifies it again when the whole tree is used anyway.
+
+const (
+	treeFileMagic         = "FBTREE\x00\x00"
+	TreeFileFormatVersion = 1
+	treeFileHeaderSize    = 64
+)
+
+// tree files whose nodes were verified since the server started, keyed by treeFileState
+var verifiedTreeFiles sync.Map
+
+type treeFileState struct {
+	path     string
+	size     int64
+	modTime  time.Time
+	checksum [32]byte
+}
+
+type TreeFile struct {
+	TreeMode      pb.TreeMode
+	TreeVersion   pb.TreeVersion
+	HashAlgorithm pb.HashAlgorithm
+	ChunkSize     int32
+	// nodes are backed by the mapped file, and must not be used after Close
+	Nodes    [][32]byte
+	checksum [32]byte
+	data     []byte
+}
+
+func treeFilePath(bankhome string, pubKeyHashB58 string, nbfiles int32) string {
+	// tree files are versioned by the number of files, so that the descriptor of a bank always references a complete tree
+	return fmt.Sprintf("%s/server/%s/tree.%d", bankhome, pubKeyHashB58, nbfiles)
+}
+
+// chunk tree files hold the chunk tree of a file of a chunked bank, so that ranges of the file are proven without
+// reading it whole. They have the header of tree files, with the tree mode and chunk size of the bank
+func chunkTreeFilePath(bankhome string, pubKeyHashB58 string, fileNum int) string {
+	return fmt.Sprintf("%s/server/%s/%d.chunks", bankhome, pubKeyHashB58, fileNum)
+}
+
+func Server_WriteTreeFile(bankhome string, pubKeyHashB58 string, descriptor *pb.ServerBankDescriptor, nodes [][32]byte) error {
+	return writeTreeFile(treeFilePath(bankhome, pubKeyHashB58, descriptor.Nbfiles), descriptor, nodes)
+}
+
+func Server_WriteChunkTreeFile(bankhome string, pubKeyHashB58 string, descriptor *pb.ServerBankDescriptor, fileNum int, nodes [][32]byte) error {
+	return writeTreeFile(chunkTreeFilePath(bankhome, pubKeyHashB58, fileNum), descriptor, nodes)
+}
+
+func writeTreeFile(path string, descriptor *pb.ServerBankDescriptor, nodes [][32]byte) error {
+	data := make([]byte, treeFileHeaderSize, treeFileHeaderSize+32*len(nodes))
+	for _, node := range nodes {
+		data = append(data, node[:]...)
+	}
+	copy(data, treeFileMagic)
+	binary.BigEndian.PutUint16(data[8:10], TreeFileFormatVersion)
+	data[10] = byte(descriptor.TreeMode)
+	data[11] = byte(descriptor.TreeVersion)
+	data[12] = byte(descriptor.HashAlgorithm)
+	binary.BigEndian.PutUint32(data[16:20], uint32(descriptor.ChunkSize))
+	binary.BigEndian.PutUint64(data[20:28], uint64(len(nodes)))
+	checksum := sha256.Sum256(data[treeFileHeaderSize:])
+	copy(data[32:64], checksum[:])
+	binary.BigEndian.PutUint32(data[28:32], crc32.ChecksumIEEE(data[:treeFileHeaderSize]))
+
+	return replaceFile(path, data, 0400)
+}
+
+// maps the tree file referenced by a bank descriptor, the caller must close it
+func Server_OpenTreeFile(bankhome string, pubKeyHashB58 string, descriptor *pb.ServerBankDescriptor) (*TreeFile, error) {
+	return openTreeFile(treeFilePath(bankhome, pubKeyHashB58, descriptor.Nbfiles), descriptor)
+}
+
+// maps the chunk tree file of a file of a bank, the caller must close it
+func Server_OpenChunkTreeFile(bankhome string, pubKeyHashB58 string, descriptor *pb.ServerBankDescriptor, fileNum int) (*TreeFile, error) {
+	return openTreeFile(chunkTreeFilePath(bankhome, pubKeyHashB58, fileNum), descriptor)
+}
+
+func openTreeFile(path string, descriptor *pb.ServerBankDescriptor) (*TreeFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < treeFileHeaderSize {
+		return nil, errors.New("Tree file is truncated")
+	}
+	data, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	treeFile, err := parseTreeFile(data)
+	if err != nil {
+		unmapFile(data)
+		return nil, err
+	}
+	// tree file must match the bank
+	if treeFile.TreeMode != descriptor.TreeMode || treeFile.TreeVersion != descriptor.TreeVersion ||
+		treeFile.HashAlgorithm != descriptor.HashAlgorithm || treeFile.ChunkSize != descriptor.ChunkSize {
+		unmapFile(data)
+		return nil, errors.New("Tree file does not match bank descriptor")
+	}
+	// proofs are served from nodes that were verified at least once, tree files are never modified in place
+	state := treeFileState{path: path, size: info.Size(), modTime: info.ModTime(), checksum: treeFile.checksum}
+	if _, verified := verifiedTreeFiles.Load(state); !verified {
+		if err := treeFile.VerifyChecksum(); err != nil {
+			unmapFile(data)
+			return nil, err
+		}
+		verifiedTreeFiles.Store(state, struct{}{})
+	}
+	return treeFile, nil
+}
+
+func parseTreeFile(data []byte) (*TreeFile, error) {
+	header := make([]byte, treeFileHeaderSize)
+	copy(header, data)
+	if string(header[:8]) != treeFileMagic {
+		return nil, errors.New("Not a tree file")
+	}
+	headerCRC := binary.BigEndian.Uint32(header[28:32])
+	binary.BigEndian.PutUint32(header[28:32], 0)
+	if crc32.ChecksumIEEE(header) != headerCRC {
+		return nil, errors.New("Invalid tree file header checksum")
+	}
+	if formatVersion := binary.BigEndian.Uint16(header[8:10]); formatVersion != TreeFileFormatVersion {
+		return nil, errors.New(fmt.Sprintf("Unsupported tree file format version %v", formatVersion))
+	}
+	nbNodes := binary.BigEndian.Uint64(header[20:28])
+	if uint64(len(data)-treeFileHeaderSize) != 32*nbNodes || nbNodes == 0 {
+		return nil, errors.New(fmt.Sprintf("Tree file has %v bytes of nodes, header announces %v nodes", len(data)-treeFileHeaderSize, nbNodes))
+	}
+	return &TreeFile{
+		TreeMode:      pb.TreeMode(header[10]),
+		TreeVersion:   pb.TreeVersion(header[11]),
+		HashAlgorithm: pb.HashAlgorithm(header[12]),
+		ChunkSize:     int32(binary.BigEndian.Uint32(header[16:20])),
+		// view the mapped nodes as hashes without copying them, [32]byte has no alignment constraint
+		Nodes:    unsafe.Slice((*[32]byte)(unsafe.Pointer(&data[treeFileHeaderSize])), nbNodes),
+		checksum: [32]byte(header[32:64]),
+		data:     data,
+	}, nil
+}
+
+// reads all the nodes to verify their checksum
+func (t *TreeFile) VerifyChecksum() error {
+	if sha256.Sum256(t.data[treeFileHeaderSize:]) != t.checksum {
+		return errors.New("Invalid tree file checksum")
+	}
+	return nil
+}
+
+func (t *TreeFile) Close() error {
+	t.Nodes = nil
+	return unmapFile(t.data)
+}
+
+// removes the tree file of a previous version of a bank
+func Server_RemoveTreeFile(bankhome string, pubKeyHashB58 string, nbfiles int32) error {
+	path := treeFilePath(bankhome, pubKeyHashB58, nbfiles)
+	verifiedTreeFiles.Range(func(key, _ any) bool {
+		if key.(treeFileState).path == path {
+			verifiedTreeFiles.Delete(key)
+		}
+		return true
+	})
+	return os.Remove(path)
+}
+
+// removes the tree files of the versions of a bank older than the one of nbfiles files. The tree file of that version
+// is kept until the next update, so that downloads that read the previous descriptor can still open it
+func Server_RemoveTreeFilesBefore(bankhome string, pubKeyHashB58 string, nbfiles int32) error {
+	entries, err := os.ReadDir(bankhome + "/server/" + pubKeyHashB58)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		version, isTreeFile := strings.CutPrefix(entry.Name(), "tree.")
+		if !isTreeFile {
+			continue
+		}
+		// temporary files of interrupted writes are not versions
+		versionNbfiles, err := strconv.ParseInt(version, 10, 32)
+		if err != nil || int32(versionNbfiles) >= nbfiles {
+			continue
+		}
+		if err := Server_RemoveTreeFile(bankhome, pubKeyHashB58, int32(versionNbfiles)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"os"
@@ -27,38 +28,82 @@ func Server_WriteServerKey(bankhome string, key []byte) error {
 }
 
 func Server_WriteBankDescriptor(bankhome string, descriptor *pb.ServerBankDescriptor) error {
-	pubKey := descriptor.PubKey
-	keyHash := cr.HashOnce(pubKey)
+	keyHash := cr.HashOnce(descriptor.PubKey)
 	dirName := cr.Base58Encode(keyHash[:])
+	if err := Server_CreateBankDirectory(bankhome, dirName); err != nil {
+		return err
+	}
+	return Server_CommitBankDescriptor(bankhome, descriptor)
+}
 
-	if _, err := os.Stat(bankhome + "/server/" + dirName); !os.IsNotExist(err) {
+// creates the directory of a new bank, whose files can then be written before its descriptor
+func Server_CreateBankDirectory(bankhome string, pubKeyHashB58 string) error {
+	if _, err := os.Stat(bankhome + "/server/" + pubKeyHashB58); !os.IsNotExist(err) {
 		return errors.New("Client key already has bank")
 	}
+	return os.Mkdir(bankhome+"/server/"+pubKeyHashB58, os.ModeDir+0755)
+}
 
+// writes the descriptor of a bank created with Server_CreateBankDirectory, once all its files are written
+func Server_CommitBankDescriptor(bankhome string, descriptor *pb.ServerBankDescriptor) error {
+	keyHash := cr.HashOnce(descriptor.PubKey)
+	dirName := cr.Base58Encode(keyHash[:])
 	data, err := proto.Marshal(descriptor)
 	if err != nil {
 		return err
 	}
+	return replaceFile(bankhome+"/server/"+dirName+"/bank.desc", data, 0400)
+}
 
-	if err := os.Mkdir(bankhome+"/server/"+dirName, os.ModeDir+0755); err != nil {
+// Server_CreateStagedFile creates a file in the directory of a bank, which receives a file before it is numbered by
+// Server_CommitStagedFile. Staged files have unique names, so that concurrent requests do not write the same file
+func Server_CreateStagedFile(bankhome string, pubKeyHashB58 string) (*os.File, error) {
+	return os.CreateTemp(bankhome+"/server/"+pubKeyHashB58, "staged-*")
+}
+
+// Server_CommitStagedFile moves a staged file to file fileNum of a bank, which can be another bank than the one it was
+// staged in
+func Server_CommitStagedFile(bankhome string, stagedPath string, pubKeyHashB58 string, fileNum int) error {
+	if err := os.Chmod(stagedPath, 0444); err != nil {
 		return err
 	}
-	if err := os.WriteFile(bankhome+"/server/"+dirName+"/bank.desc", data, 0400); err != nil {
+	return os.Rename(stagedPath, fmt.Sprintf("%s/server/%s/%d", bankhome, pubKeyHashB58, fileNum))
+}
+
+// removes a staged file that was not committed
+func Server_RemoveStagedFile(stagedPath string) error {
+	if err := os.Remove(stagedPath); err != nil && !os.IsNotExist(err) {
 		return err
 	}
 	return nil
 }
 
-func Server_WriteFileToBank(bankhome string, clientPubKey []byte, file []byte, fileNum int) error {
-	keyHash := cr.HashOnce(clientPubKey)
-	dirName := cr.Base58Encode(keyHash[:])
-
-	if err := os.WriteFile(fmt.Sprintf("%s/server/%s/%d", bankhome, dirName, fileNum), file, 0444); err != nil {
-		return err
+// removes the files of a bank numbered from firstSeq to lastSeq with their chunk trees, that a failed operation wrote
+func Server_RemoveFilesFromBank(bankhome string, pubKeyHashB58 string, firstSeq, lastSeq int) error {
+	for seq := firstSeq; seq <= lastSeq; seq++ {
+		if err := os.Remove(fmt.Sprintf("%s/server/%s/%d", bankhome, pubKeyHashB58, seq)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.Remove(chunkTreeFilePath(bankhome, pubKeyHashB58, seq)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
 	return nil
 }
 
+// removes a bank with all its files
+func Server_RemoveBank(bankhome string, pubKeyHashB58 string) error {
+	if pubKeyHashB58 == "" {
+		return errors.New("Invalid bank address")
+	}
+	return os.RemoveAll(bankhome + "/server/" + pubKeyHashB58)
+}
+
+// writes the encrypted manifest of a bank, replacing the previous one
+func Server_WriteBankManifest(bankhome string, pubKeyHashB58 string, manifest []byte) error {
+	return replaceFile(bankhome+"/server/"+pubKeyHashB58+"/manifest", manifest, 0400)
+}
+
 func Client_WriteBankDescriptor(bankhome string, descriptor *pb.ClientBankDescriptor, serverName string, bankName string) error {
 	serverPath := fmt.Sprintf("%s/client/srv_%s", bankhome, serverName)
 	bankPath := fmt.Sprintf("%s/bnk_%s.desc", serverPath, bankName)
@@ -81,6 +126,18 @@ func Client_WriteBankDescriptor(bankhome string, descriptor *pb.ClientBankDescri
 	return nil
 }
 
+// writes the encrypted identity key of the user, used to receive bank shares, and its public key given to other users
+func Client_WriteIdentityKey(bankhome string, key []byte, pubKey string) error {
+	keyPath := bankhome + "/client/identity.key"
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		return errors.New("Identity key already exists")
+	}
+	if err := os.WriteFile(keyPath, key, 0400); err != nil {
+		return err
+	}
+	return replaceFile(bankhome+"/client/identity.pub", []byte(pubKey+"\n"), 0444)
+}
+
 func Client_WriteServerDescriptor(bankhome string, descriptor *pb.ServerDescriptor, serverName string) error {
 	serverPath := fmt.Sprintf("%s/client/srv_%s", bankhome, serverName)
 	// serverPath must not exist
@@ -111,3 +168,130 @@ func Client_WriteDownloadedFile(bankhome string, filename string, file []byte) e
 	fmt.Println("File written to " + filepath)
 	return nil
 }
+
+// DownloadedFile is a file being downloaded. It is written next to its path and renamed by Commit once complete and
+// verified, so that a failed download leaves no partial file and does not replace a previous one
+type DownloadedFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	path   string
+}
+
+// Client_CreateDownloadedFile starts writing a downloaded file, which is removed unless Commit is called
+func Client_CreateDownloadedFile(bankhome string, filename string) (*DownloadedFile, error) {
+	filepath := bankhome + "/downloads/" + filename
+	// files with the same name can be downloaded together
+	file, err := os.CreateTemp(bankhome+"/downloads", filename+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadedFile{file: file, writer: bufio.NewWriter(file), path: filepath}, nil
+}
+
+func (f *DownloadedFile) Write(p []byte) (int, error) {
+	return f.writer.Write(p)
+}
+
+// Commit moves the downloaded file to its path
+func (f *DownloadedFile) Commit() error {
+	err := f.writer.Flush()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	// temporary files are created private, downloads keep the permissions they had when written at once
+	if err == nil {
+		err = os.Chmod(f.file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(f.file.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(f.file.Name())
+		return err
+	}
+	fmt.Println("File written to " + f.path)
+	return nil
+}
+
+// Remove discards a downloaded file that was not committed
+func (f *DownloadedFile) Remove() {
+	f.file.Close()
+	if err := os.Remove(f.file.Name()); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Could not remove %v: %v\n", f.file.Name(), err)
+	}
+}
+
+// Client_CreateStagedFile creates a file holding a ciphertext before it is sent, such as the files of a bank encrypted
+// again during its rotation. Staged files have unique names and are removed with Client_RemoveStagedFile once sent
+func Client_CreateStagedFile(bankhome string) (*os.File, error) {
+	return os.CreateTemp(bankhome+"/client", "staged-*")
+}
+
+// removes a staged file once it was sent or could not be
+func Client_RemoveStagedFile(stagedPath string) {
+	if err := os.Remove(stagedPath); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Could not remove %v: %v\n", stagedPath, err)
+	}
+}
+
+func Server_UpdateBankDescriptor(bankhome string, descriptor *pb.ServerBankDescriptor) error {
+	pubKey := descriptor.PubKey
+	keyHash := cr.HashOnce(pubKey)
+	dirName := cr.Base58Encode(keyHash[:])
+	bankPath := bankhome + "/server/" + dirName + "/bank.desc"
+
+	// bank must exist
+	if _, err := os.Stat(bankPath); os.IsNotExist(err) {
+		return errors.New("Client key has no bank")
+	}
+
+	data, err := proto.Marshal(descriptor)
+	if err != nil {
+		return err
+	}
+	return replaceFile(bankPath, data, 0400)
+}
+
+func Client_UpdateBankDescriptor(bankhome string, descriptor *pb.ClientBankDescriptor, serverName string, bankName string) error {
+	bankPath := fmt.Sprintf("%s/client/srv_%s/bnk_%s.desc", bankhome, serverName, bankName)
+	// bank must exist
+	if _, err := os.Stat(bankPath); os.IsNotExist(err) {
+		return errors.New(fmt.Sprintf("Bank %s:%s does not exist", serverName, bankName))
+	}
+
+	data, err := proto.Marshal(descriptor)
+	if err != nil {
+		return err
+	}
+	return replaceFile(bankPath, data, 0400)
+}
+
+// writes the descriptor that a bank will have once an operation succeeds on the server, next to its current descriptor,
+// so that it is not lost if the client stops before the operation is acknowledged. Returns the path of the file
+func Client_WriteStagedBankDescriptor(bankhome string, descriptor *pb.ClientBankDescriptor, serverName string, bankName string) (string, error) {
+	stagedPath := fmt.Sprintf("%s/client/srv_%s/bnk_%s.desc.staged", bankhome, serverName, bankName)
+	data, err := proto.Marshal(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return stagedPath, replaceFile(stagedPath, data, 0400)
+}
+
+// replaces the descriptor of a bank with its staged descriptor
+func Client_CommitStagedBankDescriptor(bankhome string, serverName string, bankName string) error {
+	bankPath := fmt.Sprintf("%s/client/srv_%s/bnk_%s.desc", bankhome, serverName, bankName)
+	return os.Rename(bankPath+".staged", bankPath)
+}
+
+// writes data to a temporary file that is renamed over path, so that path is never partially written
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	// remove leftover of an interrupted update
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}